@@ -38,8 +38,41 @@ type AlertChannelSpec struct {
 
 	// Email holds information about the Email alert configuration
 	Email checkly.AlertChannelEmail `json:"email,omitempty"`
+
+	// Slack holds information about the Slack alert configuration
+	Slack *AlertChannelSlack `json:"slack,omitempty"`
+
+	// PagerDuty holds information about the PagerDuty alert configuration
+	PagerDuty *AlertChannelPagerDuty `json:"pagerduty,omitempty"`
+
+	// Webhook holds information about the generic webhook alert configuration
+	Webhook *AlertChannelWebhook `json:"webhook,omitempty"`
+
+	// SMS holds information about the SMS alert configuration
+	SMS *AlertChannelSMS `json:"sms,omitempty"`
+
+	// MSTeams holds information about the Microsoft Teams alert configuration
+	MSTeams *AlertChannelMSTeams `json:"msteams,omitempty"`
+
+	// Account is the name of the ChecklyAccount the alert channel is created in, empty uses the default account of the operator,
+	// the field can't be changed after creation
+	Account string `json:"account,omitempty"`
+
+	// DependentsPolicy determines what happens to the groups subscribed to the alert channel when it's deleted: Block keeps the
+	// alert channel until no group is subscribed anymore, Unsubscribe removes the alert channel from the groups, default Block
+	// +kubebuilder:validation:Enum=Block;Unsubscribe
+	DependentsPolicy string `json:"dependentsPolicy,omitempty"`
+
+	// DeletionPolicy determines what happens to the checklyhq.com alert channel when the resource is deleted: Delete removes it,
+	// Orphan keeps it, empty uses the default policy of the operator. Retain isn't supported as alert channels can't be tagged in
+	// checklyhq.com, a Retain default policy keeps them like Orphan.
+	// +kubebuilder:validation:Enum=Delete;Orphan
+	DeletionPolicy string `json:"deletionPolicy,omitempty"`
 }
 
+// DependentsPolicyUnsubscribe removes an alert channel from the groups subscribed to it when it's deleted
+const DependentsPolicyUnsubscribe = "Unsubscribe"
+
 type AlertChannelOpsGenie struct {
 	// APISecret determines where the secret ref is to pull the OpsGenie API key from
 	APISecret corev1.ObjectReference `json:"apisecret"`
@@ -51,15 +84,88 @@ type AlertChannelOpsGenie struct {
 	Priority string `json:"priority,omitempty"`
 }
 
+type AlertChannelSlack struct {
+	// URLSecret determines where the secret ref is to pull the Slack incoming webhook URL from
+	URLSecret corev1.ObjectReference `json:"urlsecret"`
+
+	// Channel overrides the channel configured for the Slack incoming webhook, ex. #alerts
+	Channel string `json:"channel,omitempty"`
+}
+
+type AlertChannelPagerDuty struct {
+	// ServiceKeySecret determines where the secret ref is to pull the PagerDuty integration key from
+	ServiceKeySecret corev1.ObjectReference `json:"servicekeysecret"`
+
+	// Account is the name of the PagerDuty account
+	Account string `json:"account,omitempty"`
+
+	// ServiceName is the name of the PagerDuty service
+	ServiceName string `json:"servicename,omitempty"`
+}
+
+type AlertChannelWebhook struct {
+	// URLSecret determines where the secret ref is to pull the webhook URL from
+	URLSecret corev1.ObjectReference `json:"urlsecret"`
+
+	// Method is the HTTP method used to call the webhook
+	// +kubebuilder:validation:Enum=GET;POST;PUT;PATCH;HEAD;DELETE
+	Method string `json:"method,omitempty"`
+
+	// Template is the request body, see https://www.checklyhq.com/docs/alerting-and-retries/webhooks/ for the available variables
+	Template string `json:"template,omitempty"`
+
+	// Headers are added to the webhook request
+	Headers []AlertChannelKeyValue `json:"headers,omitempty"`
+
+	// QueryParameters are added to the webhook URL
+	QueryParameters []AlertChannelKeyValue `json:"queryparameters,omitempty"`
+
+	// WebhookSecret determines where the secret ref is to pull the value checklyhq.com signs the webhook requests with
+	WebhookSecret *corev1.ObjectReference `json:"webhooksecret,omitempty"`
+}
+
+// AlertChannelKeyValue is a key value pair of a webhook request, the value is either set inline or read from a secret
+type AlertChannelKeyValue struct {
+	// Key is the name of the header or query parameter
+	Key string `json:"key"`
+
+	// Value is the inline value
+	Value string `json:"value,omitempty"`
+
+	// ValueFrom points to a key in a secret which holds the value, takes precedence over Value
+	ValueFrom *corev1.ObjectReference `json:"valuefrom,omitempty"`
+}
+
+type AlertChannelSMS struct {
+	// Number is the phone number the alerts are sent to in international format, ex. +4915112345678
+	// +kubebuilder:validation:Pattern=`^\+[1-9][0-9]{6,14}$`
+	Number string `json:"number"`
+}
+
+type AlertChannelMSTeams struct {
+	// URLSecret determines where the secret ref is to pull the Microsoft Teams incoming webhook URL from
+	URLSecret corev1.ObjectReference `json:"urlsecret"`
+}
+
 // AlertChannelStatus defines the observed state of AlertChannel
 type AlertChannelStatus struct {
 	// INSERT ADDITIONAL STATUS FIELD - define observed state of cluster
 	// Important: Run "make" to regenerate code after modifying this file
 	ID int64 `json:"id"`
+
+	// SecretsHash holds the hash of the configuration read from the referenced secrets when it was last pushed to checklyhq.com,
+	// it changes when one of the secrets is rotated
+	SecretsHash string `json:"secretsHash,omitempty"`
+
+	// SyncStatus holds the conditions and the last sync information of the resource
+	SyncStatus `json:",inline"`
 }
 
 //+kubebuilder:object:root=true
 //+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.conditions[?(@.type==\"Ready\")].status"
+//+kubebuilder:printcolumn:name="Reason",type="string",JSONPath=".status.conditions[?(@.type==\"Ready\")].reason",priority=1
+//+kubebuilder:printcolumn:name="Last Sync",type="date",JSONPath=".status.lastSyncTime"
 //+kubebuilder:resource:scope=Cluster
 
 // AlertChannel is the Schema for the alertchannels API
@@ -0,0 +1,51 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+// AlertSettings determines when and how often alerts are sent, see https://www.checklyhq.com/docs/alerting/alert-settings/
+type AlertSettings struct {
+	// UseGlobalAlertSettings determines if the account level alert settings are used instead of the ones below, default false
+	UseGlobalAlertSettings bool `json:"useGlobalAlertSettings,omitempty"`
+
+	// EscalationType determines if alerts are escalated based on failed runs or on the time a check has been failing, default RUN_BASED
+	// +kubebuilder:validation:Enum=RUN_BASED;TIME_BASED
+	EscalationType string `json:"escalationType,omitempty"`
+
+	// FailedRunThreshold determines after how many failed runs an alert is sent with RUN_BASED escalation, default 5
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=5
+	FailedRunThreshold int `json:"failedRunThreshold,omitempty"`
+
+	// MinutesFailingThreshold determines after how many minutes of failing an alert is sent with TIME_BASED escalation, default 5
+	// +kubebuilder:validation:Enum=5;10;15;30
+	MinutesFailingThreshold int `json:"minutesFailingThreshold,omitempty"`
+
+	// ReminderAmount determines how many reminders are sent after the first alert, 100000 means unlimited, default 0
+	// +kubebuilder:validation:Enum=0;1;2;3;4;5;100000
+	ReminderAmount int `json:"reminderAmount,omitempty"`
+
+	// ReminderInterval determines the minutes between reminders, default 5
+	// +kubebuilder:validation:Enum=5;10;15;30
+	ReminderInterval int `json:"reminderInterval,omitempty"`
+
+	// SSLCertificates determines if an alert is sent for expiring SSL certificates, default false
+	SSLCertificates bool `json:"sslCertificates,omitempty"`
+
+	// SSLCertificatesThreshold determines how many days before the SSL certificate expiry the alert is sent, default 3
+	// +kubebuilder:validation:Enum=3;7;14;30
+	SSLCertificatesThreshold int `json:"sslCertificatesThreshold,omitempty"`
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package v1alpha1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -24,6 +25,7 @@ import (
 // NOTE: json tags are required.  Any new fields you add must have json tags for the fields to be serialized.
 
 // ApiCheckSpec defines the desired state of ApiCheck
+// +kubebuilder:validation:XValidation:rule="has(self.success) || (has(self.assertions) && size(self.assertions) > 0)",message="either success or assertions has to be set"
 type ApiCheckSpec struct {
 	// INSERT ADDITIONAL SPEC FIELDS - desired state of cluster
 	// Important: Run "make" to regenerate code after modifying this file
@@ -37,14 +39,89 @@ type ApiCheckSpec struct {
 	// Endpoint determines which URL to monitor, ex. https://foo.bar/baz
 	Endpoint string `json:"endpoint"`
 
-	// Success determines the returned success code, ex. 200
-	Success string `json:"success"`
+	// Success determines the returned success code, ex. 200, can be omitted if Assertions are set
+	// +kubebuilder:validation:Pattern=`^[0-9]{3}$`
+	Success string `json:"success,omitempty"`
 
 	// MaxResponseTime determines what the maximum number of miliseconds can pass before the check fails, default 15000
 	MaxResponseTime int `json:"maxresponsetime,omitempty"`
 
-	// Group determines in which group does the check belong to
+	// Group determines in which group does the check belong to, a CheckGroup in the namespace of the check or a cluster scoped Group
 	Group string `json:"group"`
+
+	// GroupKind is the kind of the group, Group for a cluster scoped Group or CheckGroup for a CheckGroup in the namespace of the check.
+	// Empty looks up both, the check isn't synced while a CheckGroup and a Group share the name until GroupKind is set.
+	// +kubebuilder:validation:Enum=Group;CheckGroup
+	GroupKind string `json:"groupKind,omitempty"`
+
+	// Locations determines the locations where the check is run from, use AWS Region codes, ex. eu-west-1 for Ireland, empty uses the locations of the group
+	Locations []string `json:"locations,omitempty"`
+
+	// Account is the name of the ChecklyAccount the check is created in, it has to match the account of the group, empty uses the account of the group
+	Account string `json:"account,omitempty"`
+
+	// DeletionPolicy determines what happens to the checklyhq.com check when the resource is deleted: Delete removes it,
+	// Retain keeps it tagged for a later adoption and Orphan keeps it unchanged, empty uses the default policy of the operator
+	// +kubebuilder:validation:Enum=Delete;Retain;Orphan
+	DeletionPolicy string `json:"deletionPolicy,omitempty"`
+
+	// Method determines the HTTP method used for the request, default GET
+	// +kubebuilder:validation:Enum=GET;POST;PUT;PATCH;DELETE;HEAD;OPTIONS
+	Method string `json:"method,omitempty"`
+
+	// Headers determines the HTTP headers sent with the request
+	Headers []ApiCheckKeyValue `json:"headers,omitempty"`
+
+	// QueryParameters determines the query parameters added to the endpoint
+	QueryParameters []ApiCheckKeyValue `json:"queryParameters,omitempty"`
+
+	// Body determines the body sent with the request
+	Body string `json:"body,omitempty"`
+
+	// BodyType determines the type of the body, default NONE
+	// +kubebuilder:validation:Enum=NONE;JSON;FORM;RAW;GRAPHQL
+	BodyType string `json:"bodyType,omitempty"`
+
+	// Assertions determines the assertions run against the response, added next to the Success assertion
+	// +kubebuilder:validation:MaxItems=50
+	Assertions []ApiCheckAssertion `json:"assertions,omitempty"`
+
+	// AlertSettings determines when and how often alerts are sent for the check
+	AlertSettings *AlertSettings `json:"alertSettings,omitempty"`
+}
+
+// ApiCheckAssertion defines a single assertion about the response of the check, see https://www.checklyhq.com/docs/api-checks/assertions/
+// +kubebuilder:validation:XValidation:rule="self.comparison in ['IS_EMPTY', 'NOT_EMPTY', 'IS_NULL', 'NOT_NULL'] || has(self.target)",message="target is required for this comparison"
+// +kubebuilder:validation:XValidation:rule="!(self.source in ['JSON_BODY', 'HEADERS']) || has(self.property)",message="property is required for JSON_BODY and HEADERS assertions"
+// +kubebuilder:validation:XValidation:rule="!(self.source in ['STATUS_CODE', 'RESPONSE_TIME']) || (self.comparison in ['EQUALS', 'NOT_EQUALS', 'GREATER_THAN', 'LESS_THAN'] && has(self.target) && self.target.matches('^[0-9]+$'))",message="STATUS_CODE and RESPONSE_TIME assertions need a numeric target and one of EQUALS, NOT_EQUALS, GREATER_THAN or LESS_THAN"
+type ApiCheckAssertion struct {
+	// Source determines which part of the response is asserted
+	// +kubebuilder:validation:Enum=STATUS_CODE;JSON_BODY;HEADERS;TEXT_BODY;RESPONSE_TIME
+	Source string `json:"source"`
+
+	// Property holds the JSON path for JSON_BODY assertions, ex. $.status, or the header name for HEADERS assertions
+	// +kubebuilder:validation:MaxLength=1024
+	Property string `json:"property,omitempty"`
+
+	// Comparison determines how the source is compared to the target
+	// +kubebuilder:validation:Enum=EQUALS;NOT_EQUALS;HAS_KEY;NOT_HAS_KEY;HAS_VALUE;NOT_HAS_VALUE;IS_EMPTY;NOT_EMPTY;GREATER_THAN;LESS_THAN;CONTAINS;NOT_CONTAINS;IS_NULL;NOT_NULL
+	Comparison string `json:"comparison"`
+
+	// Target holds the expected value, ex. 200 or ok
+	// +kubebuilder:validation:MaxLength=1024
+	Target string `json:"target,omitempty"`
+}
+
+// ApiCheckKeyValue holds a key and value pair, the value can either be set directly or read from a secret
+type ApiCheckKeyValue struct {
+	// Key holds the name of the header or query parameter
+	Key string `json:"key"`
+
+	// Value holds the value of the header or query parameter
+	Value string `json:"value,omitempty"`
+
+	// ValueFrom points to a key in a secret in the same namespace which holds the value, takes precedence over Value
+	ValueFrom *corev1.SecretKeySelector `json:"valueFrom,omitempty"`
 }
 
 // ApiCheckStatus defines the observed state of ApiCheck
@@ -57,6 +134,16 @@ type ApiCheckStatus struct {
 
 	// GroupID holds the ID of the group where the check belongs to
 	GroupID int64 `json:"groupId"`
+
+	// Account holds the name of the ChecklyAccount the check was created in, empty for the default account of the operator
+	Account string `json:"account,omitempty"`
+
+	// SecretsHash holds the hash of the header and query parameter values read from secrets when they were last pushed to
+	// checklyhq.com, it changes when one of the secrets is rotated
+	SecretsHash string `json:"secretsHash,omitempty"`
+
+	// SyncStatus holds the conditions and the last sync information of the resource
+	SyncStatus `json:",inline"`
 }
 
 //+kubebuilder:object:root=true
@@ -64,6 +151,9 @@ type ApiCheckStatus struct {
 //+kubebuilder:printcolumn:name="Status code",type="string",JSONPath=".spec.success",description="Expected status code"
 //+kubebuilder:printcolumn:name="Muted",type="boolean",JSONPath=".spec.muted"
 //+kubebuilder:printcolumn:name="Group",type="string",JSONPath=".spec.group"
+//+kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.conditions[?(@.type==\"Ready\")].status"
+//+kubebuilder:printcolumn:name="Reason",type="string",JSONPath=".status.conditions[?(@.type==\"Ready\")].reason",priority=1
+//+kubebuilder:printcolumn:name="Last Sync",type="date",JSONPath=".status.lastSyncTime"
 //+kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
 //+kubebuilder:subresource:status
 
@@ -0,0 +1,99 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// BrowserCheckSpec defines the desired state of BrowserCheck
+type BrowserCheckSpec struct {
+	// Frequency is used to determine the frequency of the checks in minutes, default 10
+	Frequency int `json:"frequency,omitempty"`
+
+	// Muted determines if the created alert is muted or not, default false
+	Muted bool `json:"muted,omitempty"`
+
+	// Script holds the Playwright script inline, ignored if ScriptConfigMap is set
+	Script string `json:"script,omitempty"`
+
+	// ScriptConfigMap points to a key in a ConfigMap in the same namespace which holds the Playwright script
+	ScriptConfigMap *corev1.ConfigMapKeySelector `json:"scriptConfigMap,omitempty"`
+
+	// Group determines in which group does the check belong to, a CheckGroup in the namespace of the check or a cluster scoped Group
+	Group string `json:"group"`
+
+	// GroupKind is the kind of the group, Group for a cluster scoped Group or CheckGroup for a CheckGroup in the namespace of the check.
+	// Empty looks up both, the check isn't synced while a CheckGroup and a Group share the name until GroupKind is set.
+	// +kubebuilder:validation:Enum=Group;CheckGroup
+	GroupKind string `json:"groupKind,omitempty"`
+
+	// Account is the name of the ChecklyAccount the check is created in, it has to match the account of the group, empty uses the account of the group
+	Account string `json:"account,omitempty"`
+
+	// DeletionPolicy determines what happens to the checklyhq.com check when the resource is deleted: Delete removes it,
+	// Retain keeps it tagged for a later adoption and Orphan keeps it unchanged, empty uses the default policy of the operator
+	// +kubebuilder:validation:Enum=Delete;Retain;Orphan
+	DeletionPolicy string `json:"deletionPolicy,omitempty"`
+}
+
+// BrowserCheckStatus defines the observed state of BrowserCheck
+type BrowserCheckStatus struct {
+	// ID holds the checklyhq.com internal ID of the check
+	ID string `json:"id"`
+
+	// GroupID holds the ID of the group where the check belongs to
+	GroupID int64 `json:"groupId"`
+
+	// Account holds the name of the ChecklyAccount the check was created in, empty for the default account of the operator
+	Account string `json:"account,omitempty"`
+
+	// SyncStatus holds the conditions and the last sync information of the resource
+	SyncStatus `json:",inline"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:printcolumn:name="Muted",type="boolean",JSONPath=".spec.muted"
+//+kubebuilder:printcolumn:name="Group",type="string",JSONPath=".spec.group"
+//+kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.conditions[?(@.type==\"Ready\")].status"
+//+kubebuilder:printcolumn:name="Reason",type="string",JSONPath=".status.conditions[?(@.type==\"Ready\")].reason",priority=1
+//+kubebuilder:printcolumn:name="Last Sync",type="date",JSONPath=".status.lastSyncTime"
+//+kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+//+kubebuilder:subresource:status
+
+// BrowserCheck is the Schema for the browserchecks API
+type BrowserCheck struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   BrowserCheckSpec   `json:"spec,omitempty"`
+	Status BrowserCheckStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// BrowserCheckList contains a list of BrowserCheck
+type BrowserCheckList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []BrowserCheck `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&BrowserCheck{}, &BrowserCheckList{})
+}
@@ -0,0 +1,57 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ChecklyAccountSpec defines the desired state of ChecklyAccount
+type ChecklyAccountSpec struct {
+	// AccountID is the ID of the checklyhq.com account
+	AccountID string `json:"accountID"`
+
+	// APIKeySecret determines where the secret ref is to pull the checklyhq.com API key from
+	APIKeySecret corev1.ObjectReference `json:"apikeysecret"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:printcolumn:name="Account ID",type="string",JSONPath=".spec.accountID"
+//+kubebuilder:resource:scope=Cluster
+
+// ChecklyAccount is the Schema for the checklyaccounts API, it holds the credentials of a checklyhq.com account
+// which Groups, AlertChannels and their namespaced variants can reference instead of the default account of the operator
+type ChecklyAccount struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec ChecklyAccountSpec `json:"spec,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// ChecklyAccountList contains a list of ChecklyAccount
+type ChecklyAccountList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ChecklyAccount `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ChecklyAccount{}, &ChecklyAccountList{})
+}
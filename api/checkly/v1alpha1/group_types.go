@@ -23,7 +23,26 @@ import (
 // EDIT THIS FILE!  THIS IS SCAFFOLDING FOR YOU TO OWN!
 // NOTE: json tags are required.  Any new fields you add must have json tags for the fields to be serialized.
 
+// Dependents policies of a Group or CheckGroup
+const (
+	// DependentsPolicyBlock keeps the resource until nothing references it anymore
+	DependentsPolicyBlock = "Block"
+	// DependentsPolicyDelete deletes the checks of the group together with the group
+	DependentsPolicyDelete = "Delete"
+	// DependentsPolicyReparent moves the checks of the group to the group set in ReparentTo
+	DependentsPolicyReparent = "Reparent"
+)
+
+// Kinds of the group a check references with its GroupKind
+const (
+	// GroupKindGroup references a cluster scoped Group
+	GroupKindGroup = "Group"
+	// GroupKindCheckGroup references a CheckGroup in the namespace of the check
+	GroupKindCheckGroup = "CheckGroup"
+)
+
 // GroupSpec defines the desired state of Group
+// +kubebuilder:validation:XValidation:rule="!has(self.dependentsPolicy) || self.dependentsPolicy != 'Reparent' || has(self.reparentTo)",message="reparentTo has to be set for the Reparent dependents policy"
 type GroupSpec struct {
 	// INSERT ADDITIONAL SPEC FIELDS - desired state of cluster
 	// Important: Run "make" to regenerate code after modifying this file
@@ -39,6 +58,30 @@ type GroupSpec struct {
 
 	// AlertChannels determines where to send alerts
 	AlertChannels []string `json:"alertchannel,omitempty"`
+
+	// AlertSettings determines when and how often alerts are sent for the checks in the group
+	AlertSettings *AlertSettings `json:"alertSettings,omitempty"`
+
+	// AllowedNamespaces restricts the namespaces whose checks can be added to the group, empty allows all namespaces.
+	// Only used by the cluster scoped Group, a CheckGroup only accepts checks from its own namespace
+	AllowedNamespaces []string `json:"allowedNamespaces,omitempty"`
+
+	// Account is the name of the ChecklyAccount the group is created in, empty uses the default account of the operator.
+	// The checks of the group and its alert channels have to belong to the same account, the field can't be changed after creation
+	Account string `json:"account,omitempty"`
+
+	// DependentsPolicy determines what happens to the checks of the group when the group is deleted: Block keeps the group
+	// until no check belongs to it anymore, Delete deletes the checks first and Reparent moves them to the ReparentTo group, default Block
+	// +kubebuilder:validation:Enum=Block;Delete;Reparent
+	DependentsPolicy string `json:"dependentsPolicy,omitempty"`
+
+	// ReparentTo is the name of the group the checks are moved to by the Reparent dependents policy, it's looked up like the group of a check
+	ReparentTo string `json:"reparentTo,omitempty"`
+
+	// DeletionPolicy determines what happens to the checklyhq.com group when the resource is deleted: Delete removes it,
+	// Retain keeps it tagged for a later adoption and Orphan keeps it unchanged, empty uses the default policy of the operator
+	// +kubebuilder:validation:Enum=Delete;Retain;Orphan
+	DeletionPolicy string `json:"deletionPolicy,omitempty"`
 }
 
 // GroupStatus defines the observed state of Group
@@ -48,10 +91,16 @@ type GroupStatus struct {
 
 	// ID holds the ID of the created checklyhq.com group
 	ID int64 `json:"ID"`
+
+	// SyncStatus holds the conditions and the last sync information of the resource
+	SyncStatus `json:",inline"`
 }
 
 //+kubebuilder:object:root=true
 //+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.conditions[?(@.type==\"Ready\")].status"
+//+kubebuilder:printcolumn:name="Reason",type="string",JSONPath=".status.conditions[?(@.type==\"Ready\")].reason",priority=1
+//+kubebuilder:printcolumn:name="Last Sync",type="date",JSONPath=".status.lastSyncTime"
 //+kubebuilder:resource:scope=Cluster
 
 // Group is the Schema for the groups API
@@ -0,0 +1,50 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.conditions[?(@.type==\"Ready\")].status"
+//+kubebuilder:printcolumn:name="Reason",type="string",JSONPath=".status.conditions[?(@.type==\"Ready\")].reason",priority=1
+//+kubebuilder:printcolumn:name="Last Sync",type="date",JSONPath=".status.lastSyncTime"
+
+// NamespacedAlertChannel is the Schema for the namespacedalertchannels API, it's the namespaced variant of AlertChannel,
+// secrets are only read from its own namespace and only CheckGroups in the same namespace can reference it
+type NamespacedAlertChannel struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   AlertChannelSpec   `json:"spec,omitempty"`
+	Status AlertChannelStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// NamespacedAlertChannelList contains a list of NamespacedAlertChannel
+type NamespacedAlertChannelList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []NamespacedAlertChannel `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&NamespacedAlertChannel{}, &NamespacedAlertChannelList{})
+}
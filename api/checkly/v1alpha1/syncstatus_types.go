@@ -0,0 +1,80 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Condition types set on the status of the checkly resources
+const (
+	// ConditionReady is true when the resource is in sync with checklyhq.com and all its dependencies are resolved
+	ConditionReady = "Ready"
+
+	// ConditionSynced is true when the last checklyhq.com API call for the resource succeeded
+	ConditionSynced = "Synced"
+
+	// ConditionDependenciesResolved is true when the referenced resources, ex. Group, AlertChannel or Secret, exist and are ready
+	ConditionDependenciesResolved = "DependenciesResolved"
+
+	// ConditionDrifted is true when the last resync found the resource changed or deleted in checklyhq.com
+	ConditionDrifted = "Drifted"
+)
+
+// Condition reasons set on the status of the checkly resources
+const (
+	ReasonSynced               = "Synced"
+	ReasonSyncFailed           = "SyncFailed"
+	ReasonRateLimited          = "RateLimited"
+	ReasonDependenciesResolved = "DependenciesResolved"
+	ReasonDependencyNotFound   = "DependencyNotFound"
+	ReasonDependencyNotReady   = "DependencyNotReady"
+	ReasonDependencyNotAllowed = "DependencyNotAllowed"
+	ReasonDependencyAmbiguous  = "DependencyAmbiguous"
+	ReasonNoDrift              = "NoDrift"
+	ReasonDriftCorrected       = "DriftCorrected"
+	ReasonRecreated            = "Recreated"
+	ReasonDeletionBlocked      = "DeletionBlocked"
+)
+
+// Deletion policies of the checkly resources, they determine what happens in checklyhq.com when the resource is deleted
+const (
+	// DeletionPolicyDelete deletes the checklyhq.com resource
+	DeletionPolicyDelete = "Delete"
+	// DeletionPolicyRetain keeps the checklyhq.com resource and tags checks and groups, so they can be adopted again
+	DeletionPolicyRetain = "Retain"
+	// DeletionPolicyOrphan keeps the checklyhq.com resource as it is
+	DeletionPolicyOrphan = "Orphan"
+)
+
+// SyncStatus holds the sync state shared by the status of all checkly resources
+type SyncStatus struct {
+	// ObservedGeneration is the generation of the resource the status was last set for
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// LastSyncTime is the last time the resource was successfully synced with checklyhq.com
+	LastSyncTime *metav1.Time `json:"lastSyncTime,omitempty"`
+
+	// LastError holds the error of the last failed checklyhq.com API call, cleared after a successful sync
+	LastError string `json:"lastError,omitempty"`
+
+	// Conditions holds the Ready, Synced, DependenciesResolved and Drifted conditions of the resource
+	// +listType=map
+	// +listMapKey=type
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
@@ -21,6 +21,8 @@ limitations under the License.
 package v1alpha1
 
 import (
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -29,8 +31,8 @@ func (in *AlertChannel) DeepCopyInto(out *AlertChannel) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
-	out.Status = in.Status
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AlertChannel.
@@ -51,6 +53,26 @@ func (in *AlertChannel) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AlertChannelKeyValue) DeepCopyInto(out *AlertChannelKeyValue) {
+	*out = *in
+	if in.ValueFrom != nil {
+		in, out := &in.ValueFrom, &out.ValueFrom
+		*out = new(v1.ObjectReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AlertChannelKeyValue.
+func (in *AlertChannelKeyValue) DeepCopy() *AlertChannelKeyValue {
+	if in == nil {
+		return nil
+	}
+	out := new(AlertChannelKeyValue)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AlertChannelList) DeepCopyInto(out *AlertChannelList) {
 	*out = *in
@@ -83,6 +105,22 @@ func (in *AlertChannelList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AlertChannelMSTeams) DeepCopyInto(out *AlertChannelMSTeams) {
+	*out = *in
+	out.URLSecret = in.URLSecret
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AlertChannelMSTeams.
+func (in *AlertChannelMSTeams) DeepCopy() *AlertChannelMSTeams {
+	if in == nil {
+		return nil
+	}
+	out := new(AlertChannelMSTeams)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AlertChannelOpsGenie) DeepCopyInto(out *AlertChannelOpsGenie) {
 	*out = *in
@@ -99,11 +137,83 @@ func (in *AlertChannelOpsGenie) DeepCopy() *AlertChannelOpsGenie {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AlertChannelPagerDuty) DeepCopyInto(out *AlertChannelPagerDuty) {
+	*out = *in
+	out.ServiceKeySecret = in.ServiceKeySecret
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AlertChannelPagerDuty.
+func (in *AlertChannelPagerDuty) DeepCopy() *AlertChannelPagerDuty {
+	if in == nil {
+		return nil
+	}
+	out := new(AlertChannelPagerDuty)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AlertChannelSMS) DeepCopyInto(out *AlertChannelSMS) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AlertChannelSMS.
+func (in *AlertChannelSMS) DeepCopy() *AlertChannelSMS {
+	if in == nil {
+		return nil
+	}
+	out := new(AlertChannelSMS)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AlertChannelSlack) DeepCopyInto(out *AlertChannelSlack) {
+	*out = *in
+	out.URLSecret = in.URLSecret
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AlertChannelSlack.
+func (in *AlertChannelSlack) DeepCopy() *AlertChannelSlack {
+	if in == nil {
+		return nil
+	}
+	out := new(AlertChannelSlack)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AlertChannelSpec) DeepCopyInto(out *AlertChannelSpec) {
 	*out = *in
 	out.OpsGenie = in.OpsGenie
 	out.Email = in.Email
+	if in.Slack != nil {
+		in, out := &in.Slack, &out.Slack
+		*out = new(AlertChannelSlack)
+		**out = **in
+	}
+	if in.PagerDuty != nil {
+		in, out := &in.PagerDuty, &out.PagerDuty
+		*out = new(AlertChannelPagerDuty)
+		**out = **in
+	}
+	if in.Webhook != nil {
+		in, out := &in.Webhook, &out.Webhook
+		*out = new(AlertChannelWebhook)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SMS != nil {
+		in, out := &in.SMS, &out.SMS
+		*out = new(AlertChannelSMS)
+		**out = **in
+	}
+	if in.MSTeams != nil {
+		in, out := &in.MSTeams, &out.MSTeams
+		*out = new(AlertChannelMSTeams)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AlertChannelSpec.
@@ -119,6 +229,7 @@ func (in *AlertChannelSpec) DeepCopy() *AlertChannelSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AlertChannelStatus) DeepCopyInto(out *AlertChannelStatus) {
 	*out = *in
+	in.SyncStatus.DeepCopyInto(&out.SyncStatus)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AlertChannelStatus.
@@ -131,13 +242,63 @@ func (in *AlertChannelStatus) DeepCopy() *AlertChannelStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AlertChannelWebhook) DeepCopyInto(out *AlertChannelWebhook) {
+	*out = *in
+	out.URLSecret = in.URLSecret
+	if in.Headers != nil {
+		in, out := &in.Headers, &out.Headers
+		*out = make([]AlertChannelKeyValue, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.QueryParameters != nil {
+		in, out := &in.QueryParameters, &out.QueryParameters
+		*out = make([]AlertChannelKeyValue, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.WebhookSecret != nil {
+		in, out := &in.WebhookSecret, &out.WebhookSecret
+		*out = new(v1.ObjectReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AlertChannelWebhook.
+func (in *AlertChannelWebhook) DeepCopy() *AlertChannelWebhook {
+	if in == nil {
+		return nil
+	}
+	out := new(AlertChannelWebhook)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AlertSettings) DeepCopyInto(out *AlertSettings) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AlertSettings.
+func (in *AlertSettings) DeepCopy() *AlertSettings {
+	if in == nil {
+		return nil
+	}
+	out := new(AlertSettings)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ApiCheck) DeepCopyInto(out *ApiCheck) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
-	out.Status = in.Status
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApiCheck.
@@ -158,6 +319,41 @@ func (in *ApiCheck) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ApiCheckAssertion) DeepCopyInto(out *ApiCheckAssertion) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApiCheckAssertion.
+func (in *ApiCheckAssertion) DeepCopy() *ApiCheckAssertion {
+	if in == nil {
+		return nil
+	}
+	out := new(ApiCheckAssertion)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ApiCheckKeyValue) DeepCopyInto(out *ApiCheckKeyValue) {
+	*out = *in
+	if in.ValueFrom != nil {
+		in, out := &in.ValueFrom, &out.ValueFrom
+		*out = new(v1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApiCheckKeyValue.
+func (in *ApiCheckKeyValue) DeepCopy() *ApiCheckKeyValue {
+	if in == nil {
+		return nil
+	}
+	out := new(ApiCheckKeyValue)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ApiCheckList) DeepCopyInto(out *ApiCheckList) {
 	*out = *in
@@ -193,6 +389,35 @@ func (in *ApiCheckList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ApiCheckSpec) DeepCopyInto(out *ApiCheckSpec) {
 	*out = *in
+	if in.Locations != nil {
+		in, out := &in.Locations, &out.Locations
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Headers != nil {
+		in, out := &in.Headers, &out.Headers
+		*out = make([]ApiCheckKeyValue, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.QueryParameters != nil {
+		in, out := &in.QueryParameters, &out.QueryParameters
+		*out = make([]ApiCheckKeyValue, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Assertions != nil {
+		in, out := &in.Assertions, &out.Assertions
+		*out = make([]ApiCheckAssertion, len(*in))
+		copy(*out, *in)
+	}
+	if in.AlertSettings != nil {
+		in, out := &in.AlertSettings, &out.AlertSettings
+		*out = new(AlertSettings)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApiCheckSpec.
@@ -208,6 +433,7 @@ func (in *ApiCheckSpec) DeepCopy() *ApiCheckSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ApiCheckStatus) DeepCopyInto(out *ApiCheckStatus) {
 	*out = *in
+	in.SyncStatus.DeepCopyInto(&out.SyncStatus)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApiCheckStatus.
@@ -220,13 +446,241 @@ func (in *ApiCheckStatus) DeepCopy() *ApiCheckStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BrowserCheck) DeepCopyInto(out *BrowserCheck) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BrowserCheck.
+func (in *BrowserCheck) DeepCopy() *BrowserCheck {
+	if in == nil {
+		return nil
+	}
+	out := new(BrowserCheck)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *BrowserCheck) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BrowserCheckList) DeepCopyInto(out *BrowserCheckList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]BrowserCheck, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BrowserCheckList.
+func (in *BrowserCheckList) DeepCopy() *BrowserCheckList {
+	if in == nil {
+		return nil
+	}
+	out := new(BrowserCheckList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *BrowserCheckList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BrowserCheckSpec) DeepCopyInto(out *BrowserCheckSpec) {
+	*out = *in
+	if in.ScriptConfigMap != nil {
+		in, out := &in.ScriptConfigMap, &out.ScriptConfigMap
+		*out = new(v1.ConfigMapKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BrowserCheckSpec.
+func (in *BrowserCheckSpec) DeepCopy() *BrowserCheckSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(BrowserCheckSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BrowserCheckStatus) DeepCopyInto(out *BrowserCheckStatus) {
+	*out = *in
+	in.SyncStatus.DeepCopyInto(&out.SyncStatus)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BrowserCheckStatus.
+func (in *BrowserCheckStatus) DeepCopy() *BrowserCheckStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(BrowserCheckStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CheckGroup) DeepCopyInto(out *CheckGroup) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CheckGroup.
+func (in *CheckGroup) DeepCopy() *CheckGroup {
+	if in == nil {
+		return nil
+	}
+	out := new(CheckGroup)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CheckGroup) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CheckGroupList) DeepCopyInto(out *CheckGroupList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]CheckGroup, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CheckGroupList.
+func (in *CheckGroupList) DeepCopy() *CheckGroupList {
+	if in == nil {
+		return nil
+	}
+	out := new(CheckGroupList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CheckGroupList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ChecklyAccount) DeepCopyInto(out *ChecklyAccount) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChecklyAccount.
+func (in *ChecklyAccount) DeepCopy() *ChecklyAccount {
+	if in == nil {
+		return nil
+	}
+	out := new(ChecklyAccount)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ChecklyAccount) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ChecklyAccountList) DeepCopyInto(out *ChecklyAccountList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ChecklyAccount, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChecklyAccountList.
+func (in *ChecklyAccountList) DeepCopy() *ChecklyAccountList {
+	if in == nil {
+		return nil
+	}
+	out := new(ChecklyAccountList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ChecklyAccountList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ChecklyAccountSpec) DeepCopyInto(out *ChecklyAccountSpec) {
+	*out = *in
+	out.APIKeySecret = in.APIKeySecret
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChecklyAccountSpec.
+func (in *ChecklyAccountSpec) DeepCopy() *ChecklyAccountSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ChecklyAccountSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Group) DeepCopyInto(out *Group) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Group.
@@ -287,11 +741,26 @@ func (in *GroupSpec) DeepCopyInto(out *GroupSpec) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.PrivateLocations != nil {
+		in, out := &in.PrivateLocations, &out.PrivateLocations
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	if in.AlertChannels != nil {
 		in, out := &in.AlertChannels, &out.AlertChannels
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.AlertSettings != nil {
+		in, out := &in.AlertSettings, &out.AlertSettings
+		*out = new(AlertSettings)
+		**out = **in
+	}
+	if in.AllowedNamespaces != nil {
+		in, out := &in.AllowedNamespaces, &out.AllowedNamespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GroupSpec.
@@ -307,6 +776,7 @@ func (in *GroupSpec) DeepCopy() *GroupSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *GroupStatus) DeepCopyInto(out *GroupStatus) {
 	*out = *in
+	in.SyncStatus.DeepCopyInto(&out.SyncStatus)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GroupStatus.
@@ -318,3 +788,88 @@ func (in *GroupStatus) DeepCopy() *GroupStatus {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NamespacedAlertChannel) DeepCopyInto(out *NamespacedAlertChannel) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NamespacedAlertChannel.
+func (in *NamespacedAlertChannel) DeepCopy() *NamespacedAlertChannel {
+	if in == nil {
+		return nil
+	}
+	out := new(NamespacedAlertChannel)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NamespacedAlertChannel) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NamespacedAlertChannelList) DeepCopyInto(out *NamespacedAlertChannelList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]NamespacedAlertChannel, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NamespacedAlertChannelList.
+func (in *NamespacedAlertChannelList) DeepCopy() *NamespacedAlertChannelList {
+	if in == nil {
+		return nil
+	}
+	out := new(NamespacedAlertChannelList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NamespacedAlertChannelList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SyncStatus) DeepCopyInto(out *SyncStatus) {
+	*out = *in
+	if in.LastSyncTime != nil {
+		in, out := &in.LastSyncTime, &out.LastSyncTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SyncStatus.
+func (in *SyncStatus) DeepCopy() *SyncStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(SyncStatus)
+	in.DeepCopyInto(out)
+	return out
+}
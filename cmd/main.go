@@ -17,30 +17,38 @@ limitations under the License.
 package main
 
 import (
+	"context"
 	"crypto/tls"
 	"errors"
 	"flag"
+	"fmt"
 	"os"
+	"time"
 
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
 	// to ensure that exec-entrypoint and run can make use of them.
 
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
 	"sigs.k8s.io/controller-runtime/pkg/metrics/filters"
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
-
-	"github.com/checkly/checkly-go-sdk"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
 
 	checklyv1alpha1 "github.com/checkly/checkly-operator/api/checkly/v1alpha1"
+	external "github.com/checkly/checkly-operator/external/checkly"
 	checklycontrollers "github.com/checkly/checkly-operator/internal/controller/checkly"
 	networkingcontrollers "github.com/checkly/checkly-operator/internal/controller/networking"
+	checklymetrics "github.com/checkly/checkly-operator/internal/metrics"
+	checklywebhooks "github.com/checkly/checkly-operator/internal/webhook/checkly"
 	//kubebuilder:scaffold:imports
 )
 
@@ -53,6 +61,7 @@ func init() {
 	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
 
 	utilruntime.Must(checklyv1alpha1.AddToScheme(scheme))
+	utilruntime.Must(gatewayv1.AddToScheme(scheme))
 	//kubebuilder:scaffold:scheme
 }
 
@@ -62,6 +71,14 @@ func main() {
 	var probeAddr string
 	var secureMetrics bool
 	var controllerDomain string
+	var enableWebhooks bool
+	var enableServiceDiscovery bool
+	var enableDeploymentDiscovery bool
+	var resyncInterval time.Duration
+	var deletionPolicy string
+	var clusterID string
+	var checklyOptions external.ClientOptions
+	var checklyDebugLog string
 	var tlsOpts []func(*tls.Config)
 	flag.StringVar(&metricsAddr, "metrics-bind-address", "0", "The address the metrics endpoint binds to. "+
 		"Use :8443 for HTTPS or :8080 for HTTP, or leave as 0 to disable the metrics service.")
@@ -72,6 +89,34 @@ func main() {
 	flag.BoolVar(&secureMetrics, "metrics-secure", true,
 		"If set, the metrics endpoint is served securely via HTTPS. Use --metrics-secure=false to use HTTP instead.")
 	flag.StringVar(&controllerDomain, "controller-domain", "k8s.checklyhq.com", "Domain to use for annotations and finalizers.")
+	flag.BoolVar(&enableWebhooks, "enable-webhooks", false,
+		"If set, the defaulting and validating admission webhooks are served. Requires a serving certificate, see config/certmanager.")
+	flag.BoolVar(&enableServiceDiscovery, "enable-service-discovery", false,
+		"If set, ApiChecks are generated for annotated Services of type LoadBalancer. Caches all Services of the cluster.")
+	flag.BoolVar(&enableDeploymentDiscovery, "enable-deployment-discovery", false,
+		"If set, ApiChecks are generated for the health URL annotation of Deployments. Caches all Deployments of the cluster.")
+	flag.DurationVar(&resyncInterval, "resync-interval", 10*time.Minute,
+		"How often checks, groups and alert channels are compared with checklyhq.com to revert changes made outside of the operator. Use 0 to disable.")
+	flag.StringVar(&deletionPolicy, "default-deletion-policy", checklyv1alpha1.DeletionPolicyDelete,
+		"What happens in checklyhq.com when a resource without a deletionPolicy is deleted: Delete, Retain or Orphan.")
+	flag.StringVar(&clusterID, "cluster-id", "",
+		"Identifies the cluster in the ownership tags of the checklyhq.com checks and groups. "+
+			"Defaults to the UID of the kube-system namespace, set it to keep the ownership when migrating to another cluster.")
+	flag.StringVar(&checklyOptions.BaseURL, "checkly-api-url", external.DefaultBaseURL, "The address of the checklyhq.com API.")
+	flag.StringVar(&checklyOptions.ProxyURL, "checkly-proxy-url", "",
+		"The HTTP proxy used to reach the checklyhq.com API. The HTTPS_PROXY and NO_PROXY environment variables are used if empty.")
+	flag.StringVar(&checklyOptions.CABundle, "checkly-ca-bundle", "",
+		"Path of a PEM file with CA certificates trusted for the checklyhq.com API besides the system ones.")
+	flag.DurationVar(&external.RequestTimeout, "checkly-request-timeout", external.RequestTimeout,
+		"How long a single checklyhq.com API call may take before it's cancelled.")
+	flag.Float64Var(&checklyOptions.RateLimit, "checkly-rate-limit", external.DefaultRateLimit,
+		"Number of checklyhq.com API requests per second and account. Use 0 to disable the limit.")
+	flag.IntVar(&checklyOptions.RateBurst, "checkly-rate-burst", external.DefaultRateBurst,
+		"Number of checklyhq.com API requests sent at once before the rate limit applies.")
+	flag.IntVar(&checklyOptions.MaxRetries, "checkly-max-retries", external.DefaultMaxRetries,
+		"How often rate limited checklyhq.com API requests and server errors are retried.")
+	flag.StringVar(&checklyDebugLog, "checkly-debug-log", "",
+		"If set, the checklyhq.com API requests and responses are written to this file with the credentials redacted. Use - for stderr.")
 	opts := zap.Options{
 		// Development: true,
 	}
@@ -103,13 +148,27 @@ func main() {
 		metricsServerOptions.FilterProvider = filters.WithAuthenticationAndAuthorization
 	}
 
+	webhookServer := webhook.NewServer(webhook.Options{
+		TLSOpts: tlsOpts,
+	})
+
 	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
 
 	setupLog.Info("Controller domain setup", "value", controllerDomain)
+	setupLog.Info("Resync interval setup", "value", resyncInterval)
+
+	switch deletionPolicy {
+	case checklyv1alpha1.DeletionPolicyDelete, checklyv1alpha1.DeletionPolicyRetain, checklyv1alpha1.DeletionPolicyOrphan:
+		setupLog.Info("Default deletion policy setup", "value", deletionPolicy)
+	default:
+		setupLog.Error(fmt.Errorf("unknown deletion policy %q", deletionPolicy), "invalid --default-deletion-policy, use Delete, Retain or Orphan")
+		os.Exit(1)
+	}
 
 	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
 		Scheme:                 scheme,
 		Metrics:                metricsServerOptions,
+		WebhookServer:          webhookServer,
 		HealthProbeBindAddress: probeAddr,
 		LeaderElection:         enableLeaderElection,
 		LeaderElectionID:       "4e7eab13.checklyhq.com",
@@ -119,50 +178,143 @@ func main() {
 		os.Exit(1)
 	}
 
-	baseUrl := "https://api.checklyhq.com"
-	apiKey := os.Getenv("CHECKLY_API_KEY")
-	if apiKey == "" {
-		setupLog.Error(errors.New("checklyhq.com API key environment variable is undefined"), "checklyhq.com credentials missing")
+	switch checklyDebugLog {
+	case "":
+	case "-":
+		checklyOptions.Debug = os.Stderr
+	default:
+		debugLog, err := os.OpenFile(checklyDebugLog, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+		if err != nil {
+			setupLog.Error(err, "unable to open checklyhq.com debug log")
+			os.Exit(1)
+		}
+		defer debugLog.Close()
+		checklyOptions.Debug = debugLog
+	}
+
+	clientFactory, err := external.NewClientFactory(checklyOptions)
+	if err != nil {
+		setupLog.Error(err, "unable to set up checklyhq.com client")
 		os.Exit(1)
 	}
+	setupLog.Info("Checkly API setup", "url", checklyOptions.BaseURL, "timeout", external.RequestTimeout,
+		"rateLimit", checklyOptions.RateLimit, "maxRetries", checklyOptions.MaxRetries)
 
+	// The environment configures the default account, resources referencing a ChecklyAccount use its credentials instead
+	var client external.Client
+	apiKey := os.Getenv("CHECKLY_API_KEY")
 	accountId := os.Getenv("CHECKLY_ACCOUNT_ID")
-	if accountId == "" {
+	switch {
+	case apiKey != "" && accountId != "":
+		client = clientFactory.NewClient(apiKey, accountId)
+	case apiKey == "" && accountId == "":
+		setupLog.Info("No default checklyhq.com account configured, only resources referencing a ChecklyAccount are synced")
+	case apiKey == "":
+		setupLog.Error(errors.New("checklyhq.com API key environment variable is undefined"), "checklyhq.com credentials missing")
+		os.Exit(1)
+	default:
 		setupLog.Error(errors.New("checklyhq.com Account ID environment variable is undefined"), "checklyhq.com credentials missing")
 		os.Exit(1)
 	}
 
-	client := checkly.NewClient(
-		baseUrl,
-		apiKey,
-		nil, //custom http client, defaults to http.DefaultClient
-		nil, //io.Writer to output debug messages
-	)
-
-	client.SetAccountId(accountId)
+	accounts := checklycontrollers.NewAccountClients(mgr.GetClient(), clientFactory.NewClient)
 
 	if err = (&networkingcontrollers.IngressReconciler{
 		Client:           mgr.GetClient(),
 		Scheme:           mgr.GetScheme(),
 		ControllerDomain: controllerDomain,
+		Recorder:         mgr.GetEventRecorderFor("ingress-controller"),
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Ingress")
 		os.Exit(1)
 	}
+	if enableServiceDiscovery {
+		if err = (&networkingcontrollers.ServiceReconciler{
+			Client:           mgr.GetClient(),
+			Scheme:           mgr.GetScheme(),
+			ControllerDomain: controllerDomain,
+			Recorder:         mgr.GetEventRecorderFor("service-controller"),
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "Service")
+			os.Exit(1)
+		}
+	}
+	if enableDeploymentDiscovery {
+		if err = (&networkingcontrollers.DeploymentReconciler{
+			Client:           mgr.GetClient(),
+			Scheme:           mgr.GetScheme(),
+			ControllerDomain: controllerDomain,
+			Recorder:         mgr.GetEventRecorderFor("deployment-controller"),
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "Deployment")
+			os.Exit(1)
+		}
+	}
+	// HTTPRoutes are only watched if the Gateway API CRDs are installed in the cluster
+	_, err = mgr.GetRESTMapper().RESTMapping(gatewayv1.SchemeGroupVersion.WithKind("HTTPRoute").GroupKind(), gatewayv1.SchemeGroupVersion.Version)
+	switch {
+	case err == nil:
+		if err = (&networkingcontrollers.HTTPRouteReconciler{
+			Client:           mgr.GetClient(),
+			Scheme:           mgr.GetScheme(),
+			ControllerDomain: controllerDomain,
+			Recorder:         mgr.GetEventRecorderFor("httproute-controller"),
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "HTTPRoute")
+			os.Exit(1)
+		}
+	case meta.IsNoMatchError(err):
+		setupLog.Info("Gateway API HTTPRoute CRD not found, HTTPRoutes are not watched")
+	default:
+		setupLog.Error(err, "unable to look up the Gateway API HTTPRoute CRD")
+		os.Exit(1)
+	}
+	// The ownership tags let the operator find its checks and groups again when the status of a resource is lost
+	if clusterID == "" {
+		clusterID, err = checklycontrollers.ClusterID(context.Background(), mgr.GetAPIReader())
+		if err != nil {
+			setupLog.Error(err, "unable to determine the cluster ID, checks and groups are created without ownership tags")
+		}
+	}
+	setupLog.Info("Cluster ID setup", "value", clusterID)
 	if err = (&checklycontrollers.ApiCheckReconciler{
 		Client:           mgr.GetClient(),
 		Scheme:           mgr.GetScheme(),
 		ApiClient:        client,
 		ControllerDomain: controllerDomain,
+		Recorder:         mgr.GetEventRecorderFor("apicheck-controller"),
+		ResyncInterval:   resyncInterval,
+		Accounts:         accounts,
+		DeletionPolicy:   deletionPolicy,
+		ClusterID:        clusterID,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "ApiCheck")
 		os.Exit(1)
 	}
+	if err = (&checklycontrollers.BrowserCheckReconciler{
+		Client:           mgr.GetClient(),
+		Scheme:           mgr.GetScheme(),
+		ApiClient:        client,
+		ControllerDomain: controllerDomain,
+		Recorder:         mgr.GetEventRecorderFor("browsercheck-controller"),
+		ResyncInterval:   resyncInterval,
+		Accounts:         accounts,
+		DeletionPolicy:   deletionPolicy,
+		ClusterID:        clusterID,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "BrowserCheck")
+		os.Exit(1)
+	}
 	if err = (&checklycontrollers.GroupReconciler{
 		Client:           mgr.GetClient(),
 		Scheme:           mgr.GetScheme(),
 		ApiClient:        client,
 		ControllerDomain: controllerDomain,
+		Recorder:         mgr.GetEventRecorderFor("group-controller"),
+		ResyncInterval:   resyncInterval,
+		Accounts:         accounts,
+		DeletionPolicy:   deletionPolicy,
+		ClusterID:        clusterID,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Group")
 		os.Exit(1)
@@ -172,12 +324,78 @@ func main() {
 		Scheme:           mgr.GetScheme(),
 		ApiClient:        client,
 		ControllerDomain: controllerDomain,
+		Recorder:         mgr.GetEventRecorderFor("alertchannel-controller"),
+		ResyncInterval:   resyncInterval,
+		Accounts:         accounts,
+		DeletionPolicy:   deletionPolicy,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "AlertChannel")
 		os.Exit(1)
 	}
+	if err = (&checklycontrollers.CheckGroupReconciler{
+		GroupReconciler: checklycontrollers.GroupReconciler{
+			Client:           mgr.GetClient(),
+			Scheme:           mgr.GetScheme(),
+			ApiClient:        client,
+			ControllerDomain: controllerDomain,
+			Recorder:         mgr.GetEventRecorderFor("checkgroup-controller"),
+			ResyncInterval:   resyncInterval,
+			Accounts:         accounts,
+			DeletionPolicy:   deletionPolicy,
+			ClusterID:        clusterID,
+		},
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "CheckGroup")
+		os.Exit(1)
+	}
+	if err = (&checklycontrollers.NamespacedAlertChannelReconciler{
+		AlertChannelReconciler: checklycontrollers.AlertChannelReconciler{
+			Client:           mgr.GetClient(),
+			Scheme:           mgr.GetScheme(),
+			ApiClient:        client,
+			ControllerDomain: controllerDomain,
+			Recorder:         mgr.GetEventRecorderFor("namespacedalertchannel-controller"),
+			ResyncInterval:   resyncInterval,
+			Accounts:         accounts,
+			DeletionPolicy:   deletionPolicy,
+		},
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "NamespacedAlertChannel")
+		os.Exit(1)
+	}
+	if enableWebhooks {
+		if err = checklywebhooks.SetupApiCheckWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "ApiCheck")
+			os.Exit(1)
+		}
+		if err = checklywebhooks.SetupBrowserCheckWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "BrowserCheck")
+			os.Exit(1)
+		}
+		if err = checklywebhooks.SetupGroupWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "Group")
+			os.Exit(1)
+		}
+		if err = checklywebhooks.SetupAlertChannelWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "AlertChannel")
+			os.Exit(1)
+		}
+		if err = checklywebhooks.SetupCheckGroupWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "CheckGroup")
+			os.Exit(1)
+		}
+		if err = checklywebhooks.SetupNamespacedAlertChannelWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "NamespacedAlertChannel")
+			os.Exit(1)
+		}
+	}
 	//kubebuilder:scaffold:builder
 
+	if err := metrics.Registry.Register(checklymetrics.NewResourceCollector(mgr.GetClient())); err != nil {
+		setupLog.Error(err, "unable to register metrics collector")
+		os.Exit(1)
+	}
+
 	setupLog.V(1).Info("starting health endpoint")
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
 		setupLog.Error(err, "unable to set up health check")
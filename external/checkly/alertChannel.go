@@ -2,48 +2,116 @@ package external
 
 import (
 	"context"
-	"time"
+	"fmt"
+	"strings"
 
 	"github.com/checkly/checkly-go-sdk"
 	checklyv1alpha1 "github.com/checkly/checkly-operator/api/checkly/v1alpha1"
 )
 
-func checklyAlertChannel(alertChannel *checklyv1alpha1.AlertChannel, opsGenieConfig checkly.AlertChannelOpsgenie) (ac checkly.AlertChannel, err error) {
+// MS Teams alert channels are webhooks with a dedicated webhook type
+const webhookTypeMSTeams = "WEBHOOK_MSTEAMS"
+
+// AlertChannelConfig holds the alert channel configuration which is read from secrets by the controller
+type AlertChannelConfig struct {
+	Opsgenie  checkly.AlertChannelOpsgenie
+	Slack     *checkly.AlertChannelSlack
+	Pagerduty *checkly.AlertChannelPagerduty
+	Webhook   *checkly.AlertChannelWebhook
+	MSTeams   *checkly.AlertChannelWebhook `json:",omitempty"`
+}
+
+// MSTeamsWebhook returns the webhook configuration of a Microsoft Teams alert channel
+func MSTeamsWebhook(name string, URL string) *checkly.AlertChannelWebhook {
+	return &checkly.AlertChannelWebhook{
+		Name:        name,
+		URL:         URL,
+		WebhookType: webhookTypeMSTeams,
+		Method:      "POST",
+	}
+}
+
+func checklyAlertChannel(alertChannel *checklyv1alpha1.AlertChannel, config AlertChannelConfig) (ac checkly.AlertChannel, err error) {
 	sslExpiry := false
 
+	// The validating webhook is optional, a spec with several types or none would be sent with the wrong or an empty type
+	types := alertChannelTypes(alertChannel, config)
+	if len(types) != 1 {
+		err = fmt.Errorf("exactly one alert channel type has to be set, got %d: %s", len(types), strings.Join(types, ", "))
+		return
+	}
+
 	ac = checkly.AlertChannel{
 		SendRecovery: &alertChannel.Spec.SendRecovery,
 		SendFailure:  &alertChannel.Spec.SendFailure,
 		SSLExpiry:    &sslExpiry,
 	}
 
-	if opsGenieConfig != (checkly.AlertChannelOpsgenie{}) {
-		ac.Type = "OPSGENIE" // Type has to be all caps, see https://developers.checklyhq.com/reference/postv1alertchannels
-		ac.Opsgenie = &opsGenieConfig
-		return
-	}
-
-	if alertChannel.Spec.Email != (checkly.AlertChannelEmail{}) {
-		ac.Type = "EMAIL" // Type has to be all caps, see https://developers.checklyhq.com/reference/postv1alertchannels
+	// Type has to be all caps, see https://developers.checklyhq.com/reference/postv1alertchannels
+	switch {
+	case config.Opsgenie != (checkly.AlertChannelOpsgenie{}):
+		ac.Type = checkly.AlertTypeOpsgenie
+		ac.Opsgenie = &config.Opsgenie
+	case config.Slack != nil:
+		ac.Type = checkly.AlertTypeSlack
+		ac.Slack = config.Slack
+	case config.Pagerduty != nil:
+		ac.Type = checkly.AlertTypePagerduty
+		ac.Pagerduty = config.Pagerduty
+	case config.Webhook != nil:
+		ac.Type = checkly.AlertTypeWebhook
+		ac.Webhook = config.Webhook
+	case config.MSTeams != nil:
+		ac.Type = checkly.AlertTypeWebhook
+		ac.Webhook = config.MSTeams
+	case alertChannel.Spec.SMS != nil:
+		ac.Type = checkly.AlertTypeSMS
+		ac.SMS = &checkly.AlertChannelSMS{
+			Name:   alertChannel.Name,
+			Number: alertChannel.Spec.SMS.Number,
+		}
+	case alertChannel.Spec.Email != (checkly.AlertChannelEmail{}):
+		ac.Type = checkly.AlertTypeEmail
 		ac.Email = &checkly.AlertChannelEmail{
 			Address: alertChannel.Spec.Email.Address,
 		}
-		return
 	}
+
 	return
 }
 
-func CreateAlertChannel(alertChannel *checklyv1alpha1.AlertChannel, opsGenieConfig checkly.AlertChannelOpsgenie, client checkly.Client) (ID int64, err error) {
+// alertChannelTypes returns the alert channel types set in the spec or read from the secrets
+func alertChannelTypes(alertChannel *checklyv1alpha1.AlertChannel, config AlertChannelConfig) (types []string) {
+	for _, alertChannelType := range []struct {
+		name string
+		set  bool
+	}{
+		{"email", alertChannel.Spec.Email != (checkly.AlertChannelEmail{})},
+		{"opsgenie", config.Opsgenie != (checkly.AlertChannelOpsgenie{})},
+		{"slack", config.Slack != nil},
+		{"pagerduty", config.Pagerduty != nil},
+		{"webhook", config.Webhook != nil},
+		{"sms", alertChannel.Spec.SMS != nil},
+		{"msteams", config.MSTeams != nil},
+	} {
+		if alertChannelType.set {
+			types = append(types, alertChannelType.name)
+		}
+	}
+
+	return
+}
 
-	ac, err := checklyAlertChannel(alertChannel, opsGenieConfig)
+func CreateAlertChannel(ctx context.Context, alertChannel *checklyv1alpha1.AlertChannel, config AlertChannelConfig, client Client) (ID int64, err error) {
+
+	ac, err := checklyAlertChannel(alertChannel, config)
 	if err != nil {
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
-	defer cancel()
-
+	ctx, call := startAPICall(ctx, resourceAlertChannel, operationCreate)
 	gotAlertChannel, err := client.CreateAlertChannel(ctx, ac)
+	err = call.done(err)
 	if err != nil {
 		return
 	}
@@ -53,16 +121,15 @@ func CreateAlertChannel(alertChannel *checklyv1alpha1.AlertChannel, opsGenieConf
 	return
 }
 
-func UpdateAlertChannel(alertChannel *checklyv1alpha1.AlertChannel, opsGenieConfig checkly.AlertChannelOpsgenie, client checkly.Client) (err error) {
-	ac, err := checklyAlertChannel(alertChannel, opsGenieConfig)
+func UpdateAlertChannel(ctx context.Context, alertChannel *checklyv1alpha1.AlertChannel, config AlertChannelConfig, client Client) (err error) {
+	ac, err := checklyAlertChannel(alertChannel, config)
 	if err != nil {
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
-	defer cancel()
-
+	ctx, call := startAPICall(ctx, resourceAlertChannel, operationUpdate)
 	_, err = client.UpdateAlertChannel(ctx, alertChannel.Status.ID, ac)
+	err = call.done(err)
 	if err != nil {
 		return
 	}
@@ -70,14 +137,89 @@ func UpdateAlertChannel(alertChannel *checklyv1alpha1.AlertChannel, opsGenieConf
 	return
 }
 
-func DeleteAlertChannel(alertChannel *checklyv1alpha1.AlertChannel, client checkly.Client) (err error) {
-	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
-	defer cancel()
-
+// DeleteAlertChannel deletes an existing checklyhq.com alert channel, an alert channel which no longer exists in checklyhq.com isn't an error
+func DeleteAlertChannel(ctx context.Context, alertChannel *checklyv1alpha1.AlertChannel, client Client) (err error) {
+	ctx, call := startAPICall(ctx, resourceAlertChannel, operationDelete)
 	err = client.DeleteAlertChannel(ctx, alertChannel.Status.ID)
+	err = call.done(err)
+	if isNotFound(err) {
+		err = nil
+	}
+
+	return
+}
+
+// AlertChannelDrift fetches the checklyhq.com alert channel and returns the fields which differ from the desired state,
+// found is false if the alert channel no longer exists in checkly
+func AlertChannelDrift(ctx context.Context, alertChannel *checklyv1alpha1.AlertChannel, config AlertChannelConfig, client Client) (drift []string, found bool, err error) {
+	ac, err := checklyAlertChannel(alertChannel, config)
+	if err != nil {
+		return
+	}
+
+	ctx, call := startAPICall(ctx, resourceAlertChannel, operationGet)
+	gotAlertChannel, err := client.GetAlertChannel(ctx, alertChannel.Status.ID)
+	err = call.done(err)
 	if err != nil {
+		if isNotFound(err) {
+			err = nil
+		}
+		return
+	}
+
+	found = true
+	drift = alertChannelDrift(ac, *gotAlertChannel)
+
+	return
+}
+
+// alertChannelDrift returns the fields of the remote alert channel which differ from the desired alert channel
+func alertChannelDrift(desired checkly.AlertChannel, remote checkly.AlertChannel) (drift []string) {
+	if desired.Type != remote.Type {
+		drift = append(drift, "type")
 		return
 	}
+	if !sameBool(desired.SendRecovery, remote.SendRecovery) {
+		drift = append(drift, "sendRecovery")
+	}
+	if !sameBool(desired.SendFailure, remote.SendFailure) {
+		drift = append(drift, "sendFailure")
+	}
+	if desired.Email != nil && (remote.Email == nil || desired.Email.Address != remote.Email.Address) {
+		drift = append(drift, "email")
+	}
+	if desired.SMS != nil && (remote.SMS == nil || desired.SMS.Number != remote.SMS.Number) {
+		drift = append(drift, "sms")
+	}
+	if desired.Slack != nil && (remote.Slack == nil || desired.Slack.Channel != remote.Slack.Channel) {
+		drift = append(drift, "slack")
+	}
+	if desired.Pagerduty != nil && (remote.Pagerduty == nil ||
+		desired.Pagerduty.Account != remote.Pagerduty.Account ||
+		desired.Pagerduty.ServiceName != remote.Pagerduty.ServiceName) {
+		drift = append(drift, "pagerduty")
+	}
+	if desired.Webhook != nil && (remote.Webhook == nil ||
+		desired.Webhook.URL != remote.Webhook.URL ||
+		desired.Webhook.WebhookType != remote.Webhook.WebhookType ||
+		desired.Webhook.Method != remote.Webhook.Method ||
+		desired.Webhook.Template != remote.Webhook.Template) {
+		drift = append(drift, "webhook")
+	}
+	// The secrets, ex. the OpsGenie API key, are not compared, checkly does not return them in full
+	if desired.Opsgenie != nil && (remote.Opsgenie == nil ||
+		desired.Opsgenie.Name != remote.Opsgenie.Name ||
+		desired.Opsgenie.Region != remote.Opsgenie.Region ||
+		desired.Opsgenie.Priority != remote.Opsgenie.Priority) {
+		drift = append(drift, "opsgenie")
+	}
 
 	return
 }
+
+func sameBool(x *bool, y *bool) bool {
+	if x == nil || y == nil {
+		return x == y
+	}
+	return *x == *y
+}
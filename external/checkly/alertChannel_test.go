@@ -1,6 +1,7 @@
 package external
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"math/rand"
@@ -26,15 +27,12 @@ func TestChecklyAlertChannel(t *testing.T) {
 		},
 	}
 
-	opsGenieConfigEmpty := checkly.AlertChannelOpsgenie{}
+	configEmpty := AlertChannelConfig{}
 
-	returned, err := checklyAlertChannel(&dataEmpty, opsGenieConfigEmpty)
-	if err != nil {
-		t.Errorf("Expected no error, got %e", err)
-	}
-
-	if returned.Opsgenie != nil {
-		t.Errorf("Expected empty Opsgenie config, got %s", returned.Opsgenie)
+	// No type at all
+	_, err := checklyAlertChannel(&dataEmpty, configEmpty)
+	if err == nil {
+		t.Error("Expected error, got none")
 	}
 
 	dataEmail := dataEmpty
@@ -42,7 +40,7 @@ func TestChecklyAlertChannel(t *testing.T) {
 		Address: acEmailAddress,
 	}
 
-	returned, err = checklyAlertChannel(&dataEmail, opsGenieConfigEmpty)
+	returned, err := checklyAlertChannel(&dataEmail, configEmpty)
 	if err != nil {
 		t.Errorf("Expected no error, got %e", err)
 	}
@@ -58,7 +56,7 @@ func TestChecklyAlertChannel(t *testing.T) {
 		Name:     "baz",
 	}
 
-	returned, err = checklyAlertChannel(&dataEmpty, dataOpsGenieFull)
+	returned, err = checklyAlertChannel(&dataEmpty, AlertChannelConfig{Opsgenie: dataOpsGenieFull})
 	if err != nil {
 		t.Errorf("Expected no error, got %e", err)
 	}
@@ -79,6 +77,89 @@ func TestChecklyAlertChannel(t *testing.T) {
 		t.Errorf("Expected nil, got %s", returned.Email)
 	}
 
+	returned, err = checklyAlertChannel(&dataEmpty, AlertChannelConfig{
+		Slack: &checkly.AlertChannelSlack{
+			WebhookURL: "https://hooks.slack.com/services/foo",
+			Channel:    "#alerts",
+		},
+	})
+	if err != nil {
+		t.Errorf("Expected no error, got %e", err)
+	}
+
+	if returned.Type != checkly.AlertTypeSlack {
+		t.Errorf("Expected %s, got %s", checkly.AlertTypeSlack, returned.Type)
+	}
+
+	if returned.Slack == nil || returned.Slack.Channel != "#alerts" {
+		t.Errorf("Expected Slack channel %s, got %v", "#alerts", returned.Slack)
+	}
+
+	returned, err = checklyAlertChannel(&dataEmpty, AlertChannelConfig{
+		Pagerduty: &checkly.AlertChannelPagerduty{
+			ServiceKey:  "foo-bar",
+			Account:     "baz",
+			ServiceName: "qux",
+		},
+	})
+	if err != nil {
+		t.Errorf("Expected no error, got %e", err)
+	}
+
+	if returned.Type != checkly.AlertTypePagerduty {
+		t.Errorf("Expected %s, got %s", checkly.AlertTypePagerduty, returned.Type)
+	}
+
+	if returned.Pagerduty == nil || returned.Pagerduty.ServiceKey != "foo-bar" {
+		t.Errorf("Expected PagerDuty service key %s, got %v", "foo-bar", returned.Pagerduty)
+	}
+
+	returned, err = checklyAlertChannel(&dataEmpty, AlertChannelConfig{
+		MSTeams: MSTeamsWebhook(acName, "https://foo.webhook.office.com/bar"),
+	})
+	if err != nil {
+		t.Errorf("Expected no error, got %e", err)
+	}
+
+	if returned.Type != checkly.AlertTypeWebhook {
+		t.Errorf("Expected %s, got %s", checkly.AlertTypeWebhook, returned.Type)
+	}
+
+	if returned.Webhook == nil || returned.Webhook.WebhookType != "WEBHOOK_MSTEAMS" {
+		t.Errorf("Expected MS Teams webhook, got %v", returned.Webhook)
+	}
+
+	if returned.Webhook.Name != acName {
+		t.Errorf("Expected %s, got %s", acName, returned.Webhook.Name)
+	}
+
+	// MS Teams and a webhook at once
+	_, err = checklyAlertChannel(&dataEmpty, AlertChannelConfig{
+		Webhook: &checkly.AlertChannelWebhook{Name: acName, URL: "https://bar.baz", Method: "POST"},
+		MSTeams: MSTeamsWebhook(acName, "https://foo.webhook.office.com/bar"),
+	})
+	if err == nil {
+		t.Error("Expected error, got none")
+	}
+
+	dataSMS := dataEmpty
+	dataSMS.Spec.SMS = &checklyv1alpha1.AlertChannelSMS{
+		Number: "+15555550100",
+	}
+
+	returned, err = checklyAlertChannel(&dataSMS, configEmpty)
+	if err != nil {
+		t.Errorf("Expected no error, got %e", err)
+	}
+
+	if returned.Type != checkly.AlertTypeSMS {
+		t.Errorf("Expected %s, got %s", checkly.AlertTypeSMS, returned.Type)
+	}
+
+	if returned.SMS == nil || returned.SMS.Number != "+15555550100" || returned.SMS.Name != acName {
+		t.Errorf("Expected SMS number %s, got %v", "+15555550100", returned.SMS)
+	}
+
 }
 
 func TestAlertChannelActions(t *testing.T) {
@@ -100,7 +181,7 @@ func TestAlertChannelActions(t *testing.T) {
 		},
 	}
 
-	opsGenieConfigEmpty := checkly.AlertChannelOpsgenie{}
+	configEmpty := AlertChannelConfig{}
 
 	// Test errors
 	testClient := checkly.NewClient(
@@ -112,19 +193,19 @@ func TestAlertChannelActions(t *testing.T) {
 	testClient.SetAccountId("1234567890")
 
 	// Create fail
-	_, err := CreateAlertChannel(testData, opsGenieConfigEmpty, testClient)
+	_, err := CreateAlertChannel(context.Background(), testData, configEmpty, testClient)
 	if err == nil {
 		t.Error("Expected error, got none")
 	}
 
 	// Update fail
-	err = UpdateAlertChannel(testData, opsGenieConfigEmpty, testClient)
+	err = UpdateAlertChannel(context.Background(), testData, configEmpty, testClient)
 	if err == nil {
 		t.Error("Expected error, got none")
 	}
 
 	// Delete fail
-	err = DeleteAlertChannel(testData, testClient)
+	err = DeleteAlertChannel(context.Background(), testData, testClient)
 	if err == nil {
 		t.Error("Expected error, got none")
 	}
@@ -159,7 +240,7 @@ func TestAlertChannelActions(t *testing.T) {
 	}()
 
 	// Create success
-	testID, err := CreateAlertChannel(testData, opsGenieConfigEmpty, testClient)
+	testID, err := CreateAlertChannel(context.Background(), testData, configEmpty, testClient)
 	if err != nil {
 		t.Errorf("Expected no error, got %e", err)
 	}
@@ -168,15 +249,78 @@ func TestAlertChannelActions(t *testing.T) {
 	}
 
 	// Update success
-	err = UpdateAlertChannel(testData, opsGenieConfigEmpty, testClient)
+	err = UpdateAlertChannel(context.Background(), testData, configEmpty, testClient)
 	if err != nil {
 		t.Errorf("Expected no error, got %e", err)
 	}
 
 	// Delete success
-	err = DeleteAlertChannel(testData, testClient)
+	err = DeleteAlertChannel(context.Background(), testData, testClient)
 	if err != nil {
 		t.Errorf("Expecte no error, got %e", err)
 	}
 
 }
+
+func TestAlertChannelDrift(t *testing.T) {
+	desired, err := checklyAlertChannel(&checklyv1alpha1.AlertChannel{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "foo",
+		},
+		Spec: checklyv1alpha1.AlertChannelSpec{
+			SendRecovery: true,
+			Email: checkly.AlertChannelEmail{
+				Address: "foo@bar.baz",
+			},
+		},
+	}, AlertChannelConfig{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %e", err)
+	}
+
+	remote := desired
+	if drift := alertChannelDrift(desired, remote); len(drift) != 0 {
+		t.Errorf("Expected no drift, got %v", drift)
+	}
+
+	sendRecovery := false
+	remote.SendRecovery = &sendRecovery
+	remote.Email = &checkly.AlertChannelEmail{Address: "qux@bar.baz"}
+	drift := alertChannelDrift(desired, remote)
+	expectedDrift := []string{"sendRecovery", "email"}
+	if !sameStrings(drift, expectedDrift) {
+		t.Errorf("Expected %v, got %v", expectedDrift, drift)
+	}
+
+	webhookDesired, err := checklyAlertChannel(&checklyv1alpha1.AlertChannel{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "foo",
+		},
+	}, AlertChannelConfig{
+		Webhook: &checkly.AlertChannelWebhook{
+			Name:   "foo",
+			URL:    "https://bar.baz",
+			Method: "POST",
+		},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %e", err)
+	}
+
+	webhookRemote := webhookDesired
+	webhookRemote.Webhook = &checkly.AlertChannelWebhook{
+		Name:   "foo",
+		URL:    "https://bar.baz",
+		Method: "GET",
+	}
+	drift = alertChannelDrift(webhookDesired, webhookRemote)
+	if !sameStrings(drift, []string{"webhook"}) {
+		t.Errorf("Expected %v, got %v", []string{"webhook"}, drift)
+	}
+
+	remote.Type = "SLACK"
+	drift = alertChannelDrift(desired, remote)
+	if !sameStrings(drift, []string{"type"}) {
+		t.Errorf("Expected %v, got %v", []string{"type"}, drift)
+	}
+}
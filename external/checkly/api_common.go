@@ -16,7 +16,26 @@ limitations under the License.
 
 package external
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/checkly/checkly-go-sdk"
+	"github.com/checkly/checkly-operator/internal/metrics"
+)
+
+// Defaults used when the spec leaves a value empty, the defaulting webhooks set the same values
+const (
+	DefaultApiCheckFrequency       = 5
+	DefaultApiCheckMaxResponseTime = 15000
+	DefaultApiCheckMethod          = http.MethodGet
+	DefaultApiCheckBodyType        = "NONE"
+	DefaultBrowserCheckFrequency   = 10
+	DefaultGroupLocation           = "eu-west-1"
+)
 
 func checkValueString(x string, y string) (value string) {
 	if x == "" {
@@ -45,6 +64,15 @@ func checkValueArray(x []string, y []string) (value []string) {
 	return
 }
 
+func checkValueKeyValue(x []checkly.KeyValue) (value []checkly.KeyValue) {
+	if x == nil {
+		value = []checkly.KeyValue{}
+	} else {
+		value = x
+	}
+	return
+}
+
 func getTags(labels map[string]string) (tags []string) {
 
 	for k, v := range labels {
@@ -53,3 +81,89 @@ func getTags(labels map[string]string) (tags []string) {
 
 	return
 }
+
+// DefaultAlertSettings returns the alert settings used by checks and groups which don't configure their own
+func DefaultAlertSettings() checkly.AlertSettings {
+	return checkly.AlertSettings{
+		EscalationType: checkly.RunBased,
+		RunBasedEscalation: checkly.RunBasedEscalation{
+			FailedRunThreshold: 5,
+		},
+		TimeBasedEscalation: checkly.TimeBasedEscalation{
+			MinutesFailingThreshold: 5,
+		},
+		Reminders: checkly.Reminders{
+			Interval: 5,
+		},
+		SSLCertificates: checkly.SSLCertificates{
+			Enabled:        false,
+			AlertThreshold: 3,
+		},
+	}
+}
+
+// isNotFound determines if an error returned by an API call is a 404 response
+func isNotFound(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound
+}
+
+// sameStrings compares two string slices ignoring the order of the elements
+func sameStrings(x []string, y []string) bool {
+	if len(x) != len(y) {
+		return false
+	}
+	a := append([]string{}, x...)
+	b := append([]string{}, y...)
+	sort.Strings(a)
+	sort.Strings(b)
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// alertSettingsDrift returns the alert settings fields which differ between the desired and the remote state
+func alertSettingsDrift(desired checkly.AlertSettings, desiredGlobal bool, remote checkly.AlertSettings, remoteGlobal bool) (drift []string) {
+	if desiredGlobal != remoteGlobal {
+		drift = append(drift, "useGlobalAlertSettings")
+	}
+	// Checkly ignores the escalation settings when the global ones are used
+	if desiredGlobal {
+		return
+	}
+	if desired.EscalationType != remote.EscalationType ||
+		desired.RunBasedEscalation != remote.RunBasedEscalation ||
+		desired.TimeBasedEscalation != remote.TimeBasedEscalation ||
+		desired.Reminders != remote.Reminders ||
+		desired.SSLCertificates != remote.SSLCertificates {
+		drift = append(drift, "alertSettings")
+	}
+	return
+}
+
+// Labels of the checklyhq.com API call metrics
+const (
+	resourceCheck        = "check"
+	resourceGroup        = "group"
+	resourceAlertChannel = "alert_channel"
+
+	operationCreate = "create"
+	operationUpdate = "update"
+	operationDelete = "delete"
+	operationGet    = "get"
+	operationList   = "list"
+)
+
+// observeAPICall records the outcome and the duration of a checklyhq.com API call in the operator metrics
+func observeAPICall(resource string, operation string, start time.Time, err error) {
+	outcome := metrics.OutcomeSuccess
+	if isNotFound(err) {
+		outcome = metrics.OutcomeNotFound
+	} else if err != nil {
+		outcome = metrics.OutcomeError
+	}
+	metrics.ObserveAPICall(resource, operation, outcome, time.Since(start))
+}
@@ -17,7 +17,15 @@ limitations under the License.
 package external
 
 import (
+	"errors"
+	"net/http"
+	"reflect"
 	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/checkly/checkly-operator/internal/metrics"
 )
 
 func TestCheckValueString(t *testing.T) {
@@ -93,3 +101,49 @@ func TestGetTags(t *testing.T) {
 	}
 
 }
+
+func TestSameStrings(t *testing.T) {
+	if !sameStrings([]string{"foo", "bar"}, []string{"bar", "foo"}) {
+		t.Error("Expected true, got false")
+	}
+	if sameStrings([]string{"foo", "foo"}, []string{"foo", "bar"}) {
+		t.Error("Expected false, got true")
+	}
+}
+
+func TestAlertSettingsDrift(t *testing.T) {
+	desired := DefaultAlertSettings()
+	remote := desired
+	if drift := alertSettingsDrift(desired, false, remote, false); len(drift) != 0 {
+		t.Errorf("Expected no drift, got %v", drift)
+	}
+
+	remote.Reminders.Amount = 2
+	drift := alertSettingsDrift(desired, false, remote, true)
+	expectedDrift := []string{"useGlobalAlertSettings", "alertSettings"}
+	if !reflect.DeepEqual(drift, expectedDrift) {
+		t.Errorf("Expected %v, got %v", expectedDrift, drift)
+	}
+
+	// Escalation settings are ignored with global alert settings
+	if drift := alertSettingsDrift(desired, true, remote, true); len(drift) != 0 {
+		t.Errorf("Expected no drift, got %v", drift)
+	}
+}
+
+func TestObserveAPICall(t *testing.T) {
+	notFound := metrics.APICallsTotal.WithLabelValues(resourceGroup, operationGet, metrics.OutcomeNotFound)
+	failed := metrics.APICallsTotal.WithLabelValues(resourceGroup, operationGet, metrics.OutcomeError)
+	before := testutil.ToFloat64(notFound)
+
+	observeAPICall(resourceGroup, operationGet, time.Now(), &APIError{StatusCode: http.StatusNotFound, Err: errors.New(`unexpected response status 404: "{}"`)})
+	if value := testutil.ToFloat64(notFound); value != before+1 {
+		t.Errorf("Expected %f, got %f", before+1, value)
+	}
+
+	before = testutil.ToFloat64(failed)
+	observeAPICall(resourceGroup, operationGet, time.Now(), errors.New("HTTP request failed"))
+	if value := testutil.ToFloat64(failed); value != before+1 {
+		t.Errorf("Expected %f, got %f", before+1, value)
+	}
+}
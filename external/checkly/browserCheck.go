@@ -0,0 +1,120 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package external
+
+import (
+	"context"
+	"errors"
+
+	"github.com/checkly/checkly-go-sdk"
+)
+
+// BrowserCheck is a struct for the internal packages to help put together the checkly browser check
+type BrowserCheck struct {
+	Name      string
+	Namespace string
+	Frequency int
+	Script    string
+	GroupID   int64
+	ID        string
+	Muted     bool
+	Labels    map[string]string
+	// OwnerTag is added to the tags of the check so it can be found again with FindCheck, empty disables it
+	OwnerTag string
+}
+
+func checklyBrowserCheck(browserCheck BrowserCheck) (check checkly.Check, err error) {
+
+	if browserCheck.Script == "" {
+		err = errors.New("browser check script is empty")
+		return
+	}
+
+	tags := getTags(browserCheck.Labels)
+	tags = append(tags, "checkly-operator")
+	tags = append(tags, browserCheck.Namespace)
+	if browserCheck.OwnerTag != "" {
+		tags = append(tags, browserCheck.OwnerTag)
+	}
+
+	check = checkly.Check{
+		Name:                   browserCheck.Name,
+		Type:                   checkly.TypeBrowser,
+		Frequency:              checkValueInt(browserCheck.Frequency, DefaultBrowserCheckFrequency),
+		Activated:              true,
+		Muted:                  browserCheck.Muted,
+		ShouldFail:             false,
+		DoubleCheck:            false,
+		SSLCheck:               false,
+		Locations:              []string{},
+		Script:                 browserCheck.Script,
+		EnvironmentVariables:   []checkly.EnvironmentVariable{},
+		Tags:                   tags,
+		AlertSettings:          DefaultAlertSettings(),
+		UseGlobalAlertSettings: false,
+		GroupID:                browserCheck.GroupID,
+	}
+
+	return
+}
+
+// CreateBrowserCheck creates a new checklyhq.com browser check
+func CreateBrowserCheck(ctx context.Context, browserCheck BrowserCheck, client Client) (ID string, err error) {
+
+	check, err := checklyBrowserCheck(browserCheck)
+	if err != nil {
+		return
+	}
+
+	ctx, call := startAPICall(ctx, resourceCheck, operationCreate)
+	gotCheck, err := client.Create(ctx, check)
+	err = call.done(err)
+	if err != nil {
+		return
+	}
+
+	ID = gotCheck.ID
+
+	return
+}
+
+// UpdateBrowserCheck updates an existing checklyhq.com browser check
+func UpdateBrowserCheck(ctx context.Context, browserCheck BrowserCheck, client Client) (err error) {
+
+	check, err := checklyBrowserCheck(browserCheck)
+	if err != nil {
+		return
+	}
+
+	ctx, call := startAPICall(ctx, resourceCheck, operationUpdate)
+	_, err = client.Update(ctx, browserCheck.ID, check)
+	err = call.done(err)
+
+	return
+}
+
+// BrowserCheckDrift fetches the checklyhq.com browser check and returns the fields which differ from the desired state,
+// found is false if the check no longer exists in checkly
+func BrowserCheckDrift(ctx context.Context, browserCheck BrowserCheck, client Client) (drift []string, found bool, err error) {
+
+	check, err := checklyBrowserCheck(browserCheck)
+	if err != nil {
+		return
+	}
+
+	return remoteCheckDrift(ctx, browserCheck.ID, check, client)
+}
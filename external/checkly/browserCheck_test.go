@@ -0,0 +1,82 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package external
+
+import (
+	"testing"
+
+	"github.com/checkly/checkly-go-sdk"
+)
+
+func TestChecklyBrowserCheck(t *testing.T) {
+
+	data1 := BrowserCheck{
+		Name:      "foo",
+		Namespace: "bar",
+		Frequency: 15,
+		Script:    "console.log('foo')",
+		GroupID:   1,
+		Muted:     true,
+	}
+
+	testData, err := checklyBrowserCheck(data1)
+	if err != nil {
+		t.Errorf("Expected no error, got %e", err)
+	}
+
+	if testData.Type != checkly.TypeBrowser {
+		t.Errorf("Expected %s, got %s", checkly.TypeBrowser, testData.Type)
+	}
+
+	if testData.Script != data1.Script {
+		t.Errorf("Expected %s, got %s", data1.Script, testData.Script)
+	}
+
+	if testData.Frequency != data1.Frequency {
+		t.Errorf("Expected %d, got %d", data1.Frequency, testData.Frequency)
+	}
+
+	if testData.GroupID != data1.GroupID {
+		t.Errorf("Expected %d, got %d", data1.GroupID, testData.GroupID)
+	}
+
+	if testData.Muted != data1.Muted {
+		t.Errorf("Expected %t, got %t", data1.Muted, testData.Muted)
+	}
+
+	data2 := BrowserCheck{
+		Name:      "foo",
+		Namespace: "bar",
+		Script:    "console.log('foo')",
+	}
+
+	testData, _ = checklyBrowserCheck(data2)
+
+	if testData.Frequency != 10 {
+		t.Errorf("Expected %d, got %d", 10, testData.Frequency)
+	}
+
+	failData := BrowserCheck{
+		Name:      "fail",
+		Namespace: "bar",
+	}
+
+	_, err = checklyBrowserCheck(failData)
+	if err == nil {
+		t.Error("Expected error, got nil")
+	}
+}
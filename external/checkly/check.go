@@ -18,9 +18,10 @@ package external
 
 import (
 	"context"
-	"net/http"
+	"errors"
+	"fmt"
+	"slices"
 	"strconv"
-	"time"
 
 	"github.com/checkly/checkly-go-sdk"
 )
@@ -31,84 +32,95 @@ type Check struct {
 	Namespace       string
 	Frequency       int
 	MaxResponseTime int
+	Locations       []string
 	Endpoint        string
 	SuccessCode     string
 	GroupID         int64
 	ID              string
 	Muted           bool
 	Labels          map[string]string
+	Method          string
+	Headers         []checkly.KeyValue
+	QueryParameters []checkly.KeyValue
+	Body            string
+	BodyType        string
+	Assertions      []checkly.Assertion
+	// AlertSettings are sent as they are, DefaultAlertSettings are used if nil
+	AlertSettings          *checkly.AlertSettings
+	UseGlobalAlertSettings bool
+	// OwnerTag is added to the tags of the check so it can be found again with FindCheck, empty disables it
+	OwnerTag string
 }
 
 func checklyCheck(apiCheck Check) (check checkly.Check, err error) {
 
-	shouldFail, err := shouldFail(apiCheck.SuccessCode)
-	if err != nil {
+	if apiCheck.SuccessCode == "" && len(apiCheck.Assertions) == 0 {
+		err = errors.New("either a success code or assertions have to be set")
 		return
 	}
 
+	failing := assertionsShouldFail(apiCheck.Assertions)
+	assertions := []checkly.Assertion{}
+
+	if apiCheck.SuccessCode != "" {
+		var successCodeFailing bool
+		successCodeFailing, err = shouldFail(apiCheck.SuccessCode)
+		if err != nil {
+			return
+		}
+		// The success code and the assertions are checked together, either of them can expect an error status code
+		failing = failing || successCodeFailing
+
+		assertions = append(assertions, checkly.Assertion{
+			Source:     checkly.StatusCode,
+			Comparison: checkly.Equals,
+			Target:     apiCheck.SuccessCode,
+		})
+	}
+
+	for _, assertion := range apiCheck.Assertions {
+		assertion.Order = len(assertions)
+		assertions = append(assertions, assertion)
+	}
+
 	tags := getTags(apiCheck.Labels)
 	tags = append(tags, "checkly-operator")
 	tags = append(tags, apiCheck.Namespace)
+	if apiCheck.OwnerTag != "" {
+		tags = append(tags, apiCheck.OwnerTag)
+	}
 
-	alertSettings := checkly.AlertSettings{
-		EscalationType: checkly.RunBased,
-		RunBasedEscalation: checkly.RunBasedEscalation{
-			FailedRunThreshold: 5,
-		},
-		TimeBasedEscalation: checkly.TimeBasedEscalation{
-			MinutesFailingThreshold: 5,
-		},
-		Reminders: checkly.Reminders{
-			Interval: 5,
-		},
-		SSLCertificates: checkly.SSLCertificates{
-			Enabled:        false,
-			AlertThreshold: 3,
-		},
+	alertSettings := DefaultAlertSettings()
+	if apiCheck.AlertSettings != nil {
+		alertSettings = *apiCheck.AlertSettings
 	}
 
 	check = checkly.Check{
 		Name:                   apiCheck.Name,
 		Type:                   checkly.TypeAPI,
-		Frequency:              checkValueInt(apiCheck.Frequency, 5),
+		Frequency:              checkValueInt(apiCheck.Frequency, DefaultApiCheckFrequency),
 		DegradedResponseTime:   5000,
-		MaxResponseTime:        checkValueInt(apiCheck.MaxResponseTime, 15000),
+		MaxResponseTime:        checkValueInt(apiCheck.MaxResponseTime, DefaultApiCheckMaxResponseTime),
 		Activated:              true,
 		Muted:                  apiCheck.Muted, // muted for development
-		ShouldFail:             shouldFail,
+		ShouldFail:             failing,
 		DoubleCheck:            false,
 		SSLCheck:               false,
 		LocalSetupScript:       "",
 		LocalTearDownScript:    "",
-		Locations:              []string{},
+		Locations:              checkValueArray(apiCheck.Locations, []string{}),
 		Tags:                   tags,
 		AlertSettings:          alertSettings,
-		UseGlobalAlertSettings: false,
+		UseGlobalAlertSettings: apiCheck.UseGlobalAlertSettings,
 		GroupID:                apiCheck.GroupID,
 		Request: checkly.Request{
-			Method:  http.MethodGet,
-			URL:     apiCheck.Endpoint,
-			Headers: []checkly.KeyValue{
-				// {
-				// 	Key:   "X-Test",
-				// 	Value: "foo",
-				// },
-			},
-			QueryParameters: []checkly.KeyValue{
-				// {
-				// 	Key:   "query",
-				// 	Value: "foo",
-				// },
-			},
-			Assertions: []checkly.Assertion{
-				{
-					Source:     checkly.StatusCode,
-					Comparison: checkly.Equals,
-					Target:     apiCheck.SuccessCode,
-				},
-			},
-			Body:     "",
-			BodyType: "NONE",
+			Method:          checkValueString(apiCheck.Method, DefaultApiCheckMethod),
+			URL:             apiCheck.Endpoint,
+			Headers:         checkValueKeyValue(apiCheck.Headers),
+			QueryParameters: checkValueKeyValue(apiCheck.QueryParameters),
+			Assertions:      assertions,
+			Body:            apiCheck.Body,
+			BodyType:        checkValueString(apiCheck.BodyType, DefaultApiCheckBodyType),
 		},
 	}
 
@@ -116,17 +128,16 @@ func checklyCheck(apiCheck Check) (check checkly.Check, err error) {
 }
 
 // Create creates a new checklyhq.com check
-func Create(apiCheck Check, client checkly.Client) (ID string, err error) {
+func Create(ctx context.Context, apiCheck Check, client Client) (ID string, err error) {
 
 	check, err := checklyCheck(apiCheck)
 	if err != nil {
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
-	defer cancel()
-
+	ctx, call := startAPICall(ctx, resourceCheck, operationCreate)
 	gotCheck, err := client.Create(ctx, check)
+	err = call.done(err)
 	if err != nil {
 		return
 	}
@@ -137,28 +148,176 @@ func Create(apiCheck Check, client checkly.Client) (ID string, err error) {
 }
 
 // Update updates an existing checklyhq.com check
-func Update(apiCheck Check, client checkly.Client) (err error) {
+func Update(ctx context.Context, apiCheck Check, client Client) (err error) {
 
 	check, err := checklyCheck(apiCheck)
 	if err != nil {
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
-	defer cancel()
-
+	ctx, call := startAPICall(ctx, resourceCheck, operationUpdate)
 	_, err = client.Update(ctx, apiCheck.ID, check)
+	err = call.done(err)
 
 	return
 }
 
-// Delete deletes an existing checklyhq.com check
-func Delete(ID string, client checkly.Client) (err error) {
-
-	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
-	defer cancel()
+// Delete deletes an existing checklyhq.com check, a check which no longer exists in checklyhq.com isn't an error
+func Delete(ctx context.Context, ID string, client Client) (err error) {
 
+	ctx, call := startAPICall(ctx, resourceCheck, operationDelete)
 	err = client.Delete(ctx, ID)
+	err = call.done(err)
+	if isNotFound(err) {
+		err = nil
+	}
+
+	return
+}
+
+// TagCheck adds the tag to an existing checklyhq.com check, ex. to mark a check which is kept after its resource was deleted.
+// A check which no longer exists in checklyhq.com isn't an error.
+func TagCheck(ctx context.Context, ID string, tag string, client Client) (err error) {
+
+	getCtx, call := startAPICall(ctx, resourceCheck, operationGet)
+	check, err := client.Get(getCtx, ID)
+	err = call.done(err)
+	if err != nil {
+		if isNotFound(err) {
+			err = nil
+		}
+		return
+	}
+
+	if slices.Contains(check.Tags, tag) {
+		return
+	}
+	check.Tags = append(check.Tags, tag)
+
+	updateCtx, call := startAPICall(ctx, resourceCheck, operationUpdate)
+	_, err = client.Update(updateCtx, ID, *check)
+	err = call.done(err)
+
+	return
+}
+
+// FindCheck returns the ID of the checklyhq.com check with the tag, ex. the ownership tag of a resource which lost its status,
+// ID is empty if there's no such check. It fails if the client can't list checks, the lookup is what prevents duplicates.
+func FindCheck(ctx context.Context, tag string, client Client) (ID string, err error) {
+	lister, ok := client.(Lister)
+	if !ok {
+		err = fmt.Errorf("the checklyhq.com client can't list checks, the check with the tag %s can't be looked up", tag)
+		return
+	}
+
+	checks, err := lister.ListChecks(ctx)
+	if err != nil {
+		return
+	}
+
+	for _, check := range checks {
+		if slices.Contains(check.Tags, tag) {
+			ID = check.ID
+			return
+		}
+	}
+
+	return
+}
+
+// CheckExists determines if a check with the given ID exists in checklyhq.com, used when adopting existing checks
+func CheckExists(ctx context.Context, ID string, client Client) (found bool, err error) {
+
+	ctx, call := startAPICall(ctx, resourceCheck, operationGet)
+	_, err = client.Get(ctx, ID)
+	err = call.done(err)
+	if err != nil {
+		if isNotFound(err) {
+			err = nil
+		}
+		return
+	}
+
+	found = true
+
+	return
+}
+
+// CheckDrift fetches the checklyhq.com check and returns the fields which differ from the desired state,
+// found is false if the check no longer exists in checkly
+func CheckDrift(ctx context.Context, apiCheck Check, client Client) (drift []string, found bool, err error) {
+
+	check, err := checklyCheck(apiCheck)
+	if err != nil {
+		return
+	}
+
+	return remoteCheckDrift(ctx, apiCheck.ID, check, client)
+}
+
+func remoteCheckDrift(ctx context.Context, ID string, check checkly.Check, client Client) (drift []string, found bool, err error) {
+
+	ctx, call := startAPICall(ctx, resourceCheck, operationGet)
+	gotCheck, err := client.Get(ctx, ID)
+	err = call.done(err)
+	if err != nil {
+		if isNotFound(err) {
+			err = nil
+		}
+		return
+	}
+
+	found = true
+	drift = checkDrift(check, *gotCheck)
+
+	return
+}
+
+// checkDrift returns the fields of the remote check which differ from the desired check
+func checkDrift(desired checkly.Check, remote checkly.Check) (drift []string) {
+	if desired.Name != remote.Name {
+		drift = append(drift, "name")
+	}
+	if desired.Activated != remote.Activated {
+		drift = append(drift, "activated")
+	}
+	if desired.Muted != remote.Muted {
+		drift = append(drift, "muted")
+	}
+	if desired.Frequency != remote.Frequency {
+		drift = append(drift, "frequency")
+	}
+	if desired.GroupID != remote.GroupID {
+		drift = append(drift, "groupId")
+	}
+	if !sameStrings(desired.Tags, remote.Tags) {
+		drift = append(drift, "tags")
+	}
+
+	switch desired.Type {
+	case checkly.TypeAPI:
+		if desired.MaxResponseTime != remote.MaxResponseTime {
+			drift = append(drift, "maxResponseTime")
+		}
+		if desired.Request.Method != remote.Request.Method {
+			drift = append(drift, "method")
+		}
+		if desired.Request.URL != remote.Request.URL {
+			drift = append(drift, "url")
+		}
+		if desired.Request.Body != remote.Request.Body || desired.Request.BodyType != remote.Request.BodyType {
+			drift = append(drift, "body")
+		}
+		if !sameStrings(desired.Locations, remote.Locations) {
+			drift = append(drift, "locations")
+		}
+	case checkly.TypeBrowser:
+		if desired.Script != remote.Script {
+			drift = append(drift, "script")
+		}
+	}
+
+	drift = append(drift, alertSettingsDrift(desired.AlertSettings, desired.UseGlobalAlertSettings, remote.AlertSettings, remote.UseGlobalAlertSettings)...)
 
 	return
 }
@@ -174,3 +333,27 @@ func shouldFail(successCode string) (bool, error) {
 		return true, nil
 	}
 }
+
+// assertionsShouldFail determines if the check expects an error status code (>= 400) based on the status code assertions
+func assertionsShouldFail(assertions []checkly.Assertion) bool {
+	for _, assertion := range assertions {
+		if assertion.Source != checkly.StatusCode {
+			continue
+		}
+		if assertion.Comparison != checkly.Equals && assertion.Comparison != checkly.GreaterThan {
+			continue
+		}
+		code, err := strconv.Atoi(assertion.Target)
+		if err != nil {
+			continue
+		}
+		// GREATER_THAN 399 expects the same error status codes as GREATER_THAN 400 does with >=
+		if assertion.Comparison == checkly.GreaterThan {
+			code++
+		}
+		if code >= 400 {
+			return true
+		}
+	}
+	return false
+}
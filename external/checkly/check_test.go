@@ -17,11 +17,15 @@ limitations under the License.
 package external
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	"github.com/checkly/checkly-go-sdk"
+
+	"github.com/checkly/checkly-operator/external/checkly/fake"
 )
 
 func TestChecklyCheck(t *testing.T) {
@@ -34,6 +38,7 @@ func TestChecklyCheck(t *testing.T) {
 		Endpoint:        "https://foo.bar/baz",
 		SuccessCode:     "403",
 		Muted:           true,
+		Locations:       []string{"eu-west-1"},
 	}
 
 	testData, _ := checklyCheck(data1)
@@ -58,6 +63,10 @@ func TestChecklyCheck(t *testing.T) {
 		t.Errorf("Expected %t, got %t", true, testData.ShouldFail)
 	}
 
+	if !sameStrings(testData.Locations, data1.Locations) {
+		t.Errorf("Expected %v, got %v", data1.Locations, testData.Locations)
+	}
+
 	data2 := Check{
 		Name:        "foo",
 		Namespace:   "bar",
@@ -79,6 +88,125 @@ func TestChecklyCheck(t *testing.T) {
 		t.Errorf("Expected %t, got %t", false, testData.ShouldFail)
 	}
 
+	if testData.Request.Method != http.MethodGet {
+		t.Errorf("Expected %s, got %s", http.MethodGet, testData.Request.Method)
+	}
+
+	if testData.Request.BodyType != "NONE" {
+		t.Errorf("Expected %s, got %s", "NONE", testData.Request.BodyType)
+	}
+
+	if testData.Request.Headers == nil || testData.Request.QueryParameters == nil {
+		t.Error("Expected empty headers and query parameters, got nil")
+	}
+
+	if testData.Locations == nil || len(testData.Locations) != 0 {
+		t.Errorf("Expected empty locations, got %v", testData.Locations)
+	}
+
+	data3 := Check{
+		Name:        "foo",
+		Namespace:   "bar",
+		Endpoint:    "https://foo.bar/graphql",
+		SuccessCode: "200",
+		Method:      http.MethodPost,
+		Headers: []checkly.KeyValue{
+			{
+				Key:    "Authorization",
+				Value:  "Bearer foo",
+				Locked: true,
+			},
+		},
+		QueryParameters: []checkly.KeyValue{
+			{
+				Key:   "query",
+				Value: "foo",
+			},
+		},
+		Body:     `{"query": "{ health }"}`,
+		BodyType: "GRAPHQL",
+	}
+
+	testData, _ = checklyCheck(data3)
+
+	if testData.Request.Method != data3.Method {
+		t.Errorf("Expected %s, got %s", data3.Method, testData.Request.Method)
+	}
+
+	if len(testData.Request.Headers) != 1 || testData.Request.Headers[0].Value != "Bearer foo" {
+		t.Errorf("Expected headers %v, got %v", data3.Headers, testData.Request.Headers)
+	}
+
+	if len(testData.Request.QueryParameters) != 1 || testData.Request.QueryParameters[0].Key != "query" {
+		t.Errorf("Expected query parameters %v, got %v", data3.QueryParameters, testData.Request.QueryParameters)
+	}
+
+	if testData.Request.Body != data3.Body {
+		t.Errorf("Expected %s, got %s", data3.Body, testData.Request.Body)
+	}
+
+	if testData.Request.BodyType != data3.BodyType {
+		t.Errorf("Expected %s, got %s", data3.BodyType, testData.Request.BodyType)
+	}
+
+	data4 := Check{
+		Name:      "foo",
+		Namespace: "bar",
+		Endpoint:  "https://foo.bar/health",
+		Assertions: []checkly.Assertion{
+			{
+				Source:     checkly.StatusCode,
+				Comparison: checkly.LessThan,
+				Target:     "400",
+			},
+			{
+				Source:     checkly.JSONBody,
+				Property:   "$.status",
+				Comparison: checkly.Equals,
+				Target:     "ok",
+			},
+		},
+	}
+
+	testData, err := checklyCheck(data4)
+	if err != nil {
+		t.Errorf("Expected no error, got %e", err)
+	}
+
+	if len(testData.Request.Assertions) != 2 {
+		t.Errorf("Expected %d assertions, got %d", 2, len(testData.Request.Assertions))
+	}
+
+	if testData.Request.Assertions[1].Order != 1 || testData.Request.Assertions[1].Property != "$.status" {
+		t.Errorf("Expected JSON body assertion with order 1, got %v", testData.Request.Assertions[1])
+	}
+
+	if testData.ShouldFail != false {
+		t.Errorf("Expected %t, got %t", false, testData.ShouldFail)
+	}
+
+	data4.SuccessCode = "200"
+	testData, _ = checklyCheck(data4)
+
+	if len(testData.Request.Assertions) != 3 {
+		t.Errorf("Expected %d assertions, got %d", 3, len(testData.Request.Assertions))
+	}
+
+	if testData.Request.Assertions[0].Source != checkly.StatusCode || testData.Request.Assertions[0].Target != "200" {
+		t.Errorf("Expected success code assertion first, got %v", testData.Request.Assertions[0])
+	}
+
+	emptyData := Check{
+		Name:      "empty",
+		Namespace: "bar",
+		Endpoint:  "https://foo.bar/baz",
+	}
+
+	_, err = checklyCheck(emptyData)
+	if err == nil {
+		t.Error("Expected error, got nil")
+	}
+
 	failData := Check{
 		Name:        "fail",
 		Namespace:   "bar",
@@ -86,7 +214,7 @@ func TestChecklyCheck(t *testing.T) {
 		SuccessCode: "foo",
 	}
 
-	_, err := checklyCheck(failData)
+	_, err = checklyCheck(failData)
 	if err == nil {
 		t.Error("Expected error, got nil")
 	}
@@ -116,19 +244,19 @@ func TestChecklyCheckActions(t *testing.T) {
 		nil,
 	)
 	// Create
-	_, err := Create(testData, testClientFail)
+	_, err := Create(context.Background(), testData, testClientFail)
 	if err == nil {
 		t.Error("Expected error, got none")
 	}
 
 	// Update
-	err = Update(testData, testClientFail)
+	err = Update(context.Background(), testData, testClientFail)
 	if err == nil {
 		t.Error("Expected error, got none")
 	}
 
 	// Delete
-	err = Delete(expectedCheckID, testClientFail)
+	err = Delete(context.Background(), expectedCheckID, testClientFail)
 	if err == nil {
 		t.Error("Expected error, got none")
 	}
@@ -196,7 +324,7 @@ func TestChecklyCheckActions(t *testing.T) {
 		http.ListenAndServe(":5555", nil)
 	}()
 
-	testID, err := Create(testData, testClient)
+	testID, err := Create(context.Background(), testData, testClient)
 	if err != nil {
 		t.Errorf("Expected no error, got %e", err)
 	}
@@ -207,12 +335,12 @@ func TestChecklyCheckActions(t *testing.T) {
 
 	testData.ID = expectedCheckID
 
-	err = Update(testData, testClient)
+	err = Update(context.Background(), testData, testClient)
 	if err != nil {
 		t.Errorf("Expected no error, got %e", err)
 	}
 
-	err = Delete(expectedCheckID, testClient)
+	err = Delete(context.Background(), expectedCheckID, testClient)
 	if err != nil {
 		t.Errorf("Expected no error, got %e", err)
 	}
@@ -220,6 +348,85 @@ func TestChecklyCheckActions(t *testing.T) {
 	return
 }
 
+func TestAssertionsShouldFail(t *testing.T) {
+	if assertionsShouldFail([]checkly.Assertion{}) {
+		t.Error("Expected false, got true")
+	}
+
+	testTrue := []checkly.Assertion{
+		{
+			Source:     checkly.StatusCode,
+			Comparison: checkly.Equals,
+			Target:     "503",
+		},
+	}
+	if !assertionsShouldFail(testTrue) {
+		t.Error("Expected true, got false")
+	}
+
+	testFalse := []checkly.Assertion{
+		{
+			Source:     checkly.StatusCode,
+			Comparison: checkly.LessThan,
+			Target:     "500",
+		},
+		{
+			Source:     checkly.ResponseTime,
+			Comparison: checkly.GreaterThan,
+			Target:     "1000",
+		},
+	}
+	if assertionsShouldFail(testFalse) {
+		t.Error("Expected false, got true")
+	}
+
+	testCases := []struct {
+		comparison string
+		target     string
+		expected   bool
+	}{
+		{checkly.Equals, "404", true},
+		{checkly.Equals, "399", false},
+		{checkly.GreaterThan, "399", true},
+		{checkly.GreaterThan, "398", false},
+		{checkly.GreaterThan, "500", true},
+		{checkly.LessThan, "500", false},
+		{checkly.Equals, "foo", false},
+	}
+	for _, tc := range testCases {
+		assertions := []checkly.Assertion{{Source: checkly.StatusCode, Comparison: tc.comparison, Target: tc.target}}
+		if got := assertionsShouldFail(assertions); got != tc.expected {
+			t.Errorf("%s %s: expected %t, got %t", tc.comparison, tc.target, tc.expected, got)
+		}
+	}
+}
+
+func TestChecklyCheckShouldFail(t *testing.T) {
+	testCases := []struct {
+		name        string
+		successCode string
+		assertions  []checkly.Assertion
+		expected    bool
+	}{
+		{"success code", "200", nil, false},
+		{"error success code", "404", nil, true},
+		{"error assertion", "", []checkly.Assertion{{Source: checkly.StatusCode, Comparison: checkly.Equals, Target: "404"}}, true},
+		{"success code and error assertion", "200", []checkly.Assertion{{Source: checkly.StatusCode, Comparison: checkly.Equals, Target: "404"}}, true},
+		{"error success code and assertion", "503", []checkly.Assertion{{Source: checkly.StatusCode, Comparison: checkly.LessThan, Target: "600"}}, true},
+		{"success code and greater than assertion", "200", []checkly.Assertion{{Source: checkly.StatusCode, Comparison: checkly.GreaterThan, Target: "399"}}, true},
+	}
+
+	for _, tc := range testCases {
+		check, err := checklyCheck(Check{Name: "foo", Endpoint: "https://foo.bar", SuccessCode: tc.successCode, Assertions: tc.assertions})
+		if err != nil {
+			t.Fatalf("%s: expected no error, got %s", tc.name, err)
+		}
+		if check.ShouldFail != tc.expected {
+			t.Errorf("%s: expected %t, got %t", tc.name, tc.expected, check.ShouldFail)
+		}
+	}
+}
+
 func TestShouldFail(t *testing.T) {
 	testTrue := "401"
 	testFalse := "200"
@@ -247,3 +454,169 @@ func TestShouldFail(t *testing.T) {
 	}
 
 }
+
+func TestCheckDrift(t *testing.T) {
+	desired, err := checklyCheck(Check{
+		Name:        "foo",
+		Namespace:   "bar",
+		Endpoint:    "https://foo.bar/baz",
+		SuccessCode: "200",
+		GroupID:     1,
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %e", err)
+	}
+
+	remote := desired
+	remote.Tags = []string{desired.Tags[1], desired.Tags[0]}
+	if drift := checkDrift(desired, remote); len(drift) != 0 {
+		t.Errorf("Expected no drift, got %v", drift)
+	}
+
+	remote.Frequency = 60
+	remote.Request.URL = "https://foo.bar/qux"
+	remote.Locations = []string{"eu-west-1"}
+	remote.AlertSettings.EscalationType = checkly.TimeBased
+	drift := checkDrift(desired, remote)
+	expectedDrift := []string{"frequency", "url", "locations", "alertSettings"}
+	if !sameStrings(drift, expectedDrift) {
+		t.Errorf("Expected %v, got %v", expectedDrift, drift)
+	}
+
+	// Found and not found
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/checks/2" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		jsonResp, _ := json.Marshal(remote)
+		w.Write(jsonResp)
+	}))
+	defer server.Close()
+
+	testClient := checkly.NewClient(server.URL, "foobarbaz", nil, nil)
+
+	testData := Check{
+		Name:        "foo",
+		Namespace:   "bar",
+		Endpoint:    "https://foo.bar/baz",
+		SuccessCode: "200",
+		GroupID:     1,
+		ID:          "2",
+	}
+	drift, found, err := CheckDrift(context.Background(), testData, testClient)
+	if err != nil {
+		t.Errorf("Expected no error, got %e", err)
+	}
+	if !found {
+		t.Error("Expected the check to be found")
+	}
+	if !sameStrings(drift, expectedDrift) {
+		t.Errorf("Expected %v, got %v", expectedDrift, drift)
+	}
+
+	testData.ID = "3"
+	_, found, err = CheckDrift(context.Background(), testData, testClient)
+	if err != nil {
+		t.Errorf("Expected no error, got %e", err)
+	}
+	if found {
+		t.Error("Expected the check not to be found")
+	}
+}
+
+func TestCheckExists(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/checks/2":
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"id":"2"}`))
+		case "/v1/checks/3":
+			w.WriteHeader(http.StatusNotFound)
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+
+	testClient := checkly.NewClient(server.URL, "foobarbaz", nil, nil)
+
+	found, err := CheckExists(context.Background(), "2", testClient)
+	if err != nil || !found {
+		t.Errorf("Expected the check to be found, got %t, %v", found, err)
+	}
+
+	found, err = CheckExists(context.Background(), "3", testClient)
+	if err != nil || found {
+		t.Errorf("Expected the check not to be found, got %t, %v", found, err)
+	}
+
+	_, err = CheckExists(context.Background(), "4", testClient)
+	if err == nil {
+		t.Error("Expected error, got none")
+	}
+}
+
+func TestTagCheck(t *testing.T) {
+	ctx := context.Background()
+	client := fake.NewClient()
+	ID := client.SetCheck(checkly.Check{Name: "foo", Tags: []string{"environment:dev"}})
+
+	for i := 0; i < 2; i++ {
+		if err := TagCheck(ctx, ID, "retained", client); err != nil {
+			t.Fatalf("Expected no error, got %e", err)
+		}
+	}
+	if tags := client.Checks()[ID].Tags; !sameStrings(tags, []string{"environment:dev", "retained"}) {
+		t.Errorf("Expected %v, got %v", []string{"environment:dev", "retained"}, tags)
+	}
+	if count := client.CallCount(fake.MethodUpdate); count != 1 {
+		t.Errorf("Expected %d update, got %d", 1, count)
+	}
+
+	// Nothing to tag
+	client.RemoveCheck(ID)
+	if err := TagCheck(ctx, ID, "retained", client); err != nil {
+		t.Errorf("Expected no error, got %e", err)
+	}
+}
+
+func TestFindCheck(t *testing.T) {
+	ctx := context.Background()
+	client := fake.NewClient()
+	client.SetCheck(checkly.Check{Name: "foo", Tags: []string{"checkly-operator"}})
+	ID := client.SetCheck(checkly.Check{Name: "bar", Tags: []string{"checkly-operator", "owner:foo"}})
+
+	foundID, err := FindCheck(ctx, "owner:foo", client)
+	if err != nil {
+		t.Fatalf("Expected no error, got %e", err)
+	}
+	if foundID != ID {
+		t.Errorf("Expected %s, got %s", ID, foundID)
+	}
+
+	foundID, err = FindCheck(ctx, "owner:bar", client)
+	if err != nil || foundID != "" {
+		t.Errorf("Expected no check and no error, got %s and %v", foundID, err)
+	}
+
+	client.FailNext(fake.MethodListChecks, fake.StatusError(http.StatusInternalServerError))
+	if _, err := FindCheck(ctx, "owner:foo", client); err == nil {
+		t.Error("Expected error, got none")
+	}
+}
+
+func TestDeleteNotFound(t *testing.T) {
+	ctx := context.Background()
+	client := fake.NewClient()
+
+	if err := Delete(ctx, "404", client); err != nil {
+		t.Errorf("Expected no error, got %e", err)
+	}
+
+	client.FailNext(fake.MethodDelete, fake.StatusError(http.StatusInternalServerError))
+	if err := Delete(ctx, "500", client); err == nil {
+		t.Error("Expected error, got none")
+	}
+}
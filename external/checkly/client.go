@@ -0,0 +1,193 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package external
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"time"
+
+	"github.com/checkly/checkly-go-sdk"
+	"golang.org/x/time/rate"
+)
+
+// DefaultBaseURL is the address of the public checklyhq.com API
+const DefaultBaseURL = "https://api.checklyhq.com"
+
+// RequestTimeout is how long a single checklyhq.com API call may take before it's cancelled
+var RequestTimeout = 5 * time.Second
+
+// Client holds the checklyhq.com API operations used by the operator, it's satisfied by checkly.Client and by the
+// in-memory client of the fake package used in tests
+type Client interface {
+	Create(ctx context.Context, check checkly.Check) (*checkly.Check, error)
+	Update(ctx context.Context, ID string, check checkly.Check) (*checkly.Check, error)
+	Delete(ctx context.Context, ID string) error
+	Get(ctx context.Context, ID string) (*checkly.Check, error)
+
+	CreateGroup(ctx context.Context, group checkly.Group) (*checkly.Group, error)
+	UpdateGroup(ctx context.Context, ID int64, group checkly.Group) (*checkly.Group, error)
+	DeleteGroup(ctx context.Context, ID int64) error
+	GetGroup(ctx context.Context, ID int64) (*checkly.Group, error)
+
+	CreateAlertChannel(ctx context.Context, ac checkly.AlertChannel) (*checkly.AlertChannel, error)
+	UpdateAlertChannel(ctx context.Context, ID int64, ac checkly.AlertChannel) (*checkly.AlertChannel, error)
+	DeleteAlertChannel(ctx context.Context, ID int64) error
+	GetAlertChannel(ctx context.Context, ID int64) (*checkly.AlertChannel, error)
+}
+
+var _ Client = checkly.Client(nil)
+
+// ClientOptions configure how the operator talks to the checklyhq.com API
+type ClientOptions struct {
+	// BaseURL is the address of the checklyhq.com API, DefaultBaseURL if empty
+	BaseURL string
+	// ProxyURL is the HTTP proxy the requests are sent through, the HTTPS_PROXY and NO_PROXY environment variables are used if empty
+	ProxyURL string
+	// CABundle is the path of a PEM file with CA certificates trusted besides the system ones, ex. the CA of an intercepting proxy
+	CABundle string
+	// Debug receives the requests and responses, secrets are redacted, nil disables it
+	Debug io.Writer
+	// RateLimit is the number of requests per second sent with a client, zero disables the limit
+	RateLimit float64
+	// RateBurst is the number of requests sent at once before the RateLimit applies
+	RateBurst int
+	// MaxRetries is how often rate limited requests and server errors are retried
+	MaxRetries int
+}
+
+// ClientFactory builds checkly clients which share the HTTP transport and the debug output,
+// every client has its own rate limit as checklyhq.com limits the requests per account
+type ClientFactory struct {
+	baseURL    string
+	transport  http.RoundTripper
+	debug      io.Writer
+	rateLimit  rate.Limit
+	rateBurst  int
+	maxRetries int
+}
+
+// NewClientFactory validates the options and builds the HTTP client used by every checkly client
+func NewClientFactory(opts ClientOptions) (*ClientFactory, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+
+	if opts.ProxyURL != "" {
+		proxyURL, err := url.Parse(opts.ProxyURL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy URL %s: %w", opts.ProxyURL, err)
+		}
+		transport.Proxy = http.ProxyURL(proxyURL)
+	}
+
+	if opts.CABundle != "" {
+		pem, err := os.ReadFile(opts.CABundle)
+		if err != nil {
+			return nil, fmt.Errorf("can't read CA bundle: %w", err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in CA bundle %s", opts.CABundle)
+		}
+		transport.TLSClientConfig = &tls.Config{
+			MinVersion: tls.VersionTLS12,
+			RootCAs:    pool,
+		}
+	}
+
+	factory := &ClientFactory{
+		baseURL:    opts.BaseURL,
+		transport:  transport,
+		rateLimit:  rate.Limit(opts.RateLimit),
+		rateBurst:  opts.RateBurst,
+		maxRetries: opts.MaxRetries,
+	}
+	if factory.baseURL == "" {
+		factory.baseURL = DefaultBaseURL
+	}
+	if factory.rateLimit == 0 {
+		factory.rateLimit = rate.Inf
+	}
+	if opts.Debug != nil {
+		factory.debug = NewRedactingWriter(opts.Debug)
+	}
+
+	return factory, nil
+}
+
+// NewClient returns a checkly client for the account
+func (f *ClientFactory) NewClient(apiKey string, accountID string) Client {
+	httpClient := &http.Client{
+		Transport: &retryTransport{
+			next:       f.transport,
+			limiter:    rate.NewLimiter(f.rateLimit, f.rateBurst),
+			maxRetries: f.maxRetries,
+		},
+	}
+	client := checkly.NewClient(f.baseURL, apiKey, httpClient, f.debug)
+	client.SetAccountId(accountID)
+	return &accountClient{
+		Client:     client,
+		httpClient: httpClient,
+		baseURL:    f.baseURL,
+		apiKey:     apiKey,
+		accountID:  accountID,
+		debug:      f.debug,
+	}
+}
+
+// redactions match the credentials in the request and response dumps, the match is replaced with the replacement
+var redactions = []struct {
+	pattern     *regexp.Regexp
+	replacement []byte
+}{
+	{regexp.MustCompile(`(?im)^(authorization:\s*)[^\r\n]*`), []byte(`${1}[REDACTED]`)},
+	{regexp.MustCompile(`("(?:apiKey|serviceKey|webhookSecret|webhookURL|url|value)"\s*:\s*)"(?:[^"\\]|\\.)*"`), []byte(`${1}"[REDACTED]"`)},
+}
+
+type redactingWriter struct {
+	w io.Writer
+}
+
+// NewRedactingWriter returns a writer which hides the API key and the alert channel credentials before writing to w,
+// every write has to hold a complete request or response dump
+func NewRedactingWriter(w io.Writer) io.Writer {
+	return &redactingWriter{w: w}
+}
+
+func (r *redactingWriter) Write(p []byte) (int, error) {
+	redacted := p
+	for _, redaction := range redactions {
+		redacted = redaction.pattern.ReplaceAll(redacted, redaction.replacement)
+	}
+
+	_, err := r.w.Write(redacted)
+	if err != nil {
+		return 0, err
+	}
+
+	return len(p), nil
+}
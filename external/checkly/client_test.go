@@ -0,0 +1,116 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package external
+
+import (
+	"bytes"
+	"context"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/checkly/checkly-operator/external/checkly/fake"
+)
+
+var _ Client = &fake.Client{}
+
+func TestClientFactory(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/checks/foo" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id":"foo","name":"bar","request":{"url":"https://secret.example.com"}}`))
+	}))
+	defer server.Close()
+
+	// The certificate of the test server is not trusted without the CA bundle
+	factory, err := NewClientFactory(ClientOptions{BaseURL: server.URL})
+	if err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+	_, err = factory.NewClient("foobarbaz", "1234").Get(context.Background(), "foo")
+	if err == nil {
+		t.Error("Expected certificate error, got none")
+	}
+
+	caBundle := filepath.Join(t.TempDir(), "ca.pem")
+	certificate := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	if err := os.WriteFile(caBundle, certificate, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var debug bytes.Buffer
+	factory, err = NewClientFactory(ClientOptions{BaseURL: server.URL, CABundle: caBundle, Debug: &debug})
+	if err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+	check, err := factory.NewClient("foobarbaz", "1234").Get(context.Background(), "foo")
+	if err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+	if check.Name != "bar" {
+		t.Errorf("Expected %s, got %s", "bar", check.Name)
+	}
+
+	if !strings.Contains(debug.String(), "/v1/checks/foo") {
+		t.Errorf("Expected the request in the debug output, got %s", debug.String())
+	}
+	if strings.Contains(debug.String(), "foobarbaz") || strings.Contains(debug.String(), "secret.example.com") {
+		t.Errorf("Expected credentials to be redacted, got %s", debug.String())
+	}
+
+	emptyBundle := filepath.Join(t.TempDir(), "empty.pem")
+	if err := os.WriteFile(emptyBundle, []byte("foo"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	_, err = NewClientFactory(ClientOptions{CABundle: emptyBundle})
+	if err == nil {
+		t.Error("Expected error for a CA bundle without certificates, got none")
+	}
+
+	_, err = NewClientFactory(ClientOptions{ProxyURL: "://foo"})
+	if err == nil {
+		t.Error("Expected error for an invalid proxy URL, got none")
+	}
+}
+
+func TestRedactingWriter(t *testing.T) {
+	var out bytes.Buffer
+	w := NewRedactingWriter(&out)
+
+	dump := "POST /v1/alert-channels HTTP/1.1\r\nAuthorization: Bearer foobarbaz\r\nX-Checkly-Account: 1234\r\n\r\n" +
+		`{"type":"OPSGENIE","config":{"apiKey":"foo\"bar","region":"EU"},"webhook":{"url":"https://hooks.slack.com/baz"}}`
+	n, err := w.Write([]byte(dump))
+	if err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+	if n != len(dump) {
+		t.Errorf("Expected %d, got %d", len(dump), n)
+	}
+
+	expected := "POST /v1/alert-channels HTTP/1.1\r\nAuthorization: [REDACTED]\r\nX-Checkly-Account: 1234\r\n\r\n" +
+		`{"type":"OPSGENIE","config":{"apiKey":"[REDACTED]","region":"EU"},"webhook":{"url":"[REDACTED]"}}`
+	if out.String() != expected {
+		t.Errorf("Expected %q, got %q", expected, out.String())
+	}
+}
@@ -0,0 +1,437 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package fake provides an in-memory checklyhq.com client for tests, it implements the Client and Lister interfaces of the
+// external package without any network calls.
+package fake
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+
+	"github.com/checkly/checkly-go-sdk"
+)
+
+// Names of the client methods, used to inject failures and in the recorded calls
+const (
+	MethodCreate             = "Create"
+	MethodUpdate             = "Update"
+	MethodDelete             = "Delete"
+	MethodGet                = "Get"
+	MethodCreateGroup        = "CreateGroup"
+	MethodUpdateGroup        = "UpdateGroup"
+	MethodDeleteGroup        = "DeleteGroup"
+	MethodGetGroup           = "GetGroup"
+	MethodCreateAlertChannel = "CreateAlertChannel"
+	MethodUpdateAlertChannel = "UpdateAlertChannel"
+	MethodDeleteAlertChannel = "DeleteAlertChannel"
+	MethodGetAlertChannel    = "GetAlertChannel"
+	MethodListChecks         = "ListChecks"
+	MethodListGroups         = "ListGroups"
+)
+
+// Call is a recorded call of the client, ID is empty for creations
+type Call struct {
+	Method string
+	ID     string
+}
+
+// Client stores checks, groups and alert channels in memory and assigns increasing IDs to them, one counter is shared
+// by all resource types. It's safe for concurrent use, so it can be shared by the reconcilers of an envtest suite.
+// Stored resources are shallow copies, don't modify the slices of a resource after handing it to the client.
+type Client struct {
+	mu            sync.Mutex
+	lastID        int64
+	checks        map[string]checkly.Check
+	groups        map[int64]checkly.Group
+	alertChannels map[int64]checkly.AlertChannel
+	failNext      map[string][]error
+	failAll       map[string]error
+	calls         []Call
+}
+
+// NewClient returns an empty client
+func NewClient() *Client {
+	return &Client{
+		checks:        map[string]checkly.Check{},
+		groups:        map[int64]checkly.Group{},
+		alertChannels: map[int64]checkly.AlertChannel{},
+		failNext:      map[string][]error{},
+		failAll:       map[string]error{},
+	}
+}
+
+// StatusError returns the error the checkly client returns for an unexpected response status,
+// ex. StatusError(http.StatusTooManyRequests) to simulate rate limiting
+func StatusError(status int) error {
+	return fmt.Errorf("unexpected response status %d: %q", status, http.StatusText(status))
+}
+
+// FailNext makes the next call of the method return err, failures of the same method are returned in the order they
+// were added
+func (c *Client) FailNext(method string, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.failNext[method] = append(c.failNext[method], err)
+}
+
+// FailAll makes every call of the method return err until ClearFailures is called
+func (c *Client) FailAll(method string, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.failAll[method] = err
+}
+
+// ClearFailures removes all injected failures
+func (c *Client) ClearFailures() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.failNext = map[string][]error{}
+	c.failAll = map[string]error{}
+}
+
+// Calls returns the calls made so far, in order
+func (c *Client) Calls() []Call {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]Call{}, c.calls...)
+}
+
+// CallCount returns how often the method was called
+func (c *Client) CallCount(method string) (count int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, call := range c.calls {
+		if call.Method == method {
+			count++
+		}
+	}
+	return
+}
+
+// Checks returns the stored checks by ID
+func (c *Client) Checks() map[string]checkly.Check {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	checks := make(map[string]checkly.Check, len(c.checks))
+	for ID, check := range c.checks {
+		checks[ID] = check
+	}
+	return checks
+}
+
+// Groups returns the stored groups by ID
+func (c *Client) Groups() map[int64]checkly.Group {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	groups := make(map[int64]checkly.Group, len(c.groups))
+	for ID, group := range c.groups {
+		groups[ID] = group
+	}
+	return groups
+}
+
+// AlertChannels returns the stored alert channels by ID
+func (c *Client) AlertChannels() map[int64]checkly.AlertChannel {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	alertChannels := make(map[int64]checkly.AlertChannel, len(c.alertChannels))
+	for ID, ac := range c.alertChannels {
+		alertChannels[ID] = ac
+	}
+	return alertChannels
+}
+
+// SetCheck stores the check as if it was changed in checklyhq.com, ex. to simulate drift or an existing check to adopt,
+// an ID is assigned if it's empty
+func (c *Client) SetCheck(check checkly.Check) string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if check.ID == "" {
+		check.ID = strconv.FormatInt(c.nextID(), 10)
+	}
+	c.checks[check.ID] = check
+	return check.ID
+}
+
+// SetGroup stores the group as if it was changed in checklyhq.com, an ID is assigned if it's zero
+func (c *Client) SetGroup(group checkly.Group) int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if group.ID == 0 {
+		group.ID = c.nextID()
+	}
+	c.groups[group.ID] = group
+	return group.ID
+}
+
+// SetAlertChannel stores the alert channel as if it was changed in checklyhq.com, an ID is assigned if it's zero
+func (c *Client) SetAlertChannel(ac checkly.AlertChannel) int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if ac.ID == 0 {
+		ac.ID = c.nextID()
+	}
+	c.alertChannels[ac.ID] = ac
+	return ac.ID
+}
+
+// RemoveCheck deletes the check as if it was deleted in checklyhq.com, the call isn't recorded
+func (c *Client) RemoveCheck(ID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.checks, ID)
+}
+
+// RemoveGroup deletes the group as if it was deleted in checklyhq.com, the call isn't recorded
+func (c *Client) RemoveGroup(ID int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.groups, ID)
+}
+
+// RemoveAlertChannel deletes the alert channel as if it was deleted in checklyhq.com, the call isn't recorded
+func (c *Client) RemoveAlertChannel(ID int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.alertChannels, ID)
+}
+
+func (c *Client) Create(ctx context.Context, check checkly.Check) (*checkly.Check, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.call(ctx, MethodCreate, ""); err != nil {
+		return nil, err
+	}
+
+	check.ID = strconv.FormatInt(c.nextID(), 10)
+	c.checks[check.ID] = check
+	return &check, nil
+}
+
+func (c *Client) Update(ctx context.Context, ID string, check checkly.Check) (*checkly.Check, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.call(ctx, MethodUpdate, ID); err != nil {
+		return nil, err
+	}
+
+	if _, ok := c.checks[ID]; !ok {
+		return nil, StatusError(http.StatusNotFound)
+	}
+	check.ID = ID
+	c.checks[ID] = check
+	return &check, nil
+}
+
+func (c *Client) Delete(ctx context.Context, ID string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.call(ctx, MethodDelete, ID); err != nil {
+		return err
+	}
+
+	if _, ok := c.checks[ID]; !ok {
+		return StatusError(http.StatusNotFound)
+	}
+	delete(c.checks, ID)
+	return nil
+}
+
+func (c *Client) Get(ctx context.Context, ID string) (*checkly.Check, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.call(ctx, MethodGet, ID); err != nil {
+		return nil, err
+	}
+
+	check, ok := c.checks[ID]
+	if !ok {
+		return nil, StatusError(http.StatusNotFound)
+	}
+	return &check, nil
+}
+
+func (c *Client) CreateGroup(ctx context.Context, group checkly.Group) (*checkly.Group, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.call(ctx, MethodCreateGroup, ""); err != nil {
+		return nil, err
+	}
+
+	group.ID = c.nextID()
+	c.groups[group.ID] = group
+	return &group, nil
+}
+
+func (c *Client) UpdateGroup(ctx context.Context, ID int64, group checkly.Group) (*checkly.Group, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.call(ctx, MethodUpdateGroup, strconv.FormatInt(ID, 10)); err != nil {
+		return nil, err
+	}
+
+	if _, ok := c.groups[ID]; !ok {
+		return nil, StatusError(http.StatusNotFound)
+	}
+	group.ID = ID
+	c.groups[ID] = group
+	return &group, nil
+}
+
+func (c *Client) DeleteGroup(ctx context.Context, ID int64) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.call(ctx, MethodDeleteGroup, strconv.FormatInt(ID, 10)); err != nil {
+		return err
+	}
+
+	if _, ok := c.groups[ID]; !ok {
+		return StatusError(http.StatusNotFound)
+	}
+	delete(c.groups, ID)
+	return nil
+}
+
+func (c *Client) GetGroup(ctx context.Context, ID int64) (*checkly.Group, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.call(ctx, MethodGetGroup, strconv.FormatInt(ID, 10)); err != nil {
+		return nil, err
+	}
+
+	group, ok := c.groups[ID]
+	if !ok {
+		return nil, StatusError(http.StatusNotFound)
+	}
+	return &group, nil
+}
+
+// ListChecks returns the stored checks ordered by ID
+func (c *Client) ListChecks(ctx context.Context) ([]checkly.Check, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.call(ctx, MethodListChecks, ""); err != nil {
+		return nil, err
+	}
+
+	checks := make([]checkly.Check, 0, len(c.checks))
+	for _, check := range c.checks {
+		checks = append(checks, check)
+	}
+	sort.Slice(checks, func(i, j int) bool {
+		x, _ := strconv.ParseInt(checks[i].ID, 10, 64)
+		y, _ := strconv.ParseInt(checks[j].ID, 10, 64)
+		return x < y
+	})
+	return checks, nil
+}
+
+// ListGroups returns the stored groups ordered by ID
+func (c *Client) ListGroups(ctx context.Context) ([]checkly.Group, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.call(ctx, MethodListGroups, ""); err != nil {
+		return nil, err
+	}
+
+	groups := make([]checkly.Group, 0, len(c.groups))
+	for _, group := range c.groups {
+		groups = append(groups, group)
+	}
+	sort.Slice(groups, func(i, j int) bool { return groups[i].ID < groups[j].ID })
+	return groups, nil
+}
+
+func (c *Client) CreateAlertChannel(ctx context.Context, ac checkly.AlertChannel) (*checkly.AlertChannel, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.call(ctx, MethodCreateAlertChannel, ""); err != nil {
+		return nil, err
+	}
+
+	ac.ID = c.nextID()
+	c.alertChannels[ac.ID] = ac
+	return &ac, nil
+}
+
+func (c *Client) UpdateAlertChannel(ctx context.Context, ID int64, ac checkly.AlertChannel) (*checkly.AlertChannel, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.call(ctx, MethodUpdateAlertChannel, strconv.FormatInt(ID, 10)); err != nil {
+		return nil, err
+	}
+
+	if _, ok := c.alertChannels[ID]; !ok {
+		return nil, StatusError(http.StatusNotFound)
+	}
+	ac.ID = ID
+	c.alertChannels[ID] = ac
+	return &ac, nil
+}
+
+func (c *Client) DeleteAlertChannel(ctx context.Context, ID int64) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.call(ctx, MethodDeleteAlertChannel, strconv.FormatInt(ID, 10)); err != nil {
+		return err
+	}
+
+	if _, ok := c.alertChannels[ID]; !ok {
+		return StatusError(http.StatusNotFound)
+	}
+	delete(c.alertChannels, ID)
+	return nil
+}
+
+func (c *Client) GetAlertChannel(ctx context.Context, ID int64) (*checkly.AlertChannel, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.call(ctx, MethodGetAlertChannel, strconv.FormatInt(ID, 10)); err != nil {
+		return nil, err
+	}
+
+	ac, ok := c.alertChannels[ID]
+	if !ok {
+		return nil, StatusError(http.StatusNotFound)
+	}
+	return &ac, nil
+}
+
+// call records the call and returns the injected failure of the method, if any
+func (c *Client) call(ctx context.Context, method string, ID string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	c.calls = append(c.calls, Call{Method: method, ID: ID})
+
+	if queued := c.failNext[method]; len(queued) > 0 {
+		c.failNext[method] = queued[1:]
+		return queued[0]
+	}
+
+	return c.failAll[method]
+}
+
+func (c *Client) nextID() int64 {
+	c.lastID++
+	return c.lastID
+}
@@ -0,0 +1,133 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fake
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/checkly/checkly-go-sdk"
+)
+
+func TestClient(t *testing.T) {
+	ctx := context.Background()
+	c := NewClient()
+
+	group, err := c.CreateGroup(ctx, checkly.Group{Name: "foo"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %e", err)
+	}
+	if group.ID != 1 {
+		t.Errorf("Expected %d, got %d", 1, group.ID)
+	}
+
+	check, err := c.Create(ctx, checkly.Check{Name: "bar", GroupID: group.ID})
+	if err != nil {
+		t.Fatalf("Expected no error, got %e", err)
+	}
+	if check.ID != "2" {
+		t.Errorf("Expected %s, got %s", "2", check.ID)
+	}
+
+	if _, err := c.Update(ctx, check.ID, checkly.Check{Name: "baz"}); err != nil {
+		t.Errorf("Expected no error, got %e", err)
+	}
+	if got := c.Checks()[check.ID]; got.Name != "baz" || got.ID != check.ID {
+		t.Errorf("Expected updated check %s, got %v", check.ID, got)
+	}
+
+	ac, err := c.CreateAlertChannel(ctx, checkly.AlertChannel{Type: checkly.AlertTypeEmail})
+	if err != nil {
+		t.Fatalf("Expected no error, got %e", err)
+	}
+	if _, ok := c.AlertChannels()[ac.ID]; !ok {
+		t.Errorf("Expected alert channel %d to be stored", ac.ID)
+	}
+
+	// Missing resources return the same error as checklyhq.com
+	c.RemoveGroup(group.ID)
+	if _, err := c.GetGroup(ctx, group.ID); err == nil || !strings.Contains(err.Error(), "unexpected response status 404") {
+		t.Errorf("Expected not found error, got %v", err)
+	}
+	if err := c.DeleteAlertChannel(ctx, ac.ID); err != nil {
+		t.Errorf("Expected no error, got %e", err)
+	}
+	if err := c.DeleteAlertChannel(ctx, ac.ID); err == nil {
+		t.Error("Expected error, got none")
+	}
+
+	// Remote changes
+	c.SetCheck(checkly.Check{ID: check.ID, Name: "qux"})
+	got, err := c.Get(ctx, check.ID)
+	if err != nil || got.Name != "qux" {
+		t.Errorf("Expected remote change, got %v, %v", got, err)
+	}
+
+	if count := c.CallCount(MethodDeleteAlertChannel); count != 2 {
+		t.Errorf("Expected %d, got %d", 2, count)
+	}
+	calls := c.Calls()
+	if len(calls) != 8 || calls[0] != (Call{Method: MethodCreateGroup}) || calls[7] != (Call{Method: MethodGet, ID: check.ID}) {
+		t.Errorf("Unexpected calls %v", calls)
+	}
+}
+
+func TestClientFailures(t *testing.T) {
+	ctx := context.Background()
+	c := NewClient()
+
+	first := StatusError(http.StatusTooManyRequests)
+	second := errors.New("foo")
+	c.FailNext(MethodCreate, first)
+	c.FailNext(MethodCreate, second)
+
+	if _, err := c.Create(ctx, checkly.Check{}); err != first {
+		t.Errorf("Expected %v, got %v", first, err)
+	}
+	if _, err := c.Create(ctx, checkly.Check{}); err != second {
+		t.Errorf("Expected %v, got %v", second, err)
+	}
+	if _, err := c.Create(ctx, checkly.Check{}); err != nil {
+		t.Errorf("Expected no error, got %e", err)
+	}
+	if len(c.Checks()) != 1 {
+		t.Errorf("Expected %d, got %d", 1, len(c.Checks()))
+	}
+
+	c.FailAll(MethodGetGroup, second)
+	for i := 0; i < 2; i++ {
+		if _, err := c.GetGroup(ctx, 1); err != second {
+			t.Errorf("Expected %v, got %v", second, err)
+		}
+	}
+	c.ClearFailures()
+	if _, err := c.GetGroup(ctx, 1); err == second {
+		t.Error("Expected failure to be cleared")
+	}
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	if _, err := c.CreateGroup(cancelled, checkly.Group{}); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected %v, got %v", context.Canceled, err)
+	}
+	if len(c.Groups()) != 0 {
+		t.Errorf("Expected no groups, got %v", c.Groups())
+	}
+}
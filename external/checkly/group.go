@@ -18,7 +18,8 @@ package external
 
 import (
 	"context"
-	"time"
+	"fmt"
+	"slices"
 
 	"github.com/checkly/checkly-go-sdk"
 )
@@ -31,34 +32,32 @@ type Group struct {
 	Activated        bool
 	AlertChannels    []checkly.AlertChannelSubscription
 	Labels           map[string]string
+	// AlertSettings are sent as they are, DefaultAlertSettings are used if nil
+	AlertSettings          *checkly.AlertSettings
+	UseGlobalAlertSettings bool
+	// OwnerTag is added to the tags of the group so it can be found again with FindGroup, empty disables it
+	OwnerTag string
 }
 
 func checklyGroup(group Group) (check checkly.Group) {
 
 	tags := getTags(group.Labels)
 	tags = append(tags, "checkly-operator")
-
-	alertSettings := checkly.AlertSettings{
-		EscalationType: checkly.RunBased,
-		RunBasedEscalation: checkly.RunBasedEscalation{
-			FailedRunThreshold: 5,
-		},
-		TimeBasedEscalation: checkly.TimeBasedEscalation{
-			MinutesFailingThreshold: 5,
-		},
-		Reminders: checkly.Reminders{
-			Interval: 5,
-		},
-		SSLCertificates: checkly.SSLCertificates{
-			Enabled:        false,
-			AlertThreshold: 3,
-		},
+	if group.OwnerTag != "" {
+		tags = append(tags, group.OwnerTag)
 	}
 
-	locations := []string{}
+	alertSettings := DefaultAlertSettings()
+	if group.AlertSettings != nil {
+		alertSettings = *group.AlertSettings
+	}
 
-	if len(group.PrivateLocations) != 0 {
-		checkValueArray(group.Locations, []string{"eu-west-1"})
+	locations := group.Locations
+	if len(group.PrivateLocations) == 0 {
+		locations = checkValueArray(group.Locations, []string{DefaultGroupLocation})
+	}
+	if locations == nil {
+		locations = []string{}
 	}
 
 	check = checkly.Group{
@@ -73,20 +72,19 @@ func checklyGroup(group Group) (check checkly.Group) {
 		PrivateLocations:          &group.PrivateLocations,
 		Tags:                      tags,
 		AlertSettings:             alertSettings,
-		UseGlobalAlertSettings:    false,
+		UseGlobalAlertSettings:    group.UseGlobalAlertSettings,
 		AlertChannelSubscriptions: group.AlertChannels,
 	}
 
 	return
 }
 
-func GroupCreate(group Group, client checkly.Client) (ID int64, err error) {
+func GroupCreate(ctx context.Context, group Group, client Client) (ID int64, err error) {
 	groupSetup := checklyGroup(group)
 
-	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
-	defer cancel()
-
+	ctx, call := startAPICall(ctx, resourceGroup, operationCreate)
 	gotGroup, err := client.CreateGroup(ctx, groupSetup)
+	err = call.done(err)
 	if err != nil {
 		return
 	}
@@ -96,14 +94,13 @@ func GroupCreate(group Group, client checkly.Client) (ID int64, err error) {
 	return
 }
 
-func GroupUpdate(group Group, client checkly.Client) (err error) {
+func GroupUpdate(ctx context.Context, group Group, client Client) (err error) {
 
 	groupSetup := checklyGroup(group)
 
-	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
-	defer cancel()
-
+	ctx, call := startAPICall(ctx, resourceGroup, operationUpdate)
 	_, err = client.UpdateGroup(ctx, group.ID, groupSetup)
+	err = call.done(err)
 	if err != nil {
 		return
 	}
@@ -111,11 +108,138 @@ func GroupUpdate(group Group, client checkly.Client) (err error) {
 	return
 }
 
-func GroupDelete(ID int64, client checkly.Client) (err error) {
-	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
-	defer cancel()
-
+// GroupDelete deletes an existing checklyhq.com group, a group which no longer exists in checklyhq.com isn't an error
+func GroupDelete(ctx context.Context, ID int64, client Client) (err error) {
+	ctx, call := startAPICall(ctx, resourceGroup, operationDelete)
 	err = client.DeleteGroup(ctx, ID)
+	err = call.done(err)
+	if isNotFound(err) {
+		err = nil
+	}
+
+	return
+}
+
+// TagGroup adds the tag to an existing checklyhq.com group, ex. to mark a group which is kept after its resource was deleted.
+// A group which no longer exists in checklyhq.com isn't an error.
+func TagGroup(ctx context.Context, ID int64, tag string, client Client) (err error) {
+
+	getCtx, call := startAPICall(ctx, resourceGroup, operationGet)
+	group, err := client.GetGroup(getCtx, ID)
+	err = call.done(err)
+	if err != nil {
+		if isNotFound(err) {
+			err = nil
+		}
+		return
+	}
+
+	if slices.Contains(group.Tags, tag) {
+		return
+	}
+	group.Tags = append(group.Tags, tag)
+
+	updateCtx, call := startAPICall(ctx, resourceGroup, operationUpdate)
+	_, err = client.UpdateGroup(updateCtx, ID, *group)
+	err = call.done(err)
+
+	return
+}
+
+// FindGroup returns the ID of the checklyhq.com group with the tag, ex. the ownership tag of a resource which lost its status,
+// ID is zero if there's no such group. It fails if the client can't list groups, the lookup is what prevents duplicates.
+func FindGroup(ctx context.Context, tag string, client Client) (ID int64, err error) {
+	lister, ok := client.(Lister)
+	if !ok {
+		err = fmt.Errorf("the checklyhq.com client can't list groups, the group with the tag %s can't be looked up", tag)
+		return
+	}
+
+	groups, err := lister.ListGroups(ctx)
+	if err != nil {
+		return
+	}
+
+	for _, group := range groups {
+		if slices.Contains(group.Tags, tag) {
+			ID = group.ID
+			return
+		}
+	}
+
+	return
+}
+
+// GroupExists determines if a group with the given ID exists in checklyhq.com, used when adopting existing groups
+func GroupExists(ctx context.Context, ID int64, client Client) (found bool, err error) {
+
+	ctx, call := startAPICall(ctx, resourceGroup, operationGet)
+	_, err = client.GetGroup(ctx, ID)
+	err = call.done(err)
+	if err != nil {
+		if isNotFound(err) {
+			err = nil
+		}
+		return
+	}
+
+	found = true
+
+	return
+}
+
+// GroupDrift fetches the checklyhq.com group and returns the fields which differ from the desired state,
+// found is false if the group no longer exists in checkly
+func GroupDrift(ctx context.Context, group Group, client Client) (drift []string, found bool, err error) {
+
+	groupSetup := checklyGroup(group)
+
+	ctx, call := startAPICall(ctx, resourceGroup, operationGet)
+	gotGroup, err := client.GetGroup(ctx, group.ID)
+	err = call.done(err)
+	if err != nil {
+		if isNotFound(err) {
+			err = nil
+		}
+		return
+	}
+
+	found = true
+	drift = groupDrift(groupSetup, *gotGroup)
+
+	return
+}
+
+// groupDrift returns the fields of the remote group which differ from the desired group
+func groupDrift(desired checkly.Group, remote checkly.Group) (drift []string) {
+	if desired.Name != remote.Name {
+		drift = append(drift, "name")
+	}
+	if desired.Activated != remote.Activated {
+		drift = append(drift, "activated")
+	}
+	if desired.Muted != remote.Muted {
+		drift = append(drift, "muted")
+	}
+	if !sameStrings(desired.Locations, remote.Locations) {
+		drift = append(drift, "locations")
+	}
+	if !sameStrings(desired.Tags, remote.Tags) {
+		drift = append(drift, "tags")
+	}
+
+	var desiredChannels, remoteChannels []string
+	for _, subscription := range desired.AlertChannelSubscriptions {
+		desiredChannels = append(desiredChannels, fmt.Sprintf("%d", subscription.ChannelID))
+	}
+	for _, subscription := range remote.AlertChannelSubscriptions {
+		remoteChannels = append(remoteChannels, fmt.Sprintf("%d", subscription.ChannelID))
+	}
+	if !sameStrings(desiredChannels, remoteChannels) {
+		drift = append(drift, "alertChannels")
+	}
+
+	drift = append(drift, alertSettingsDrift(desired.AlertSettings, desired.UseGlobalAlertSettings, remote.AlertSettings, remote.UseGlobalAlertSettings)...)
 
 	return
 }
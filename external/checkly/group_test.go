@@ -16,7 +16,15 @@ limitations under the License.
 
 package external
 
-import "testing"
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/checkly/checkly-go-sdk"
+
+	"github.com/checkly/checkly-operator/external/checkly/fake"
+)
 
 func TestChecklyGroup(t *testing.T) {
 	data := Group{
@@ -30,4 +38,157 @@ func TestChecklyGroup(t *testing.T) {
 	if testData.Name != data.Name {
 		t.Errorf("Expected %s, got %s", data.Name, testData.Name)
 	}
+
+	if len(testData.Locations) != 1 || testData.Locations[0] != data.Locations[0] {
+		t.Errorf("Expected %v, got %v", data.Locations, testData.Locations)
+	}
+
+	if testData.AlertSettings.RunBasedEscalation.FailedRunThreshold != 5 {
+		t.Errorf("Expected %d, got %d", 5, testData.AlertSettings.RunBasedEscalation.FailedRunThreshold)
+	}
+
+	alertSettings := DefaultAlertSettings()
+	alertSettings.RunBasedEscalation.FailedRunThreshold = 1
+	data.AlertSettings = &alertSettings
+	data.UseGlobalAlertSettings = true
+
+	testData = checklyGroup(data)
+
+	if testData.AlertSettings.RunBasedEscalation.FailedRunThreshold != 1 {
+		t.Errorf("Expected %d, got %d", 1, testData.AlertSettings.RunBasedEscalation.FailedRunThreshold)
+	}
+
+	if !testData.UseGlobalAlertSettings {
+		t.Errorf("Expected %t, got %t", true, testData.UseGlobalAlertSettings)
+	}
+
+	data = Group{
+		Name: "bar",
+	}
+
+	testData = checklyGroup(data)
+
+	if len(testData.Locations) != 1 || testData.Locations[0] != DefaultGroupLocation {
+		t.Errorf("Expected %v, got %v", []string{DefaultGroupLocation}, testData.Locations)
+	}
+}
+
+func TestGroupDrift(t *testing.T) {
+	desired := checklyGroup(Group{
+		Name:      "foo",
+		Locations: []string{"eu-west-1", "eu-west-2"},
+		AlertChannels: []checkly.AlertChannelSubscription{
+			{ChannelID: 3, Activated: true},
+		},
+	})
+
+	remote := desired
+	remote.Locations = []string{"eu-west-2", "eu-west-1"}
+	if drift := groupDrift(desired, remote); len(drift) != 0 {
+		t.Errorf("Expected no drift, got %v", drift)
+	}
+
+	remote.Muted = true
+	remote.AlertChannelSubscriptions = []checkly.AlertChannelSubscription{}
+	drift := groupDrift(desired, remote)
+	expectedDrift := []string{"muted", "alertChannels"}
+	if !sameStrings(drift, expectedDrift) {
+		t.Errorf("Expected %v, got %v", expectedDrift, drift)
+	}
+}
+
+func TestGroupActions(t *testing.T) {
+	ctx := context.Background()
+	client := fake.NewClient()
+	group := Group{
+		Name:      "foo",
+		Locations: []string{"eu-west-1"},
+	}
+
+	ID, err := GroupCreate(ctx, group, client)
+	if err != nil {
+		t.Fatalf("Expected no error, got %e", err)
+	}
+	group.ID = ID
+
+	drift, found, err := GroupDrift(ctx, group, client)
+	if err != nil || !found || len(drift) != 0 {
+		t.Errorf("Expected no drift, got %v, %t, %v", drift, found, err)
+	}
+
+	// Changed in checklyhq.com
+	remote := client.Groups()[ID]
+	remote.Muted = true
+	client.SetGroup(remote)
+	drift, _, _ = GroupDrift(ctx, group, client)
+	if !sameStrings(drift, []string{"muted"}) {
+		t.Errorf("Expected %v, got %v", []string{"muted"}, drift)
+	}
+
+	if err := GroupUpdate(ctx, group, client); err != nil {
+		t.Errorf("Expected no error, got %e", err)
+	}
+	if client.Groups()[ID].Muted {
+		t.Error("Expected drift to be corrected")
+	}
+
+	client.FailNext(fake.MethodDeleteGroup, fake.StatusError(http.StatusTooManyRequests))
+	err = GroupDelete(ctx, ID, client)
+	if !IsRateLimited(err) {
+		t.Errorf("Expected rate limited error, got %v", err)
+	}
+
+	if err := GroupDelete(ctx, ID, client); err != nil {
+		t.Errorf("Expected no error, got %e", err)
+	}
+	found, err = GroupExists(ctx, ID, client)
+	if err != nil || found {
+		t.Errorf("Expected group to be deleted, got %t, %v", found, err)
+	}
+
+	// Already deleted, ex. in the checklyhq.com UI
+	if err := GroupDelete(ctx, ID, client); err != nil {
+		t.Errorf("Expected no error, got %e", err)
+	}
+}
+
+func TestTagGroup(t *testing.T) {
+	ctx := context.Background()
+	client := fake.NewClient()
+	ID := client.SetGroup(checkly.Group{Name: "foo", Tags: []string{"environment:dev"}})
+
+	for i := 0; i < 2; i++ {
+		if err := TagGroup(ctx, ID, "retained", client); err != nil {
+			t.Fatalf("Expected no error, got %e", err)
+		}
+	}
+	if tags := client.Groups()[ID].Tags; !sameStrings(tags, []string{"environment:dev", "retained"}) {
+		t.Errorf("Expected %v, got %v", []string{"environment:dev", "retained"}, tags)
+	}
+
+	// Nothing to tag
+	client.RemoveGroup(ID)
+	if err := TagGroup(ctx, ID, "retained", client); err != nil {
+		t.Errorf("Expected no error, got %e", err)
+	}
+}
+
+func TestFindGroup(t *testing.T) {
+	ctx := context.Background()
+	client := fake.NewClient()
+	client.SetGroup(checkly.Group{Name: "foo", Tags: []string{"checkly-operator"}})
+	ID := client.SetGroup(checkly.Group{Name: "bar", Tags: []string{"checkly-operator", "owner:foo"}})
+
+	foundID, err := FindGroup(ctx, "owner:foo", client)
+	if err != nil {
+		t.Fatalf("Expected no error, got %e", err)
+	}
+	if foundID != ID {
+		t.Errorf("Expected %d, got %d", ID, foundID)
+	}
+
+	foundID, err = FindGroup(ctx, "owner:bar", client)
+	if err != nil || foundID != 0 {
+		t.Errorf("Expected no group and no error, got %d and %v", foundID, err)
+	}
 }
@@ -0,0 +1,253 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package external
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httputil"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/checkly/checkly-go-sdk"
+)
+
+// Lister is implemented by the clients which can list the checks and groups of an account, it's used to find the checklyhq.com
+// resources of a kubernetes resource by their ownership tag
+type Lister interface {
+	ListChecks(ctx context.Context) ([]checkly.Check, error)
+	ListGroups(ctx context.Context) ([]checkly.Group, error)
+}
+
+var _ Lister = &accountClient{}
+
+// listPageSize is the number of resources requested per page, the maximum of the checklyhq.com API
+const listPageSize = 100
+
+// ListCacheTTL is how long the checks and groups listed from an account are reused, so a burst of new resources, ex. an
+// Ingress rollout, lists the account once instead of once per resource
+var ListCacheTTL = 30 * time.Second
+
+// accountClient adds the list calls, which checkly-go-sdk lacks, to the checkly client of an account. The checks and
+// groups it creates, updates or deletes are applied to the cached listings once the API call succeeded, so they're found even
+// before the cache expires.
+type accountClient struct {
+	checkly.Client
+	httpClient *http.Client
+	baseURL    string
+	apiKey     string
+	accountID  string
+	debug      io.Writer
+
+	checks listCache[checkly.Check]
+	groups listCache[checkly.Group]
+}
+
+// ListChecks returns all checks of the account
+func (c *accountClient) ListChecks(ctx context.Context) ([]checkly.Check, error) {
+	return c.checks.get(ctx, func(ctx context.Context) ([]checkly.Check, error) {
+		return listAll[checkly.Check](ctx, c, resourceCheck, "checks")
+	})
+}
+
+// ListGroups returns all check groups of the account
+func (c *accountClient) ListGroups(ctx context.Context) ([]checkly.Group, error) {
+	return c.groups.get(ctx, func(ctx context.Context) ([]checkly.Group, error) {
+		return listAll[checkly.Group](ctx, c, resourceGroup, "check-groups")
+	})
+}
+
+func (c *accountClient) Create(ctx context.Context, check checkly.Check) (*checkly.Check, error) {
+	created, err := c.Client.Create(ctx, check)
+	if err == nil {
+		c.checks.put(created.ID, *created)
+	}
+	return created, err
+}
+
+func (c *accountClient) Update(ctx context.Context, ID string, check checkly.Check) (*checkly.Check, error) {
+	updated, err := c.Client.Update(ctx, ID, check)
+	if err == nil {
+		c.checks.put(ID, *updated)
+	}
+	return updated, err
+}
+
+func (c *accountClient) Delete(ctx context.Context, ID string) error {
+	err := c.Client.Delete(ctx, ID)
+	if err == nil || responseStatus(ctx) == http.StatusNotFound {
+		c.checks.remove(ID)
+	}
+	return err
+}
+
+func (c *accountClient) CreateGroup(ctx context.Context, group checkly.Group) (*checkly.Group, error) {
+	created, err := c.Client.CreateGroup(ctx, group)
+	if err == nil {
+		c.groups.put(strconv.FormatInt(created.ID, 10), *created)
+	}
+	return created, err
+}
+
+func (c *accountClient) UpdateGroup(ctx context.Context, ID int64, group checkly.Group) (*checkly.Group, error) {
+	updated, err := c.Client.UpdateGroup(ctx, ID, group)
+	if err == nil {
+		c.groups.put(strconv.FormatInt(ID, 10), *updated)
+	}
+	return updated, err
+}
+
+func (c *accountClient) DeleteGroup(ctx context.Context, ID int64) error {
+	err := c.Client.DeleteGroup(ctx, ID)
+	if err == nil || responseStatus(ctx) == http.StatusNotFound {
+		c.groups.remove(strconv.FormatInt(ID, 10))
+	}
+	return err
+}
+
+// listCache holds the last listing of a checklyhq.com list endpoint for ListCacheTTL, the items are stored by their ID
+type listCache[T any] struct {
+	mu      sync.Mutex
+	items   map[string]T
+	order   []string
+	fetched time.Time
+}
+
+// get returns the cached items, or lists them with fetch if the cache expired. Concurrent callers wait for a single fetch.
+func (l *listCache[T]) get(ctx context.Context, fetch func(ctx context.Context) ([]T, error)) ([]T, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.items == nil || time.Since(l.fetched) >= ListCacheTTL {
+		items, err := fetch(ctx)
+		if err != nil {
+			return nil, err
+		}
+		l.items = map[string]T{}
+		l.order = nil
+		for _, item := range items {
+			ID := itemID(item)
+			l.items[ID] = item
+			l.order = append(l.order, ID)
+		}
+		l.fetched = time.Now()
+	}
+
+	items := make([]T, 0, len(l.order))
+	for _, ID := range l.order {
+		items = append(items, l.items[ID])
+	}
+	return items, nil
+}
+
+// put adds or replaces an item of a cached listing, nothing is cached before the first listing
+func (l *listCache[T]) put(ID string, item T) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.items == nil {
+		return
+	}
+	if _, ok := l.items[ID]; !ok {
+		l.order = append(l.order, ID)
+	}
+	l.items[ID] = item
+}
+
+// remove drops an item from a cached listing
+func (l *listCache[T]) remove(ID string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if _, ok := l.items[ID]; !ok {
+		return
+	}
+	delete(l.items, ID)
+	l.order = slices.DeleteFunc(l.order, func(x string) bool { return x == ID })
+}
+
+// itemID returns the ID of a listed check or group
+func itemID(item any) string {
+	switch item := item.(type) {
+	case checkly.Check:
+		return item.ID
+	case checkly.Group:
+		return strconv.FormatInt(item.ID, 10)
+	}
+	return ""
+}
+
+// listAll fetches every page of a checklyhq.com list endpoint, each page is a separate API call with its own timeout and metric
+func listAll[T any](ctx context.Context, c *accountClient, resource string, path string) (items []T, err error) {
+	for page := 1; ; page++ {
+		pageCtx, call := startAPICall(ctx, resource, operationList)
+		pageItems, err := listPage[T](pageCtx, c, path, page)
+		err = call.done(err)
+		if err != nil {
+			return nil, err
+		}
+
+		items = append(items, pageItems...)
+		if len(pageItems) < listPageSize {
+			return items, nil
+		}
+	}
+}
+
+// listPage fetches a single page of a checklyhq.com list endpoint, errors look like the ones of checkly-go-sdk
+func listPage[T any](ctx context.Context, c *accountClient, path string, page int) (items []T, err error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/v1/%s?limit=%d&page=%d", c.baseURL, path, listPageSize, page), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create HTTP request: %v", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	if c.accountID != "" {
+		req.Header.Set("X-Checkly-Account", c.accountID)
+	}
+	if c.debug != nil {
+		if dump, err := httputil.DumpRequestOut(req, false); err == nil {
+			fmt.Fprintln(c.debug, string(dump))
+		}
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("HTTP request failed with: %v", err)
+	}
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("HTTP request failed: %v", err)
+	}
+	if c.debug != nil {
+		fmt.Fprintf(c.debug, "%s %s\n%s\n\n", resp.Proto, resp.Status, body)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected response status %d: %q", resp.StatusCode, body)
+	}
+
+	err = json.Unmarshal(body, &items)
+	if err != nil {
+		return nil, fmt.Errorf("decoding error for data %q: %v", body, err)
+	}
+	return items, nil
+}
@@ -0,0 +1,159 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package external
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/checkly/checkly-go-sdk"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/checkly/checkly-operator/external/checkly/fake"
+	"github.com/checkly/checkly-operator/internal/metrics"
+)
+
+var _ Lister = &fake.Client{}
+
+func TestListChecks(t *testing.T) {
+	pages := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.URL.Path, "/v1/checks") || r.Header.Get("Authorization") != "Bearer foobarbaz" || r.Header.Get("X-Checkly-Account") != "1234" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.Method {
+		case http.MethodPost:
+			check := checkly.Check{}
+			json.NewDecoder(r.Body).Decode(&check)
+			check.ID = "created"
+			w.WriteHeader(http.StatusCreated)
+			json.NewEncoder(w).Encode(check)
+			return
+		case http.MethodDelete:
+			switch r.URL.Path {
+			case "/v1/checks/1-1":
+				w.WriteHeader(http.StatusInternalServerError)
+			case "/v1/checks/1-2":
+				w.WriteHeader(http.StatusNotFound)
+			default:
+				w.WriteHeader(http.StatusNoContent)
+			}
+			return
+		}
+
+		// A full first page and a partial second page
+		pages++
+		count := listPageSize
+		if r.URL.Query().Get("page") != "1" {
+			count = 1
+		}
+		checks := make([]checkly.Check, count)
+		for i := range checks {
+			checks[i].ID = fmt.Sprintf("%s-%d", r.URL.Query().Get("page"), i)
+		}
+		json.NewEncoder(w).Encode(checks)
+	}))
+	defer server.Close()
+
+	factory, err := NewClientFactory(ClientOptions{BaseURL: server.URL})
+	if err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+	ctx := context.Background()
+	client := factory.NewClient("foobarbaz", "1234")
+	listed := metrics.APICallsTotal.WithLabelValues(resourceCheck, operationList, metrics.OutcomeSuccess)
+	before := testutil.ToFloat64(listed)
+
+	checks, err := client.(Lister).ListChecks(ctx)
+	if err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+	if len(checks) != listPageSize+1 {
+		t.Errorf("Expected %d checks, got %d", listPageSize+1, len(checks))
+	}
+	if ID := checks[len(checks)-1].ID; ID != "2-0" {
+		t.Errorf("Expected %s, got %s", "2-0", ID)
+	}
+	// Every page is a separate API call
+	if value := testutil.ToFloat64(listed); value != before+2 {
+		t.Errorf("Expected %f, got %f", before+2, value)
+	}
+
+	// The listing is cached and follows the checks created and deleted with the client, a check is only removed from it once
+	// it's gone in checklyhq.com
+	if _, err := client.Create(ctx, checkly.Check{Name: "foo"}); err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+	if err := Delete(ctx, "1-0", client); err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+	if err := Delete(ctx, "1-1", client); err == nil {
+		t.Fatal("Expected error, got none")
+	}
+	if err := Delete(ctx, "1-2", client); err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+	checks, err = client.(Lister).ListChecks(ctx)
+	if err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+	if pages != 2 {
+		t.Errorf("Expected %d pages, got %d", 2, pages)
+	}
+	if len(checks) != listPageSize {
+		t.Errorf("Expected %d checks, got %d", listPageSize, len(checks))
+	}
+	if checks[0].ID != "1-1" || checks[len(checks)-1].ID != "created" {
+		t.Errorf("Expected %s to %s, got %s to %s", "1-1", "created", checks[0].ID, checks[len(checks)-1].ID)
+	}
+
+	// An expired listing is fetched again
+	defer func(ttl time.Duration) { ListCacheTTL = ttl }(ListCacheTTL)
+	ListCacheTTL = 0
+	if _, err := client.(Lister).ListChecks(ctx); err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+	if pages != 4 {
+		t.Errorf("Expected %d pages, got %d", 4, pages)
+	}
+
+	_, err = factory.NewClient("foobarbaz", "4321").(Lister).ListGroups(ctx)
+	if err == nil || !strings.Contains(err.Error(), "unexpected response status 404") {
+		t.Errorf("Expected not found error, got %v", err)
+	}
+}
+
+func TestFindWithoutLister(t *testing.T) {
+	ctx := context.Background()
+	client := checkly.NewClient("http://localhost", "foobarbaz", nil, nil)
+
+	if _, err := FindCheck(ctx, "owner:foo", client); err == nil {
+		t.Error("Expected error, got none")
+	}
+	if _, err := FindGroup(ctx, "owner:foo", client); err == nil {
+		t.Error("Expected error, got none")
+	}
+}
@@ -0,0 +1,280 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package external
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"regexp"
+	"strconv"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// Defaults of the rate limiting and retry options
+const (
+	DefaultRateLimit  = 10
+	DefaultRateBurst  = 20
+	DefaultMaxRetries = 3
+)
+
+// Backoff between retries which don't carry a Retry-After header, doubled on every attempt
+const (
+	retryBaseDelay = 500 * time.Millisecond
+	retryMaxDelay  = 30 * time.Second
+)
+
+// defaultRateLimitedDelay is used as requeue delay for a 429 response without a Retry-After header
+const defaultRateLimitedDelay = 30 * time.Second
+
+// APIError is returned for failed checklyhq.com API calls, it carries the status of the last response so the
+// reconcilers can choose when to try again
+type APIError struct {
+	// StatusCode of the last response, zero if no response was received
+	StatusCode int
+	// RetryAfter is the delay the API asked for with the Retry-After header of the last response, zero if not set
+	RetryAfter time.Duration
+	Err        error
+}
+
+func (e *APIError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *APIError) Unwrap() error {
+	return e.Err
+}
+
+// IsRateLimited determines if the checklyhq.com API call failed with a 429 response after all retries
+func IsRateLimited(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusTooManyRequests
+}
+
+// RequeueAfter returns when a failed checklyhq.com API call should be tried again, ok is false if the default
+// exponential backoff of the reconciler should be used
+func RequeueAfter(err error) (delay time.Duration, ok bool) {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusTooManyRequests {
+		return
+	}
+
+	ok = true
+	delay = apiErr.RetryAfter
+	if delay == 0 {
+		delay = defaultRateLimitedDelay
+	}
+	return
+}
+
+// responseInfo is filled by the retry transport with the last response of an API call
+type responseInfo struct {
+	statusCode int
+	retryAfter time.Duration
+}
+
+type responseInfoKey struct{}
+
+// apiCall tracks a single checklyhq.com API call started with startAPICall
+type apiCall struct {
+	resource  string
+	operation string
+	start     time.Time
+	response  *responseInfo
+	cancel    context.CancelFunc
+}
+
+// startAPICall derives the context of a checklyhq.com API call from the context of the reconciler, applying RequestTimeout
+func startAPICall(ctx context.Context, resource string, operation string) (context.Context, *apiCall) {
+	call := &apiCall{
+		resource:  resource,
+		operation: operation,
+		start:     time.Now(),
+		response:  &responseInfo{},
+	}
+
+	ctx, call.cancel = context.WithTimeout(ctx, RequestTimeout)
+	ctx = context.WithValue(ctx, responseInfoKey{}, call.response)
+
+	return ctx, call
+}
+
+// done records the API call in the metrics and turns a failure into an *APIError
+func (c *apiCall) done(err error) error {
+	c.cancel()
+	if err == nil {
+		observeAPICall(c.resource, c.operation, c.start, nil)
+		return nil
+	}
+
+	apiErr := &APIError{
+		StatusCode: c.response.statusCode,
+		RetryAfter: c.response.retryAfter,
+		Err:        err,
+	}
+	// Clients which don't use the retry transport, the status is only available in the error message
+	if apiErr.StatusCode == 0 {
+		apiErr.StatusCode = statusFromError(err)
+	}
+	observeAPICall(c.resource, c.operation, c.start, apiErr)
+
+	return apiErr
+}
+
+// responseStatus returns the status code of the last response of the API call started with ctx, zero if there was none
+func responseStatus(ctx context.Context) int {
+	info, _ := ctx.Value(responseInfoKey{}).(*responseInfo)
+	if info == nil {
+		return 0
+	}
+	return info.statusCode
+}
+
+var statusPattern = regexp.MustCompile(`unexpected response status (\d+)`)
+
+// statusFromError reads the status code from the errors of the checkly client, zero if there is none
+func statusFromError(err error) int {
+	match := statusPattern.FindStringSubmatch(err.Error())
+	if match == nil {
+		return 0
+	}
+	status, _ := strconv.Atoi(match[1])
+	return status
+}
+
+// retryTransport limits the request rate and retries rate limited requests and server errors,
+// the Retry-After header of the response is honoured
+type retryTransport struct {
+	next       http.RoundTripper
+	limiter    *rate.Limiter
+	maxRetries int
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	info, _ := ctx.Value(responseInfoKey{}).(*responseInfo)
+
+	for attempt := 0; ; attempt++ {
+		if t.limiter != nil {
+			if err := t.limiter.Wait(ctx); err != nil {
+				return nil, err
+			}
+		}
+
+		attemptReq := req
+		if attempt > 0 && req.Body != nil {
+			if req.GetBody == nil {
+				return nil, errors.New("request body can't be replayed")
+			}
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			attemptReq = req.Clone(ctx)
+			attemptReq.Body = body
+		}
+
+		resp, err := t.next.RoundTrip(attemptReq)
+
+		var retryAfter time.Duration
+		if resp != nil {
+			retryAfter = parseRetryAfter(resp.Header.Get("Retry-After"), time.Now())
+			if info != nil {
+				info.statusCode = resp.StatusCode
+				info.retryAfter = retryAfter
+			}
+		}
+
+		if attempt >= t.maxRetries || ctx.Err() != nil || !shouldRetry(req.Method, resp, err) {
+			return resp, err
+		}
+
+		delay := retryAfter
+		if delay == 0 {
+			delay = backoff(attempt)
+		}
+		// Give up if the context ends before the next attempt, the reconciler requeues instead
+		if deadline, ok := ctx.Deadline(); ok && time.Now().Add(delay).After(deadline) {
+			return resp, err
+		}
+
+		if resp != nil {
+			_, _ = io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// shouldRetry determines if the request is sent again. Rate limited requests were not processed and are always retried,
+// server and connection errors only for idempotent methods, a repeated POST could create a duplicate.
+func shouldRetry(method string, resp *http.Response, err error) bool {
+	if resp != nil && resp.StatusCode == http.StatusTooManyRequests {
+		return true
+	}
+
+	if method == http.MethodPost {
+		return false
+	}
+
+	if err != nil {
+		return true
+	}
+
+	return resp.StatusCode >= http.StatusInternalServerError
+}
+
+// backoff returns the delay before the next attempt when the response has no Retry-After header
+func backoff(attempt int) time.Duration {
+	delay := retryBaseDelay << attempt
+	if delay <= 0 || delay > retryMaxDelay {
+		return retryMaxDelay
+	}
+	return delay
+}
+
+// parseRetryAfter reads a Retry-After header, which holds either seconds or an HTTP date, zero if it's empty or invalid
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	if value == "" {
+		return 0
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0
+		}
+		return time.Duration(seconds) * time.Second
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		if delay := date.Sub(now); delay > 0 {
+			return delay
+		}
+	}
+
+	return 0
+}
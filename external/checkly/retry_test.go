@@ -0,0 +1,149 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package external
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRetryTransport(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempt := requests.Add(1)
+		switch r.URL.Path {
+		case "/v1/checks/rate-limited-once":
+			if attempt == 1 {
+				w.Header().Set("Retry-After", "1")
+				w.WriteHeader(http.StatusTooManyRequests)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"id":"rate-limited-once"}`))
+		case "/v1/checks/rate-limited":
+			w.Header().Set("Retry-After", "60")
+			w.WriteHeader(http.StatusTooManyRequests)
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+
+	factory, err := NewClientFactory(ClientOptions{BaseURL: server.URL, MaxRetries: 1})
+	if err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+	client := factory.NewClient("foobarbaz", "1234")
+
+	// A rate limited request is sent again after the Retry-After delay
+	found, err := CheckExists(context.Background(), "rate-limited-once", client)
+	if err != nil || !found {
+		t.Errorf("Expected the check to be found, got %t, %v", found, err)
+	}
+	if requests.Load() != 2 {
+		t.Errorf("Expected %d requests, got %d", 2, requests.Load())
+	}
+
+	// A Retry-After beyond the request timeout is returned to the reconciler
+	requests.Store(0)
+	_, err = CheckExists(context.Background(), "rate-limited", client)
+	if !IsRateLimited(err) {
+		t.Errorf("Expected a rate limited error, got %v", err)
+	}
+	if delay, ok := RequeueAfter(err); !ok || delay != 60*time.Second {
+		t.Errorf("Expected a requeue after %s, got %s, %t", 60*time.Second, delay, ok)
+	}
+	if requests.Load() != 1 {
+		t.Errorf("Expected %d requests, got %d", 1, requests.Load())
+	}
+
+	// Server errors are retried for idempotent requests only
+	requests.Store(0)
+	err = Delete(context.Background(), "foo", client)
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusInternalServerError {
+		t.Errorf("Expected an API error with status %d, got %v", http.StatusInternalServerError, err)
+	}
+	if requests.Load() != 2 {
+		t.Errorf("Expected %d requests, got %d", 2, requests.Load())
+	}
+	if _, ok := RequeueAfter(err); ok {
+		t.Error("Expected the default backoff for server errors")
+	}
+
+	requests.Store(0)
+	_, err = Create(context.Background(), Check{Name: "foo", Endpoint: "https://foo.bar", SuccessCode: "200"}, client)
+	if err == nil {
+		t.Error("Expected error, got none")
+	}
+	if requests.Load() != 1 {
+		t.Errorf("Expected %d requests, got %d", 1, requests.Load())
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		value    string
+		expected time.Duration
+	}{
+		{"", 0},
+		{"120", 2 * time.Minute},
+		{"-1", 0},
+		{"Mon, 01 Jan 2024 12:00:30 GMT", 30 * time.Second},
+		{"Mon, 01 Jan 2024 11:00:00 GMT", 0},
+		{"soon", 0},
+	}
+
+	for _, tc := range testCases {
+		if delay := parseRetryAfter(tc.value, now); delay != tc.expected {
+			t.Errorf("%q: expected %s, got %s", tc.value, tc.expected, delay)
+		}
+	}
+}
+
+func TestStatusFromError(t *testing.T) {
+	if status := statusFromError(errors.New(`unexpected response status 429: "{}"`)); status != http.StatusTooManyRequests {
+		t.Errorf("Expected %d, got %d", http.StatusTooManyRequests, status)
+	}
+
+	if status := statusFromError(errors.New("HTTP request failed with: EOF")); status != 0 {
+		t.Errorf("Expected %d, got %d", 0, status)
+	}
+}
+
+func TestIsNotFound(t *testing.T) {
+	if !isNotFound(&APIError{StatusCode: http.StatusNotFound, Err: errors.New("not found")}) {
+		t.Error("Expected true, got false")
+	}
+
+	// Only the status of the typed error counts, not the message
+	if isNotFound(errors.New(`unexpected response status 404: "{}"`)) {
+		t.Error("Expected false, got true")
+	}
+
+	_, call := startAPICall(context.Background(), resourceCheck, operationGet)
+	if err := call.done(errors.New(`unexpected response status 404: "{}"`)); !isNotFound(err) {
+		t.Errorf("Expected not found error, got %v", err)
+	}
+}
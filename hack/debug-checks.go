@@ -6,10 +6,10 @@ import (
 	"flag"
 	"fmt"
 	"os"
-	"time"
 
-	"github.com/checkly/checkly-go-sdk"
 	ctrl "sigs.k8s.io/controller-runtime"
+
+	external "github.com/checkly/checkly-operator/external/checkly"
 )
 
 // The script returns the data for a specific checkly check from the checklyhq API,
@@ -20,8 +20,15 @@ func main() {
 	setupLog := ctrl.Log.WithName("setup")
 
 	var checklyID string
+	var options external.ClientOptions
+	var debug bool
 
 	flag.StringVar(&checklyID, "c", "", "Specify the checkly check ID")
+	flag.StringVar(&options.BaseURL, "url", external.DefaultBaseURL, "The address of the checklyhq.com API")
+	flag.StringVar(&options.ProxyURL, "proxy", "", "The HTTP proxy used to reach the checklyhq.com API")
+	flag.StringVar(&options.CABundle, "ca-bundle", "", "Path of a PEM file with additional trusted CA certificates")
+	flag.DurationVar(&external.RequestTimeout, "timeout", external.RequestTimeout, "Timeout of the checklyhq.com API call")
+	flag.BoolVar(&debug, "debug", false, "Print the request and response with the credentials redacted")
 	flag.Parse()
 
 	if checklyID == "" {
@@ -29,7 +36,6 @@ func main() {
 		os.Exit(1)
 	}
 
-	baseUrl := "https://api.checklyhq.com"
 	apiKey := os.Getenv("CHECKLY_API_KEY")
 	if apiKey == "" {
 		setupLog.Error(errors.New("checklyhq.com API key environment variable is undefined"), "checklyhq.com credentials missing")
@@ -42,15 +48,18 @@ func main() {
 		os.Exit(1)
 	}
 
-	client := checkly.NewClient(
-		baseUrl,
-		apiKey,
-		nil, //custom http client, defaults to http.DefaultClient
-		nil, //io.Writer to output debug messages
-	)
+	if debug {
+		options.Debug = os.Stderr
+	}
+
+	clientFactory, err := external.NewClientFactory(options)
+	if err != nil {
+		setupLog.Error(err, "failed to set up the checklyhq.com client")
+		os.Exit(1)
+	}
 
-	client.SetAccountId(accountId)
-	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	client := clientFactory.NewClient(apiKey, accountId)
+	ctx, cancel := context.WithTimeout(context.Background(), external.RequestTimeout)
 	defer cancel()
 
 	returnedCheck, err := client.Get(ctx, checklyID)
@@ -0,0 +1,142 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package checkly
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	checklyv1alpha1 "github.com/checkly/checkly-operator/api/checkly/v1alpha1"
+	external "github.com/checkly/checkly-operator/external/checkly"
+)
+
+// AccountClients builds the checkly clients of the ChecklyAccounts and caches them,
+// a client is rebuilt when the ChecklyAccount or its API key secret changes
+type AccountClients struct {
+	reader    client.Reader
+	newClient func(apiKey string, accountID string) external.Client
+
+	mu      sync.Mutex
+	clients map[string]accountClient
+}
+
+type accountClient struct {
+	// version is the resource version of the ChecklyAccount and of the secret the client was built from
+	version string
+	client  external.Client
+}
+
+// NewAccountClients returns an AccountClients reading the ChecklyAccounts with reader and building the clients with newClient
+func NewAccountClients(reader client.Reader, newClient func(apiKey string, accountID string) external.Client) *AccountClients {
+	return &AccountClients{
+		reader:    reader,
+		newClient: newClient,
+		clients:   map[string]accountClient{},
+	}
+}
+
+// Get returns the client of the named ChecklyAccount
+func (a *AccountClients) Get(ctx context.Context, name string) (apiClient external.Client, err error) {
+	account := &checklyv1alpha1.ChecklyAccount{}
+	err = a.reader.Get(ctx, types.NamespacedName{Name: name}, account)
+	if err != nil {
+		return
+	}
+
+	ref := account.Spec.APIKeySecret
+	secret := &corev1.Secret{}
+	err = a.reader.Get(ctx, types.NamespacedName{Name: ref.Name, Namespace: ref.Namespace}, secret)
+	if err != nil {
+		return
+	}
+
+	apiKey, exists := secret.Data[ref.FieldPath]
+	if !exists {
+		err = fmt.Errorf("key %s not found in secret %s", ref.FieldPath, ref.Name)
+		return
+	}
+
+	version := fmt.Sprintf("%s/%s", account.ResourceVersion, secret.ResourceVersion)
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if cached, ok := a.clients[name]; ok && cached.version == version {
+		apiClient = cached.client
+		return
+	}
+
+	apiClient = a.newClient(string(apiKey), account.Spec.AccountID)
+	a.clients[name] = accountClient{
+		version: version,
+		client:  apiClient,
+	}
+
+	return
+}
+
+// accountAPIClient returns the client of the named ChecklyAccount, an empty name returns the default client configured through the environment
+func accountAPIClient(ctx context.Context, accounts *AccountClients, defaultClient external.Client, name string) (apiClient external.Client, err error) {
+	if name == "" {
+		if defaultClient == nil {
+			err = fmt.Errorf("no default checkly account is configured, set the account of the resource")
+			return
+		}
+		apiClient = defaultClient
+		return
+	}
+
+	if accounts == nil {
+		err = fmt.Errorf("ChecklyAccount %s can't be used, account clients are not configured", name)
+		return
+	}
+
+	apiClient, err = accounts.Get(ctx, name)
+	if err != nil {
+		err = fmt.Errorf("ChecklyAccount %s can't be used: %w", name, err)
+	}
+
+	return
+}
+
+// accountDisplayName returns the name of the account used in messages
+func accountDisplayName(name string) string {
+	if name == "" {
+		return "the default account"
+	}
+	return fmt.Sprintf("ChecklyAccount %s", name)
+}
+
+// checkAccount returns the account a check is synced with, which is the account of its group. It fails if the check references
+// another account, or if it was created in another account, created is true if the check exists in checklyhq.com and
+// createdIn holds the account it was created in, checks can't be moved between accounts.
+func checkAccount(group *checklyv1alpha1.Group, account string, created bool, createdIn string) (string, error) {
+	if account != "" && account != group.Spec.Account {
+		return "", fmt.Errorf("check references %s, group %s belongs to %s", accountDisplayName(account), group.Name, accountDisplayName(group.Spec.Account))
+	}
+
+	if created && createdIn != group.Spec.Account {
+		return "", fmt.Errorf("check was created in %s, it can't be moved to group %s which belongs to %s", accountDisplayName(createdIn), group.Name, accountDisplayName(group.Spec.Account))
+	}
+
+	return group.Spec.Account, nil
+}
@@ -0,0 +1,170 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package checkly
+
+import (
+	"context"
+	"testing"
+
+	"github.com/checkly/checkly-go-sdk"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	checklyv1alpha1 "github.com/checkly/checkly-operator/api/checkly/v1alpha1"
+	external "github.com/checkly/checkly-operator/external/checkly"
+)
+
+func TestAccountClients(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := checklyv1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "checkly", Namespace: "checkly-operator-system"},
+		Data:       map[string][]byte{"API_KEY": []byte("foo")},
+	}
+	account := &checklyv1alpha1.ChecklyAccount{
+		ObjectMeta: metav1.ObjectMeta{Name: "staging"},
+		Spec: checklyv1alpha1.ChecklyAccountSpec{
+			AccountID: "1234",
+			APIKeySecret: corev1.ObjectReference{
+				Name:      "checkly",
+				Namespace: "checkly-operator-system",
+				FieldPath: "API_KEY",
+			},
+		},
+	}
+	missingKey := &checklyv1alpha1.ChecklyAccount{
+		ObjectMeta: metav1.ObjectMeta{Name: "production"},
+		Spec: checklyv1alpha1.ChecklyAccountSpec{
+			AccountID: "5678",
+			APIKeySecret: corev1.ObjectReference{
+				Name:      "checkly",
+				Namespace: "checkly-operator-system",
+				FieldPath: "MISSING",
+			},
+		},
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(secret, account, missingKey).Build()
+
+	var built []string
+	accounts := NewAccountClients(c, func(apiKey string, accountID string) external.Client {
+		built = append(built, apiKey+"/"+accountID)
+		return checkly.NewClient("http://localhost", apiKey, nil, nil)
+	})
+
+	first, err := accounts.Get(context.Background(), "staging")
+	if err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+
+	second, err := accounts.Get(context.Background(), "staging")
+	if err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+	if first != second || len(built) != 1 || built[0] != "foo/1234" {
+		t.Errorf("Expected a single cached client built with foo/1234, got %v", built)
+	}
+
+	// A rotated API key builds a new client
+	secret.Data["API_KEY"] = []byte("bar")
+	if err := c.Update(context.Background(), secret); err != nil {
+		t.Fatal(err)
+	}
+	_, err = accounts.Get(context.Background(), "staging")
+	if err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+	if len(built) != 2 || built[1] != "bar/1234" {
+		t.Errorf("Expected a new client built with bar/1234, got %v", built)
+	}
+
+	_, err = accounts.Get(context.Background(), "production")
+	if err == nil {
+		t.Error("Expected error for a missing secret key, got none")
+	}
+
+	_, err = accounts.Get(context.Background(), "missing")
+	if err == nil {
+		t.Error("Expected error for a missing ChecklyAccount, got none")
+	}
+}
+
+func TestAccountAPIClient(t *testing.T) {
+	defaultClient := checkly.NewClient("http://localhost", "foo", nil, nil)
+
+	apiClient, err := accountAPIClient(context.Background(), nil, defaultClient, "")
+	if err != nil || apiClient != defaultClient {
+		t.Errorf("Expected the default client, got %v, %v", apiClient, err)
+	}
+
+	_, err = accountAPIClient(context.Background(), nil, nil, "")
+	if err == nil {
+		t.Error("Expected error without a default client, got none")
+	}
+
+	_, err = accountAPIClient(context.Background(), nil, defaultClient, "staging")
+	if err == nil {
+		t.Error("Expected error without account clients, got none")
+	}
+}
+
+func TestCheckAccount(t *testing.T) {
+	group := &checklyv1alpha1.Group{
+		ObjectMeta: metav1.ObjectMeta{Name: "foo"},
+		Spec:       checklyv1alpha1.GroupSpec{Account: "staging"},
+	}
+
+	testCases := []struct {
+		name      string
+		account   string
+		created   bool
+		createdIn string
+		err       bool
+	}{
+		{"group account", "", false, "", false},
+		{"same account", "staging", false, "", false},
+		{"other account", "production", false, "", true},
+		{"created in group account", "", true, "staging", false},
+		{"created in other account", "", true, "", true},
+	}
+
+	for _, tc := range testCases {
+		account, err := checkAccount(group, tc.account, tc.created, tc.createdIn)
+		if tc.err {
+			if err == nil {
+				t.Errorf("%s: expected error, got none", tc.name)
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("%s: expected no error, got %s", tc.name, err)
+			continue
+		}
+		if account != "staging" {
+			t.Errorf("%s: expected %s, got %s", tc.name, "staging", account)
+		}
+	}
+}
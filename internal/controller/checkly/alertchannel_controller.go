@@ -18,19 +18,21 @@ package checkly
 
 import (
 	"context"
-	errs "errors"
 	"fmt"
+	"strings"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
-	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 
-	"github.com/checkly/checkly-go-sdk"
 	checklyv1alpha1 "github.com/checkly/checkly-operator/api/checkly/v1alpha1"
 	external "github.com/checkly/checkly-operator/external/checkly"
 )
@@ -39,14 +41,25 @@ import (
 type AlertChannelReconciler struct {
 	client.Client
 	Scheme           *runtime.Scheme
-	ApiClient        checkly.Client
+	ApiClient        external.Client
 	ControllerDomain string
+	Recorder         record.EventRecorder
+	// ResyncInterval is how often the resource is compared with checklyhq.com, zero disables the periodic resync
+	ResyncInterval time.Duration
+	// Accounts holds the clients of the ChecklyAccounts, ApiClient is used for resources which don't reference an account
+	Accounts *AccountClients
+	// DeletionPolicy is the default deletion policy of the resources which don't set one, empty deletes them
+	DeletionPolicy string
 }
 
 //+kubebuilder:rbac:groups=k8s.checklyhq.com,resources=alertchannels,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=k8s.checklyhq.com,resources=alertchannels/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=k8s.checklyhq.com,resources=alertchannels/finalizers,verbs=update
-//+kubebuilder:rbac:groups="",resources=secrets,verbs=get;list
+//+kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+//+kubebuilder:rbac:groups=k8s.checklyhq.com,resources=checklyaccounts,verbs=get;list;watch
+//+kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch
+//+kubebuilder:rbac:groups=k8s.checklyhq.com,resources=groups,verbs=get;list;watch;update;patch
+//+kubebuilder:rbac:groups=k8s.checklyhq.com,resources=checkgroups,verbs=get;list;watch;update;patch
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -58,8 +71,6 @@ func (r *AlertChannelReconciler) Reconcile(ctx context.Context, req ctrl.Request
 
 	logger.V(1).Info("Reconciler started")
 
-	acFinalizer := fmt.Sprintf("%s/finalizer", r.ControllerDomain)
-
 	ac := &checklyv1alpha1.AlertChannel{}
 
 	err := r.Get(ctx, req.NamespacedName, ac)
@@ -78,23 +89,88 @@ func (r *AlertChannelReconciler) Reconcile(ctx context.Context, req ctrl.Request
 		return ctrl.Result{}, nil
 	}
 
+	return r.reconcileAlertChannel(ctx, ac, &ac.Spec, &ac.Status)
+}
+
+// reconcileAlertChannel syncs an AlertChannel or a NamespacedAlertChannel with checklyhq.com, obj is the resource itself,
+// spec and status point into it
+func (r *AlertChannelReconciler) reconcileAlertChannel(ctx context.Context, obj client.Object, spec *checklyv1alpha1.AlertChannelSpec, status *checklyv1alpha1.AlertChannelStatus) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	acFinalizer := fmt.Sprintf("%s/finalizer", r.ControllerDomain)
+
+	// The external package works with AlertChannel resources, a NamespacedAlertChannel is passed as one
+	ac := &checklyv1alpha1.AlertChannel{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      obj.GetName(),
+			Namespace: obj.GetNamespace(),
+		},
+		Spec:   *spec,
+		Status: *status,
+	}
+
+	// An abandoned or never synced alert channel doesn't need the client of its account, which may be gone already
+	if obj.GetDeletionTimestamp() != nil && controllerutil.ContainsFinalizer(obj, acFinalizer) {
+		released, err := releaseWithoutRemoteDeletion(ctx, r.Client, r.Recorder, obj, acFinalizer, r.ControllerDomain, status.ID != 0)
+		if released || err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	// /////////////////////////////
+	// Account logic
+	// ////////////////////////////
+	apiClient, err := accountAPIClient(ctx, r.Accounts, r.ApiClient, spec.Account)
+	if err != nil {
+		logger.Error(err, "Unable to get the checkly client of the account", "account", spec.Account)
+		setDependenciesNotResolved(&status.SyncStatus, obj.GetGeneration(), checklyv1alpha1.ReasonDependencyNotFound, err.Error())
+		recordEvent(r.Recorder, obj, corev1.EventTypeWarning, eventReasonWaitingForDependency, err.Error())
+		updateStatus(ctx, r.Client, obj)
+		return ctrl.Result{}, err
+	}
+
 	// ////////////////////////////////
 	// Remove Finalizer Logic
 	// ///////////////////////////////
 
-	if ac.GetDeletionTimestamp() != nil {
-		if controllerutil.ContainsFinalizer(ac, acFinalizer) {
-			logger.V(1).Info("Finalizer is present, trying to delete Checkly AlertChannel", "ID", ac.Status.ID)
-			err := external.DeleteAlertChannel(ac, r.ApiClient)
-			if err != nil {
-				logger.Error(err, "Failed to delete checkly AlertChannel")
-				return ctrl.Result{}, err
-			}
+	if obj.GetDeletionTimestamp() != nil {
+		if controllerutil.ContainsFinalizer(obj, acFinalizer) {
+			// Alert channels can't be tagged in checklyhq.com, a retained alert channel is kept as it is
+			if policy := effectiveDeletionPolicy(spec.DeletionPolicy, r.DeletionPolicy); policy != checklyv1alpha1.DeletionPolicyDelete {
+				logger.Info("Orphaned checkly AlertChannel", "ID", status.ID, "policy", policy)
+				if policy == checklyv1alpha1.DeletionPolicyRetain {
+					recordEvent(r.Recorder, obj, corev1.EventTypeWarning, eventReasonOrphaned, fmt.Sprintf("Left checkly alert channel %d in checklyhq.com, alert channels can't be tagged so the Retain deletion policy falls back to Orphan", status.ID))
+				} else {
+					recordEvent(r.Recorder, obj, corev1.EventTypeNormal, eventReasonOrphaned, fmt.Sprintf("Left checkly alert channel %d in checklyhq.com", status.ID))
+				}
+			} else {
+				// The groups subscribed to the alert channel have to drop it first
+				dependents, err := alertChannelDependents(ctx, r.Client, obj)
+				if err != nil {
+					logger.Error(err, "Failed to list the groups of the alert channel")
+					return ctrl.Result{}, err
+				}
+				if len(dependents) > 0 {
+					proceed, err := r.handleAlertChannelDependents(ctx, obj, spec, status, dependents)
+					if !proceed {
+						return ctrl.Result{}, err
+					}
+				}
 
-			logger.V(1).Info("Successfully deleted checkly AlertChannel", "ID", ac.Status.ID)
+				logger.V(1).Info("Finalizer is present, trying to delete Checkly AlertChannel", "ID", status.ID)
+				err = external.DeleteAlertChannel(ctx, ac, apiClient)
+				if err != nil {
+					logger.Error(err, "Failed to delete checkly AlertChannel")
+					recordEvent(r.Recorder, obj, corev1.EventTypeWarning, eventReasonDeleteFailed, fmt.Sprintf("Failed to delete checkly alert channel %d: %s", status.ID, err))
+					return syncFailedResult(err)
+				}
+
+				logger.V(1).Info("Successfully deleted checkly AlertChannel", "ID", status.ID)
+				recordEvent(r.Recorder, obj, corev1.EventTypeNormal, eventReasonDeleted, fmt.Sprintf("Deleted checkly alert channel %d", status.ID))
+			}
 
-			controllerutil.RemoveFinalizer(ac, acFinalizer)
-			err = r.Update(ctx, ac)
+			controllerutil.RemoveFinalizer(obj, acFinalizer)
+			err = r.Update(ctx, obj)
 			if err != nil {
 				logger.Error(err, "Failed to delete finalizer.")
 				return ctrl.Result{}, err
@@ -107,47 +183,32 @@ func (r *AlertChannelReconciler) Reconcile(ctx context.Context, req ctrl.Request
 	// /////////////////////////////
 	// Add Finalizer logic
 	// ////////////////////////////
-	if !controllerutil.ContainsFinalizer(ac, acFinalizer) {
-		controllerutil.AddFinalizer(ac, acFinalizer)
-		err = r.Update(ctx, ac)
+	if !controllerutil.ContainsFinalizer(obj, acFinalizer) {
+		controllerutil.AddFinalizer(obj, acFinalizer)
+		err := r.Update(ctx, obj)
 		if err != nil {
 			logger.Error(err, "Failed to update AlertChannel status")
 			return ctrl.Result{}, err
 		}
-		logger.V(1).Info("Added finalizer", "checkly AlertChannel ID", ac.Status.ID)
-		return ctrl.Result{}, nil
+		logger.V(1).Info("Added finalizer", "checkly AlertChannel ID", status.ID)
+		return ctrl.Result{Requeue: true}, nil
 	}
 
 	// /////////////////////////////
-	// OpsGenie logic + secret retrieval
+	// Secret retrieval
 	// ////////////////////////////
-	opsGenieConfig := checkly.AlertChannelOpsgenie{}
-	if ac.Spec.OpsGenie.APISecret != (corev1.ObjectReference{}) {
-		secret := &corev1.Secret{}
-		err := r.Get(ctx,
-			types.NamespacedName{
-				Name:      ac.Spec.OpsGenie.APISecret.Name,
-				Namespace: ac.Spec.OpsGenie.APISecret.Namespace},
-			secret)
-		if err != nil {
-			logger.Error(err, "Unable to read secret for API Key")
-			return ctrl.Result{}, err
-		}
-
-		secretValue := string(secret.Data[ac.Spec.OpsGenie.APISecret.FieldPath])
-		if secretValue == "" {
-			secretErr := errs.New("secret value is empty")
-			logger.Error(secretErr, "Please add Opsgenie secret")
-			return ctrl.Result{}, err
-		}
-
-		opsGenieConfig = checkly.AlertChannelOpsgenie{
-			Name:     ac.Name,
-			APIKey:   secretValue,
-			Region:   ac.Spec.OpsGenie.Region,
-			Priority: ac.Spec.OpsGenie.Priority,
-		}
-
+	config, err := r.alertChannelConfig(ctx, ac)
+	if err != nil {
+		logger.Error(err, "Unable to read the alert channel secrets")
+		setDependenciesNotResolved(&status.SyncStatus, obj.GetGeneration(), checklyv1alpha1.ReasonDependencyNotFound, err.Error())
+		recordEvent(r.Recorder, obj, corev1.EventTypeWarning, eventReasonWaitingForDependency, err.Error())
+		updateStatus(ctx, r.Client, obj)
+		return ctrl.Result{}, err
+	}
+	secretsHash, err := alertChannelSecretsHash(config)
+	if err != nil {
+		logger.Error(err, "Unable to hash the alert channel secrets")
+		return ctrl.Result{}, err
 	}
 
 	// /////////////////////////////
@@ -155,42 +216,109 @@ func (r *AlertChannelReconciler) Reconcile(ctx context.Context, req ctrl.Request
 	// ////////////////////////////
 
 	// Determine if it's a new object or if it's an update to an existing object
-	if ac.Status.ID != 0 {
+	if status.ID != 0 {
 		// Existing object, we need to update it
-		logger.V(1).Info("Existing object, with ID", "checkly AlertChannel ID", ac.Status.ID)
-		err := external.UpdateAlertChannel(ac, opsGenieConfig, r.ApiClient)
+		logger.V(1).Info("Existing object, with ID", "checkly AlertChannel ID", status.ID)
+		drift, found, err := external.AlertChannelDrift(ctx, ac, config, apiClient)
 		if err != nil {
-			logger.Error(err, "Failed to update checkly AlertChannel")
-			return ctrl.Result{}, err
+			logger.Error(err, "Failed to get checkly AlertChannel")
+			setSyncFailed(&status.SyncStatus, obj.GetGeneration(), err)
+			recordEvent(r.Recorder, obj, corev1.EventTypeWarning, eventReasonSyncFailed, fmt.Sprintf("Failed to sync with checklyhq.com: %s", err))
+			updateStatus(ctx, r.Client, obj)
+			return syncFailedResult(err)
 		}
-		logger.V(1).Info("Updated checkly AlertChannel", "ID", ac.Status.ID)
-		return ctrl.Result{}, nil
+
+		if found {
+			if len(drift) != 0 {
+				logger.Info("Checkly AlertChannel changed outside of the operator, overwriting", "ID", status.ID, "fields", drift)
+				recordEvent(r.Recorder, obj, corev1.EventTypeWarning, eventReasonDriftCorrected, fmt.Sprintf("Checkly alert channel %d was changed outside of the operator, reverting: %s", status.ID, strings.Join(drift, ", ")))
+			}
+			// A resync of an unchanged alert channel only compares it, updating it anyway would spend the rate limit of the account
+			if len(drift) != 0 || !isSynced(&status.SyncStatus, obj.GetGeneration()) || status.SecretsHash != secretsHash {
+				err = external.UpdateAlertChannel(ctx, ac, config, apiClient)
+				if err != nil {
+					logger.Error(err, "Failed to update checkly AlertChannel")
+					setSyncFailed(&status.SyncStatus, obj.GetGeneration(), err)
+					recordEvent(r.Recorder, obj, corev1.EventTypeWarning, eventReasonSyncFailed, fmt.Sprintf("Failed to sync with checklyhq.com: %s", err))
+					updateStatus(ctx, r.Client, obj)
+					return syncFailedResult(err)
+				}
+				logger.V(1).Info("Updated checkly AlertChannel", "ID", status.ID)
+				switch {
+				case !isSynced(&status.SyncStatus, obj.GetGeneration()):
+					recordEvent(r.Recorder, obj, corev1.EventTypeNormal, eventReasonUpdated, fmt.Sprintf("Updated checkly alert channel %d", status.ID))
+				case status.SecretsHash != "" && status.SecretsHash != secretsHash:
+					logger.Info("Referenced secret changed, updated checkly AlertChannel", "ID", status.ID)
+					recordEvent(r.Recorder, obj, corev1.EventTypeNormal, eventReasonSecretRotated, fmt.Sprintf("Updated checkly alert channel %d with the rotated secret", status.ID))
+				}
+			}
+
+			status.SecretsHash = secretsHash
+			setDrift(&status.SyncStatus, obj.GetGeneration(), drift)
+			setSynced(&status.SyncStatus, obj.GetGeneration())
+			err = r.Status().Update(ctx, obj)
+			if err != nil {
+				logger.Error(err, "Failed to update AlertChannel status", "ID", status.ID)
+				return ctrl.Result{}, err
+			}
+			return ctrl.Result{RequeueAfter: r.ResyncInterval}, nil
+		}
+
+		// The alert channel was deleted outside of the operator, we create it again, the groups pick up the new ID on their next reconcile
+		logger.Info("Checkly AlertChannel not found, recreating", "ID", status.ID)
+		recordEvent(r.Recorder, obj, corev1.EventTypeWarning, eventReasonRecreated, fmt.Sprintf("Checkly alert channel %d was deleted outside of the operator, recreating", status.ID))
+		setRecreated(&status.SyncStatus, obj.GetGeneration())
 	}
 
 	// /////////////////////////////
 	// Create logic
 	// ////////////////////////////
-	acID, err := external.CreateAlertChannel(ac, opsGenieConfig, r.ApiClient)
+	acID, err := external.CreateAlertChannel(ctx, ac, config, apiClient)
 	if err != nil {
 		logger.Error(err, "Failed to create checkly AlertChannel")
-		return ctrl.Result{}, err
+		setSyncFailed(&status.SyncStatus, obj.GetGeneration(), err)
+		recordEvent(r.Recorder, obj, corev1.EventTypeWarning, eventReasonSyncFailed, fmt.Sprintf("Failed to sync with checklyhq.com: %s", err))
+		updateStatus(ctx, r.Client, obj)
+		return syncFailedResult(err)
 	}
 
 	// Update the custom resource Status with the returned ID
-	ac.Status.ID = acID
-	err = r.Status().Update(ctx, ac)
+	status.ID = acID
+	status.SecretsHash = secretsHash
+	setSynced(&status.SyncStatus, obj.GetGeneration())
+	err = r.Status().Update(ctx, obj)
 	if err != nil {
-		logger.Error(err, "Failed to update AlertChannel status", "ID", ac.Status.ID)
+		logger.Error(err, "Failed to update AlertChannel status", "ID", status.ID)
 		return ctrl.Result{}, err
 	}
-	logger.V(1).Info("New checkly AlertChannel created", "ID", ac.Status.ID)
+	logger.V(1).Info("New checkly AlertChannel created", "ID", status.ID)
+	recordEvent(r.Recorder, obj, corev1.EventTypeNormal, eventReasonCreated, fmt.Sprintf("Created checkly alert channel %d", status.ID))
 
-	return ctrl.Result{}, nil
+	return ctrl.Result{RequeueAfter: r.ResyncInterval}, nil
 }
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *AlertChannelReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	err := mgr.GetFieldIndexer().IndexField(context.Background(), &checklyv1alpha1.AlertChannel{}, alertChannelSecretIndex, func(rawObj client.Object) []string {
+		return alertChannelSecretIndexValues(rawObj.(*checklyv1alpha1.AlertChannel))
+	})
+	if err != nil {
+		return err
+	}
+
 	return ctrl.NewControllerManagedBy(mgr).
-		For(&checklyv1alpha1.AlertChannel{}).
+		For(&checklyv1alpha1.AlertChannel{}, specChanged).
+		Watches(
+			&corev1.Secret{},
+			handler.EnqueueRequestsFromMapFunc(r.findAlertChannelsForSecret),
+		).
+		Watches(
+			&checklyv1alpha1.Group{},
+			handler.EnqueueRequestsFromMapFunc(r.findDeletingAlertChannelsForGroup),
+		).
+		Watches(
+			&checklyv1alpha1.CheckGroup{},
+			handler.EnqueueRequestsFromMapFunc(r.findDeletingAlertChannelsForGroup),
+		).
 		Complete(r)
 }
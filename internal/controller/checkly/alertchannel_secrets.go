@@ -0,0 +1,305 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package checkly
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	"github.com/checkly/checkly-go-sdk"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	checklyv1alpha1 "github.com/checkly/checkly-operator/api/checkly/v1alpha1"
+	external "github.com/checkly/checkly-operator/external/checkly"
+)
+
+// alertChannelSecretIndex is the field index used to find AlertChannels referencing a Secret, the values are namespace/name
+const alertChannelSecretIndex = ".spec.secretRefs"
+
+// alertChannelConfig builds the configuration of the alert channel types which read their credentials or URLs from secrets,
+// the secrets of a NamespacedAlertChannel are read from its own namespace
+func (r *AlertChannelReconciler) alertChannelConfig(ctx context.Context, ac *checklyv1alpha1.AlertChannel) (config external.AlertChannelConfig, err error) {
+	if ac.Spec.OpsGenie.APISecret != (corev1.ObjectReference{}) {
+		var apiKey string
+		apiKey, err = r.secretValue(ctx, ac.Namespace, ac.Spec.OpsGenie.APISecret)
+		if err != nil {
+			return
+		}
+		config.Opsgenie = checkly.AlertChannelOpsgenie{
+			Name:     ac.Name,
+			APIKey:   apiKey,
+			Region:   ac.Spec.OpsGenie.Region,
+			Priority: ac.Spec.OpsGenie.Priority,
+		}
+	}
+
+	if ac.Spec.Slack != nil {
+		var URL string
+		URL, err = r.secretValue(ctx, ac.Namespace, ac.Spec.Slack.URLSecret)
+		if err != nil {
+			return
+		}
+		config.Slack = &checkly.AlertChannelSlack{
+			WebhookURL: URL,
+			Channel:    ac.Spec.Slack.Channel,
+		}
+	}
+
+	if ac.Spec.PagerDuty != nil {
+		var serviceKey string
+		serviceKey, err = r.secretValue(ctx, ac.Namespace, ac.Spec.PagerDuty.ServiceKeySecret)
+		if err != nil {
+			return
+		}
+		config.Pagerduty = &checkly.AlertChannelPagerduty{
+			ServiceKey:  serviceKey,
+			Account:     ac.Spec.PagerDuty.Account,
+			ServiceName: ac.Spec.PagerDuty.ServiceName,
+		}
+	}
+
+	if ac.Spec.MSTeams != nil {
+		var URL string
+		URL, err = r.secretValue(ctx, ac.Namespace, ac.Spec.MSTeams.URLSecret)
+		if err != nil {
+			return
+		}
+		config.MSTeams = external.MSTeamsWebhook(ac.Name, URL)
+	}
+
+	if ac.Spec.Webhook != nil {
+		webhook := &checkly.AlertChannelWebhook{
+			Name:     ac.Name,
+			Method:   ac.Spec.Webhook.Method,
+			Template: ac.Spec.Webhook.Template,
+		}
+		if webhook.Method == "" {
+			webhook.Method = "POST"
+		}
+		webhook.URL, err = r.secretValue(ctx, ac.Namespace, ac.Spec.Webhook.URLSecret)
+		if err != nil {
+			return
+		}
+		if ac.Spec.Webhook.WebhookSecret != nil {
+			webhook.WebhookSecret, err = r.secretValue(ctx, ac.Namespace, *ac.Spec.Webhook.WebhookSecret)
+			if err != nil {
+				return
+			}
+		}
+		webhook.Headers, err = r.resolveKeyValues(ctx, ac.Namespace, ac.Spec.Webhook.Headers)
+		if err != nil {
+			return
+		}
+		webhook.QueryParameters, err = r.resolveKeyValues(ctx, ac.Namespace, ac.Spec.Webhook.QueryParameters)
+		if err != nil {
+			return
+		}
+		config.Webhook = webhook
+	}
+
+	return
+}
+
+// resolveKeyValues turns the webhook key value pairs into checkly key values, reading the secret references.
+// Values read from secrets are locked so they're hidden in the checklyhq.com UI.
+func (r *AlertChannelReconciler) resolveKeyValues(ctx context.Context, namespace string, keyValues []checklyv1alpha1.AlertChannelKeyValue) (resolved []checkly.KeyValue, err error) {
+	for _, keyValue := range keyValues {
+		if keyValue.ValueFrom == nil {
+			resolved = append(resolved, checkly.KeyValue{
+				Key:   keyValue.Key,
+				Value: keyValue.Value,
+			})
+			continue
+		}
+
+		var value string
+		value, err = r.secretValue(ctx, namespace, *keyValue.ValueFrom)
+		if err != nil {
+			return
+		}
+		resolved = append(resolved, checkly.KeyValue{
+			Key:    keyValue.Key,
+			Value:  value,
+			Locked: true,
+		})
+	}
+
+	return
+}
+
+// secretValue reads the key set in the fieldPath of the secret reference, namespace is the namespace of a NamespacedAlertChannel
+// and empty for a cluster scoped AlertChannel
+func (r *AlertChannelReconciler) secretValue(ctx context.Context, namespace string, ref corev1.ObjectReference) (value string, err error) {
+	secretName, err := secretNamespacedName(ref, namespace)
+	if err != nil {
+		return
+	}
+
+	secret := &corev1.Secret{}
+	err = r.Get(ctx, secretName, secret)
+	if err != nil {
+		return
+	}
+
+	secretValue, exists := secret.Data[ref.FieldPath]
+	if !exists {
+		err = fmt.Errorf("key %s not found in secret %s", ref.FieldPath, ref.Name)
+		return
+	}
+	if len(secretValue) == 0 {
+		err = fmt.Errorf("key %s of secret %s is empty", ref.FieldPath, ref.Name)
+		return
+	}
+
+	value = string(secretValue)
+
+	return
+}
+
+// secretNamespacedName returns the name of the referenced secret. A NamespacedAlertChannel can only read secrets from its own namespace,
+// the namespace of the reference can be left empty, a cluster scoped AlertChannel has to set it.
+func secretNamespacedName(ref corev1.ObjectReference, namespace string) (name types.NamespacedName, err error) {
+	name = types.NamespacedName{Name: ref.Name, Namespace: ref.Namespace}
+	if namespace == "" {
+		return
+	}
+
+	if ref.Namespace != "" && ref.Namespace != namespace {
+		err = fmt.Errorf("secret %s/%s is not in namespace %s, namespaced alert channels can only read secrets from their own namespace", ref.Namespace, ref.Name, namespace)
+		return
+	}
+	name.Namespace = namespace
+
+	return
+}
+
+// alertChannelSecretsHash hashes the configuration read from the secrets, so a rotated secret can be told apart from a resync
+func alertChannelSecretsHash(config external.AlertChannelConfig) (hash string, err error) {
+	return hashValues(config)
+}
+
+// hashValues returns the SHA-256 hash of the JSON encoding of values
+func hashValues(values any) (hash string, err error) {
+	data, err := json.Marshal(values)
+	if err != nil {
+		return
+	}
+
+	sum := sha256.Sum256(data)
+	hash = hex.EncodeToString(sum[:])
+
+	return
+}
+
+// alertChannelSecretReferences returns all secrets the alert channel reads its configuration from
+func alertChannelSecretReferences(ac *checklyv1alpha1.AlertChannel) (refs []corev1.ObjectReference) {
+	if ac.Spec.OpsGenie.APISecret != (corev1.ObjectReference{}) {
+		refs = append(refs, ac.Spec.OpsGenie.APISecret)
+	}
+	if ac.Spec.Slack != nil {
+		refs = append(refs, ac.Spec.Slack.URLSecret)
+	}
+	if ac.Spec.PagerDuty != nil {
+		refs = append(refs, ac.Spec.PagerDuty.ServiceKeySecret)
+	}
+	if ac.Spec.MSTeams != nil {
+		refs = append(refs, ac.Spec.MSTeams.URLSecret)
+	}
+	if ac.Spec.Webhook != nil {
+		refs = append(refs, ac.Spec.Webhook.URLSecret)
+		if ac.Spec.Webhook.WebhookSecret != nil {
+			refs = append(refs, *ac.Spec.Webhook.WebhookSecret)
+		}
+		for _, keyValue := range ac.Spec.Webhook.Headers {
+			if keyValue.ValueFrom != nil {
+				refs = append(refs, *keyValue.ValueFrom)
+			}
+		}
+		for _, keyValue := range ac.Spec.Webhook.QueryParameters {
+			if keyValue.ValueFrom != nil {
+				refs = append(refs, *keyValue.ValueFrom)
+			}
+		}
+	}
+
+	return
+}
+
+// alertChannelSecretIndexValues returns the namespace/name of every secret referenced by the alert channel, without duplicates
+func alertChannelSecretIndexValues(ac *checklyv1alpha1.AlertChannel) (values []string) {
+	for _, ref := range alertChannelSecretReferences(ac) {
+		name, err := secretNamespacedName(ref, ac.Namespace)
+		if err != nil {
+			continue
+		}
+		value := name.String()
+		if !slices.Contains(values, value) {
+			values = append(values, value)
+		}
+	}
+
+	return
+}
+
+// findAlertChannelsForSecret maps a Secret to the AlertChannels which read their configuration from it
+func (r *AlertChannelReconciler) findAlertChannelsForSecret(ctx context.Context, secret client.Object) []reconcile.Request {
+	alertChannels := &checklyv1alpha1.AlertChannelList{}
+	err := r.List(ctx, alertChannels,
+		client.MatchingFields{alertChannelSecretIndex: types.NamespacedName{Name: secret.GetName(), Namespace: secret.GetNamespace()}.String()})
+	if err != nil {
+		return []reconcile.Request{}
+	}
+
+	requests := make([]reconcile.Request, len(alertChannels.Items))
+	for i, item := range alertChannels.Items {
+		requests[i] = reconcile.Request{
+			NamespacedName: types.NamespacedName{
+				Name: item.Name,
+			},
+		}
+	}
+	return requests
+}
+
+// findNamespacedAlertChannelsForSecret maps a Secret to the NamespacedAlertChannels in its namespace which read their configuration from it
+func (r *NamespacedAlertChannelReconciler) findNamespacedAlertChannelsForSecret(ctx context.Context, secret client.Object) []reconcile.Request {
+	alertChannels := &checklyv1alpha1.NamespacedAlertChannelList{}
+	err := r.List(ctx, alertChannels,
+		client.InNamespace(secret.GetNamespace()),
+		client.MatchingFields{alertChannelSecretIndex: types.NamespacedName{Name: secret.GetName(), Namespace: secret.GetNamespace()}.String()})
+	if err != nil {
+		return []reconcile.Request{}
+	}
+
+	requests := make([]reconcile.Request, len(alertChannels.Items))
+	for i, item := range alertChannels.Items {
+		requests[i] = reconcile.Request{
+			NamespacedName: types.NamespacedName{
+				Name:      item.Name,
+				Namespace: item.Namespace,
+			},
+		}
+	}
+	return requests
+}
@@ -0,0 +1,154 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package checkly
+
+import (
+	"context"
+	"testing"
+
+	"github.com/checkly/checkly-go-sdk"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	checklyv1alpha1 "github.com/checkly/checkly-operator/api/checkly/v1alpha1"
+	external "github.com/checkly/checkly-operator/external/checkly"
+)
+
+func TestAlertChannelSecretIndexValues(t *testing.T) {
+	secret := corev1.ObjectReference{
+		Name:      "foo",
+		Namespace: "bar",
+		FieldPath: "URL",
+	}
+	token := corev1.ObjectReference{
+		Name:      "baz",
+		Namespace: "bar",
+		FieldPath: "TOKEN",
+	}
+
+	ac := &checklyv1alpha1.AlertChannel{
+		ObjectMeta: metav1.ObjectMeta{Name: "foo"},
+		Spec: checklyv1alpha1.AlertChannelSpec{
+			Email: checkly.AlertChannelEmail{Address: "foo@bar.baz"},
+		},
+	}
+	if values := alertChannelSecretIndexValues(ac); len(values) != 0 {
+		t.Errorf("Expected no secrets, got %v", values)
+	}
+
+	ac.Spec = checklyv1alpha1.AlertChannelSpec{
+		Webhook: &checklyv1alpha1.AlertChannelWebhook{
+			URLSecret: secret,
+			Headers: []checklyv1alpha1.AlertChannelKeyValue{
+				{Key: "Authorization", ValueFrom: &token},
+				{Key: "X-Foo", Value: "bar"},
+			},
+			QueryParameters: []checklyv1alpha1.AlertChannelKeyValue{
+				{Key: "token", ValueFrom: &token},
+			},
+		},
+	}
+	values := alertChannelSecretIndexValues(ac)
+	expected := []string{"bar/foo", "bar/baz"}
+	if len(values) != len(expected) || values[0] != expected[0] || values[1] != expected[1] {
+		t.Errorf("Expected %v, got %v", expected, values)
+	}
+
+	ac.Spec = checklyv1alpha1.AlertChannelSpec{
+		OpsGenie: checklyv1alpha1.AlertChannelOpsGenie{APISecret: secret},
+	}
+	values = alertChannelSecretIndexValues(ac)
+	if len(values) != 1 || values[0] != "bar/foo" {
+		t.Errorf("Expected %v, got %v", []string{"bar/foo"}, values)
+	}
+}
+
+func TestAlertChannelSecretsHash(t *testing.T) {
+	config := external.AlertChannelConfig{
+		Slack: &checkly.AlertChannelSlack{
+			WebhookURL: "https://hooks.slack.com/services/foo",
+			Channel:    "#alerts",
+		},
+	}
+
+	hash, err := alertChannelSecretsHash(config)
+	if err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+
+	sameHash, _ := alertChannelSecretsHash(config)
+	if hash != sameHash {
+		t.Errorf("Expected the same hash for the same config, got %s and %s", hash, sameHash)
+	}
+
+	config.Slack.WebhookURL = "https://hooks.slack.com/services/bar"
+	rotatedHash, _ := alertChannelSecretsHash(config)
+	if hash == rotatedHash {
+		t.Error("Expected a different hash after the secret changed")
+	}
+}
+
+func TestSecretValue(t *testing.T) {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "slack", Namespace: "default"},
+		Data: map[string][]byte{
+			"url":   []byte("https://hooks.slack.com/services/foo"),
+			"empty": {},
+		},
+	}
+	r := &AlertChannelReconciler{Client: fake.NewClientBuilder().WithScheme(clientgoscheme.Scheme).WithObjects(secret).Build()}
+	ctx := context.Background()
+
+	value, err := r.secretValue(ctx, "", corev1.ObjectReference{Name: "slack", Namespace: "default", FieldPath: "url"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+	if value != "https://hooks.slack.com/services/foo" {
+		t.Errorf("Expected %s, got %s", "https://hooks.slack.com/services/foo", value)
+	}
+
+	for _, key := range []string{"empty", "missing"} {
+		if _, err := r.secretValue(ctx, "", corev1.ObjectReference{Name: "slack", Namespace: "default", FieldPath: key}); err == nil {
+			t.Errorf("Expected error for key %s, got none", key)
+		}
+	}
+}
+
+func TestSecretNamespacedName(t *testing.T) {
+	ref := corev1.ObjectReference{Name: "foo", Namespace: "bar", FieldPath: "URL"}
+
+	name, err := secretNamespacedName(ref, "")
+	if err != nil || name.String() != "bar/foo" {
+		t.Errorf("Expected bar/foo, got %s, %v", name, err)
+	}
+
+	name, err = secretNamespacedName(ref, "bar")
+	if err != nil || name.String() != "bar/foo" {
+		t.Errorf("Expected bar/foo, got %s, %v", name, err)
+	}
+
+	name, err = secretNamespacedName(corev1.ObjectReference{Name: "foo"}, "baz")
+	if err != nil || name.String() != "baz/foo" {
+		t.Errorf("Expected baz/foo, got %s, %v", name, err)
+	}
+
+	if _, err = secretNamespacedName(ref, "baz"); err == nil {
+		t.Error("Expected error for a secret in another namespace, got none")
+	}
+}
@@ -0,0 +1,53 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package checkly
+
+import (
+	"github.com/checkly/checkly-go-sdk"
+
+	checklyv1alpha1 "github.com/checkly/checkly-operator/api/checkly/v1alpha1"
+	external "github.com/checkly/checkly-operator/external/checkly"
+)
+
+// checklyAlertSettings turns the alert settings of a resource into checkly alert settings, unset values fall back to the defaults
+func checklyAlertSettings(settings *checklyv1alpha1.AlertSettings) (alertSettings *checkly.AlertSettings, useGlobalAlertSettings bool) {
+	defaults := external.DefaultAlertSettings()
+	alertSettings = &defaults
+	if settings == nil {
+		return
+	}
+
+	alertSettings.EscalationType = valueOrDefault(settings.EscalationType, alertSettings.EscalationType)
+	alertSettings.RunBasedEscalation.FailedRunThreshold = valueOrDefault(settings.FailedRunThreshold, alertSettings.RunBasedEscalation.FailedRunThreshold)
+	alertSettings.TimeBasedEscalation.MinutesFailingThreshold = valueOrDefault(settings.MinutesFailingThreshold, alertSettings.TimeBasedEscalation.MinutesFailingThreshold)
+	alertSettings.Reminders.Amount = settings.ReminderAmount
+	alertSettings.Reminders.Interval = valueOrDefault(settings.ReminderInterval, alertSettings.Reminders.Interval)
+	alertSettings.SSLCertificates.Enabled = settings.SSLCertificates
+	alertSettings.SSLCertificates.AlertThreshold = valueOrDefault(settings.SSLCertificatesThreshold, alertSettings.SSLCertificates.AlertThreshold)
+	useGlobalAlertSettings = settings.UseGlobalAlertSettings
+
+	return
+}
+
+// valueOrDefault returns the value, or the default if the value is not set
+func valueOrDefault[T comparable](value T, defaultValue T) T {
+	var zero T
+	if value == zero {
+		return defaultValue
+	}
+	return value
+}
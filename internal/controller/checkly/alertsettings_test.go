@@ -0,0 +1,66 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package checkly
+
+import (
+	"reflect"
+	"testing"
+
+	checklyv1alpha1 "github.com/checkly/checkly-operator/api/checkly/v1alpha1"
+	external "github.com/checkly/checkly-operator/external/checkly"
+)
+
+func TestChecklyAlertSettings(t *testing.T) {
+	defaults := external.DefaultAlertSettings()
+
+	testValue, useGlobal := checklyAlertSettings(nil)
+	if !reflect.DeepEqual(*testValue, defaults) {
+		t.Errorf("Expected %v, got %v", defaults, *testValue)
+	}
+	if useGlobal {
+		t.Errorf("Expected %t, got %t", false, useGlobal)
+	}
+
+	data := checklyv1alpha1.AlertSettings{
+		UseGlobalAlertSettings:  true,
+		EscalationType:          "TIME_BASED",
+		MinutesFailingThreshold: 10,
+		ReminderAmount:          2,
+		SSLCertificates:         true,
+	}
+
+	testValue, useGlobal = checklyAlertSettings(&data)
+	if !useGlobal {
+		t.Errorf("Expected %t, got %t", true, useGlobal)
+	}
+
+	if testValue.EscalationType != data.EscalationType {
+		t.Errorf("Expected %s, got %s", data.EscalationType, testValue.EscalationType)
+	}
+
+	if testValue.TimeBasedEscalation.MinutesFailingThreshold != data.MinutesFailingThreshold {
+		t.Errorf("Expected %d, got %d", data.MinutesFailingThreshold, testValue.TimeBasedEscalation.MinutesFailingThreshold)
+	}
+
+	if testValue.RunBasedEscalation.FailedRunThreshold != defaults.RunBasedEscalation.FailedRunThreshold {
+		t.Errorf("Expected %d, got %d", defaults.RunBasedEscalation.FailedRunThreshold, testValue.RunBasedEscalation.FailedRunThreshold)
+	}
+
+	if testValue.Reminders.Amount != data.ReminderAmount {
+		t.Errorf("Expected %d, got %d", data.ReminderAmount, testValue.Reminders.Amount)
+	}
+}
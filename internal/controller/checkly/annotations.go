@@ -0,0 +1,61 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package checkly
+
+import (
+	"fmt"
+	"strconv"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// adoptIDAnnotation holds the ID of an existing checklyhq.com resource the operator should take ownership of instead of creating a new one
+func adoptIDAnnotation(controllerDomain string) string {
+	return fmt.Sprintf("%s/adopt-id", controllerDomain)
+}
+
+// getAdoptID returns the checklyhq.com ID set in the adopt-id annotation of the object
+func getAdoptID(obj client.Object, controllerDomain string) (ID string, ok bool) {
+	ID, ok = obj.GetAnnotations()[adoptIDAnnotation(controllerDomain)]
+	return
+}
+
+// abandonAnnotation lets a deleted resource go without touching its checklyhq.com resource, ex. when its account was removed
+func abandonAnnotation(controllerDomain string) string {
+	return fmt.Sprintf("%s/abandon", controllerDomain)
+}
+
+// isAbandoned determines if the abandon annotation of the object is set to true
+func isAbandoned(obj client.Object, controllerDomain string) bool {
+	abandon, _ := strconv.ParseBool(obj.GetAnnotations()[abandonAnnotation(controllerDomain)])
+	return abandon
+}
+
+// getAdoptGroupID returns the checklyhq.com group ID set in the adopt-id annotation of the object, group IDs are numeric
+func getAdoptGroupID(obj client.Object, controllerDomain string) (ID int64, ok bool, err error) {
+	value, ok := getAdoptID(obj, controllerDomain)
+	if !ok {
+		return
+	}
+
+	ID, err = strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		err = fmt.Errorf("annotation %s has to be a numeric group ID, got %q", adoptIDAnnotation(controllerDomain), value)
+	}
+
+	return
+}
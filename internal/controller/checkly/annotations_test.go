@@ -0,0 +1,64 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package checkly
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	checklyv1alpha1 "github.com/checkly/checkly-operator/api/checkly/v1alpha1"
+)
+
+func TestGetAdoptID(t *testing.T) {
+	controllerDomain := "testing.domain.tld"
+
+	group := &checklyv1alpha1.Group{}
+	if _, ok, err := getAdoptGroupID(group, controllerDomain); ok || err != nil {
+		t.Errorf("Expected no adopt ID, got %t, %v", ok, err)
+	}
+
+	group.ObjectMeta = metav1.ObjectMeta{
+		Annotations: map[string]string{
+			"testing.domain.tld/adopt-id": "1234",
+		},
+	}
+	ID, ok, err := getAdoptGroupID(group, controllerDomain)
+	if !ok || err != nil {
+		t.Errorf("Expected adopt ID, got %t, %v", ok, err)
+	}
+	if ID != 1234 {
+		t.Errorf("Expected %d, got %d", 1234, ID)
+	}
+
+	group.Annotations["testing.domain.tld/adopt-id"] = "foo"
+	if _, _, err := getAdoptGroupID(group, controllerDomain); err == nil {
+		t.Error("Expected error, got none")
+	}
+
+	apiCheck := &checklyv1alpha1.ApiCheck{
+		ObjectMeta: metav1.ObjectMeta{
+			Annotations: map[string]string{
+				"testing.domain.tld/adopt-id": "c0ffee",
+			},
+		},
+	}
+	checkID, ok := getAdoptID(apiCheck, controllerDomain)
+	if !ok || checkID != "c0ffee" {
+		t.Errorf("Expected %s, got %s", "c0ffee", checkID)
+	}
+}
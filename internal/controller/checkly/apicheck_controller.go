@@ -19,14 +19,21 @@ package checkly
 import (
 	"context"
 	"fmt"
+	"slices"
+	"strings"
+	"time"
 
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	"github.com/checkly/checkly-go-sdk"
 	checklyv1alpha1 "github.com/checkly/checkly-operator/api/checkly/v1alpha1"
@@ -37,14 +44,27 @@ import (
 type ApiCheckReconciler struct {
 	client.Client
 	Scheme           *runtime.Scheme
-	ApiClient        checkly.Client
+	ApiClient        external.Client
 	ControllerDomain string
+	Recorder         record.EventRecorder
+	// ResyncInterval is how often the resource is compared with checklyhq.com, zero disables the periodic resync
+	ResyncInterval time.Duration
+	// Accounts holds the clients of the ChecklyAccounts, ApiClient is used for resources which don't reference an account
+	Accounts *AccountClients
+	// DeletionPolicy is the default deletion policy of the resources which don't set one, empty deletes them
+	DeletionPolicy string
+	// ClusterID identifies the cluster in the ownership tags of the checks, empty disables the ownership tags
+	ClusterID string
 }
 
 //+kubebuilder:rbac:groups=k8s.checklyhq.com,resources=apichecks,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=k8s.checklyhq.com,resources=apichecks/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=k8s.checklyhq.com,resources=apichecks/finalizers,verbs=update
-//+kubebuilder:rbac:groups=k8s.checklyhq.com,resources=groups,verbs=get;list
+//+kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+//+kubebuilder:rbac:groups=k8s.checklyhq.com,resources=checklyaccounts,verbs=get;list;watch
+//+kubebuilder:rbac:groups=k8s.checklyhq.com,resources=groups,verbs=get;list;watch
+//+kubebuilder:rbac:groups=k8s.checklyhq.com,resources=checkgroups,verbs=get;list;watch
+//+kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -81,13 +101,43 @@ func (r *ApiCheckReconciler) Reconcile(ctx context.Context, req ctrl.Request) (c
 	if apiCheck.GetDeletionTimestamp() != nil {
 		if controllerutil.ContainsFinalizer(apiCheck, apiCheckFinalizer) {
 			logger.V(1).Info("Finalizer is present, trying to delete Checkly check", "checkly ID", apiCheck.Status.ID)
-			err := external.Delete(apiCheck.Status.ID, r.ApiClient)
+			released, err := releaseWithoutRemoteDeletion(ctx, r.Client, r.Recorder, apiCheck, apiCheckFinalizer, r.ControllerDomain, apiCheck.Status.ID != "")
+			if released || err != nil {
+				return ctrl.Result{}, err
+			}
+
+			apiClient, err := accountAPIClient(ctx, r.Accounts, r.ApiClient, apiCheck.Status.Account)
 			if err != nil {
-				logger.Error(err, "Failed to delete checkly API check")
+				logger.Error(err, "Unable to get the checkly client of the account", "account", apiCheck.Status.Account)
+				recordEvent(r.Recorder, apiCheck, corev1.EventTypeWarning, eventReasonDeleteFailed, fmt.Sprintf("Failed to delete checkly check %s: %s", apiCheck.Status.ID, err))
 				return ctrl.Result{}, err
 			}
+			switch effectiveDeletionPolicy(apiCheck.Spec.DeletionPolicy, r.DeletionPolicy) {
+			case checklyv1alpha1.DeletionPolicyRetain:
+				err = external.TagCheck(ctx, apiCheck.Status.ID, retainedTag(r.ControllerDomain, apiCheck), apiClient)
+				if err != nil {
+					logger.Error(err, "Failed to tag retained checkly API check")
+					recordEvent(r.Recorder, apiCheck, corev1.EventTypeWarning, eventReasonDeleteFailed, fmt.Sprintf("Failed to tag retained checkly check %s: %s", apiCheck.Status.ID, err))
+					return syncFailedResult(err)
+				}
+				logger.Info("Retained checkly API check", "checkly ID", apiCheck.Status.ID)
+				recordEvent(r.Recorder, apiCheck, corev1.EventTypeNormal, eventReasonRetained, fmt.Sprintf("Retained checkly check %s, it can be adopted again with the %s annotation", apiCheck.Status.ID, adoptIDAnnotation(r.ControllerDomain)))
+
+			case checklyv1alpha1.DeletionPolicyOrphan:
+				logger.Info("Orphaned checkly API check", "checkly ID", apiCheck.Status.ID)
+				recordEvent(r.Recorder, apiCheck, corev1.EventTypeNormal, eventReasonOrphaned, fmt.Sprintf("Left checkly check %s in checklyhq.com", apiCheck.Status.ID))
 
-			logger.Info("Successfully deleted checkly API check", "checkly ID", apiCheck.Status.ID)
+			default:
+				err = external.Delete(ctx, apiCheck.Status.ID, apiClient)
+				if err != nil {
+					logger.Error(err, "Failed to delete checkly API check")
+					recordEvent(r.Recorder, apiCheck, corev1.EventTypeWarning, eventReasonDeleteFailed, fmt.Sprintf("Failed to delete checkly check %s: %s", apiCheck.Status.ID, err))
+					return syncFailedResult(err)
+				}
+
+				logger.Info("Successfully deleted checkly API check", "checkly ID", apiCheck.Status.ID)
+				recordEvent(r.Recorder, apiCheck, corev1.EventTypeNormal, eventReasonDeleted, fmt.Sprintf("Deleted checkly check %s", apiCheck.Status.ID))
+			}
 
 			controllerutil.RemoveFinalizer(apiCheck, apiCheckFinalizer)
 			err = r.Update(ctx, apiCheck)
@@ -114,18 +164,27 @@ func (r *ApiCheckReconciler) Reconcile(ctx context.Context, req ctrl.Request) (c
 			return ctrl.Result{}, err
 		}
 		logger.V(1).Info("Added finalizer", "checkly ID", apiCheck.Status.ID, "endpoint", apiCheck.Spec.Endpoint)
-		return ctrl.Result{}, nil
+		return ctrl.Result{Requeue: true}, nil
 	}
 
 	// /////////////////////////////
 	// Lookup group ID
 	// ////////////////////////////
-	group := &checklyv1alpha1.Group{}
-	err = r.Get(ctx, types.NamespacedName{Name: apiCheck.Spec.Group}, group)
+	group, allowed, err := getGroup(ctx, r.Client, apiCheck.Namespace, apiCheck.Spec.Group, apiCheck.Spec.GroupKind)
 	if err != nil {
+		if _, ambiguous := err.(*ambiguousGroupError); ambiguous {
+			logger.Error(err, "Group reference is ambiguous", "name", apiCheck.Spec.Group)
+			setDependenciesNotResolved(&apiCheck.Status.SyncStatus, apiCheck.Generation, checklyv1alpha1.ReasonDependencyAmbiguous, err.Error())
+			recordEvent(r.Recorder, apiCheck, corev1.EventTypeWarning, eventReasonWaitingForDependency, err.Error())
+			updateStatus(ctx, r.Client, apiCheck)
+			return ctrl.Result{}, err
+		}
 		if errors.IsNotFound(err) {
 			// The resource has been deleted
 			logger.Error(err, "Group not found, probably deleted or does not exist", "name", apiCheck.Spec.Group)
+			setDependenciesNotResolved(&apiCheck.Status.SyncStatus, apiCheck.Generation, checklyv1alpha1.ReasonDependencyNotFound, fmt.Sprintf("Group %s not found", apiCheck.Spec.Group))
+			recordEvent(r.Recorder, apiCheck, corev1.EventTypeWarning, eventReasonWaitingForDependency, fmt.Sprintf("Group %s not found", apiCheck.Spec.Group))
+			updateStatus(ctx, r.Client, apiCheck)
 			return ctrl.Result{}, err
 		}
 		// Error reading the object
@@ -133,23 +192,153 @@ func (r *ApiCheckReconciler) Reconcile(ctx context.Context, req ctrl.Request) (c
 		return ctrl.Result{}, err
 	}
 
+	if !allowed {
+		err = fmt.Errorf("group %s does not allow checks from namespace %s", apiCheck.Spec.Group, apiCheck.Namespace)
+		logger.Error(err, "Group not allowed")
+		setDependenciesNotResolved(&apiCheck.Status.SyncStatus, apiCheck.Generation, checklyv1alpha1.ReasonDependencyNotAllowed, err.Error())
+		recordEvent(r.Recorder, apiCheck, corev1.EventTypeWarning, eventReasonDependencyNotAllowed, err.Error())
+		updateStatus(ctx, r.Client, apiCheck)
+		return ctrl.Result{}, err
+	}
+
 	if group.Status.ID == 0 {
-		logger.V(1).Info("Group ID has not been populated, we're too quick, requeining for retry", "group name", apiCheck.Spec.Group)
-		return ctrl.Result{Requeue: true}, nil
+		logger.V(1).Info("Group ID has not been populated, waiting for the group to be synced", "group name", apiCheck.Spec.Group)
+		setDependenciesNotResolved(&apiCheck.Status.SyncStatus, apiCheck.Generation, checklyv1alpha1.ReasonDependencyNotReady, fmt.Sprintf("Group %s is not synced yet", apiCheck.Spec.Group))
+		recordEvent(r.Recorder, apiCheck, corev1.EventTypeWarning, eventReasonWaitingForDependency, fmt.Sprintf("Group %s is not synced yet", apiCheck.Spec.Group))
+		updateStatus(ctx, r.Client, apiCheck)
+		// The group is watched, the ApiCheck is reconciled again once the group ID is set
+		return ctrl.Result{}, nil
+	}
+
+	if apiCheck.Status.GroupID != 0 && apiCheck.Status.GroupID != group.Status.ID {
+		logger.Info("Group ID changed, moving the checkly check", "old group ID", apiCheck.Status.GroupID, "group ID", group.Status.ID)
+	}
+
+	// /////////////////////////////
+	// Account logic
+	// ////////////////////////////
+	account, err := checkAccount(group, apiCheck.Spec.Account, apiCheck.Status.ID != "", apiCheck.Status.Account)
+	if err != nil {
+		logger.Error(err, "Account not allowed")
+		setDependenciesNotResolved(&apiCheck.Status.SyncStatus, apiCheck.Generation, checklyv1alpha1.ReasonDependencyNotAllowed, err.Error())
+		recordEvent(r.Recorder, apiCheck, corev1.EventTypeWarning, eventReasonDependencyNotAllowed, err.Error())
+		updateStatus(ctx, r.Client, apiCheck)
+		return ctrl.Result{}, err
+	}
+
+	apiClient, err := accountAPIClient(ctx, r.Accounts, r.ApiClient, account)
+	if err != nil {
+		logger.Error(err, "Unable to get the checkly client of the account", "account", account)
+		setDependenciesNotResolved(&apiCheck.Status.SyncStatus, apiCheck.Generation, checklyv1alpha1.ReasonDependencyNotFound, err.Error())
+		recordEvent(r.Recorder, apiCheck, corev1.EventTypeWarning, eventReasonWaitingForDependency, err.Error())
+		updateStatus(ctx, r.Client, apiCheck)
+		return ctrl.Result{}, err
 	}
 
+	// /////////////////////////////
+	// Request headers and query parameters
+	// ////////////////////////////
+	headers, err := r.resolveKeyValues(ctx, apiCheck.Namespace, apiCheck.Spec.Headers)
+	if err != nil {
+		logger.Error(err, "Unable to resolve request headers")
+		setDependenciesNotResolved(&apiCheck.Status.SyncStatus, apiCheck.Generation, checklyv1alpha1.ReasonDependencyNotFound, err.Error())
+		recordEvent(r.Recorder, apiCheck, corev1.EventTypeWarning, eventReasonWaitingForDependency, err.Error())
+		updateStatus(ctx, r.Client, apiCheck)
+		return ctrl.Result{}, err
+	}
+
+	queryParameters, err := r.resolveKeyValues(ctx, apiCheck.Namespace, apiCheck.Spec.QueryParameters)
+	if err != nil {
+		logger.Error(err, "Unable to resolve request query parameters")
+		setDependenciesNotResolved(&apiCheck.Status.SyncStatus, apiCheck.Generation, checklyv1alpha1.ReasonDependencyNotFound, err.Error())
+		recordEvent(r.Recorder, apiCheck, corev1.EventTypeWarning, eventReasonWaitingForDependency, err.Error())
+		updateStatus(ctx, r.Client, apiCheck)
+		return ctrl.Result{}, err
+	}
+
+	secretsHash, err := apiCheckSecretsHash(headers, queryParameters)
+	if err != nil {
+		logger.Error(err, "Unable to hash the request headers and query parameters")
+		return ctrl.Result{}, err
+	}
+
+	var assertions []checkly.Assertion
+	for _, assertion := range apiCheck.Spec.Assertions {
+		assertions = append(assertions, checkly.Assertion{
+			Source:     assertion.Source,
+			Property:   assertion.Property,
+			Comparison: assertion.Comparison,
+			Target:     assertion.Target,
+		})
+	}
+
+	alertSettings, useGlobalAlertSettings := checklyAlertSettings(apiCheck.Spec.AlertSettings)
+
 	// Create internal Check type
 	internalCheck := external.Check{
-		Name:            apiCheck.Name,
-		Namespace:       apiCheck.Namespace,
-		Frequency:       apiCheck.Spec.Frequency,
-		MaxResponseTime: apiCheck.Spec.MaxResponseTime,
-		Endpoint:        apiCheck.Spec.Endpoint,
-		SuccessCode:     apiCheck.Spec.Success,
-		ID:              apiCheck.Status.ID,
-		GroupID:         group.Status.ID,
-		Muted:           apiCheck.Spec.Muted,
-		Labels:          apiCheck.Labels,
+		Name:                   apiCheck.Name,
+		Namespace:              apiCheck.Namespace,
+		Frequency:              apiCheck.Spec.Frequency,
+		MaxResponseTime:        apiCheck.Spec.MaxResponseTime,
+		Locations:              apiCheck.Spec.Locations,
+		Endpoint:               apiCheck.Spec.Endpoint,
+		SuccessCode:            apiCheck.Spec.Success,
+		ID:                     apiCheck.Status.ID,
+		GroupID:                group.Status.ID,
+		Muted:                  apiCheck.Spec.Muted,
+		Labels:                 apiCheck.Labels,
+		Method:                 apiCheck.Spec.Method,
+		Headers:                headers,
+		QueryParameters:        queryParameters,
+		Body:                   apiCheck.Spec.Body,
+		BodyType:               apiCheck.Spec.BodyType,
+		Assertions:             assertions,
+		AlertSettings:          alertSettings,
+		UseGlobalAlertSettings: useGlobalAlertSettings,
+		OwnerTag:               ownerTag(r.ControllerDomain, r.ClusterID, apiCheck),
+	}
+
+	// /////////////////////////////
+	// Adoption logic
+	// ////////////////////////////
+	if apiCheck.Status.ID == "" {
+		if adoptID, ok := getAdoptID(apiCheck, r.ControllerDomain); ok {
+			found, err := external.CheckExists(ctx, adoptID, apiClient)
+			if err == nil && !found {
+				err = fmt.Errorf("check %s not found in checklyhq.com", adoptID)
+			}
+			if err != nil {
+				logger.Error(err, "Failed to adopt existing checkly check", "checkly ID", adoptID)
+				setSyncFailed(&apiCheck.Status.SyncStatus, apiCheck.Generation, err)
+				recordEvent(r.Recorder, apiCheck, corev1.EventTypeWarning, eventReasonSyncFailed, fmt.Sprintf("Failed to sync with checklyhq.com: %s", err))
+				updateStatus(ctx, r.Client, apiCheck)
+				return syncFailedResult(err)
+			}
+			logger.Info("Adopting existing checkly check", "checkly ID", adoptID)
+			recordEvent(r.Recorder, apiCheck, corev1.EventTypeNormal, eventReasonAdopted, fmt.Sprintf("Adopted existing checkly check %s", adoptID))
+			apiCheck.Status.ID = adoptID
+			internalCheck.ID = adoptID
+		}
+	}
+
+	// /////////////////////////////
+	// Ownership logic
+	// ////////////////////////////
+	if apiCheck.Status.ID == "" && internalCheck.OwnerTag != "" {
+		ownedID, err := external.FindCheck(ctx, internalCheck.OwnerTag, apiClient)
+		if err != nil {
+			logger.Error(err, "Failed to look up the checkly check by its ownership tag")
+			setSyncFailed(&apiCheck.Status.SyncStatus, apiCheck.Generation, err)
+			recordEvent(r.Recorder, apiCheck, corev1.EventTypeWarning, eventReasonSyncFailed, fmt.Sprintf("Failed to sync with checklyhq.com: %s", err))
+			updateStatus(ctx, r.Client, apiCheck)
+			return syncFailedResult(err)
+		}
+		if ownedID != "" {
+			logger.Info("Relinking checkly check found by its ownership tag", "checkly ID", ownedID)
+			recordEvent(r.Recorder, apiCheck, corev1.EventTypeNormal, eventReasonRelinked, fmt.Sprintf("Relinked existing checkly check %s found by its ownership tag", ownedID))
+			apiCheck.Status.ID = ownedID
+			internalCheck.ID = ownedID
+		}
 	}
 
 	// /////////////////////////////
@@ -160,43 +349,208 @@ func (r *ApiCheckReconciler) Reconcile(ctx context.Context, req ctrl.Request) (c
 	if apiCheck.Status.ID != "" {
 		// Existing object, we need to update it
 		logger.V(1).Info("Existing object, with ID", "checkly ID", apiCheck.Status.ID, "endpoint", apiCheck.Spec.Endpoint)
-		err := external.Update(internalCheck, r.ApiClient)
-		// err :=
+		drift, found, err := external.CheckDrift(ctx, internalCheck, apiClient)
 		if err != nil {
-			logger.Error(err, "Failed to update the checkly check")
-			return ctrl.Result{}, err
+			logger.Error(err, "Failed to get the checkly check")
+			setSyncFailed(&apiCheck.Status.SyncStatus, apiCheck.Generation, err)
+			recordEvent(r.Recorder, apiCheck, corev1.EventTypeWarning, eventReasonSyncFailed, fmt.Sprintf("Failed to sync with checklyhq.com: %s", err))
+			updateStatus(ctx, r.Client, apiCheck)
+			return syncFailedResult(err)
 		}
-		logger.Info("Updated checkly check", "checkly ID", apiCheck.Status.ID)
-		return ctrl.Result{}, nil
+
+		if found {
+			if len(drift) != 0 {
+				logger.Info("Checkly check changed outside of the operator, overwriting", "checkly ID", apiCheck.Status.ID, "fields", drift)
+				recordEvent(r.Recorder, apiCheck, corev1.EventTypeWarning, eventReasonDriftCorrected, fmt.Sprintf("Checkly check %s was changed outside of the operator, reverting: %s", apiCheck.Status.ID, strings.Join(drift, ", ")))
+			}
+			// A resync of an unchanged check only compares it, updating it anyway would spend the rate limit of the account
+			if len(drift) != 0 || !isSynced(&apiCheck.Status.SyncStatus, apiCheck.Generation) || apiCheck.Status.SecretsHash != secretsHash {
+				err = external.Update(ctx, internalCheck, apiClient)
+				if err != nil {
+					logger.Error(err, "Failed to update the checkly check")
+					setSyncFailed(&apiCheck.Status.SyncStatus, apiCheck.Generation, err)
+					recordEvent(r.Recorder, apiCheck, corev1.EventTypeWarning, eventReasonSyncFailed, fmt.Sprintf("Failed to sync with checklyhq.com: %s", err))
+					updateStatus(ctx, r.Client, apiCheck)
+					return syncFailedResult(err)
+				}
+				logger.Info("Updated checkly check", "checkly ID", apiCheck.Status.ID)
+				if !isSynced(&apiCheck.Status.SyncStatus, apiCheck.Generation) {
+					recordEvent(r.Recorder, apiCheck, corev1.EventTypeNormal, eventReasonUpdated, fmt.Sprintf("Updated checkly check %s", apiCheck.Status.ID))
+				}
+			}
+
+			apiCheck.Status.GroupID = group.Status.ID
+			apiCheck.Status.SecretsHash = secretsHash
+			apiCheck.Status.Account = account
+			setDrift(&apiCheck.Status.SyncStatus, apiCheck.Generation, drift)
+			setSynced(&apiCheck.Status.SyncStatus, apiCheck.Generation)
+			err = r.Status().Update(ctx, apiCheck)
+			if err != nil {
+				logger.Error(err, "Failed to update ApiCheck status")
+				return ctrl.Result{}, err
+			}
+			return ctrl.Result{RequeueAfter: r.ResyncInterval}, nil
+		}
+
+		// The check was deleted outside of the operator, we create it again
+		logger.Info("Checkly check not found, recreating", "checkly ID", apiCheck.Status.ID)
+		recordEvent(r.Recorder, apiCheck, corev1.EventTypeWarning, eventReasonRecreated, fmt.Sprintf("Checkly check %s was deleted outside of the operator, recreating", apiCheck.Status.ID))
+		setRecreated(&apiCheck.Status.SyncStatus, apiCheck.Generation)
 	}
 
 	// /////////////////////////////
 	// Create logic
 	// ////////////////////////////
 
-	checklyID, err := external.Create(internalCheck, r.ApiClient)
+	checklyID, err := external.Create(ctx, internalCheck, apiClient)
 	if err != nil {
 		logger.Error(err, "Failed to create checkly alert")
-		return ctrl.Result{}, err
+		setSyncFailed(&apiCheck.Status.SyncStatus, apiCheck.Generation, err)
+		recordEvent(r.Recorder, apiCheck, corev1.EventTypeWarning, eventReasonSyncFailed, fmt.Sprintf("Failed to sync with checklyhq.com: %s", err))
+		updateStatus(ctx, r.Client, apiCheck)
+		return syncFailedResult(err)
 	}
 
 	// Update the custom resource Status with the returned ID
 
 	apiCheck.Status.ID = checklyID
 	apiCheck.Status.GroupID = group.Status.ID
+	apiCheck.Status.SecretsHash = secretsHash
+	apiCheck.Status.Account = account
+	setSynced(&apiCheck.Status.SyncStatus, apiCheck.Generation)
 	err = r.Status().Update(ctx, apiCheck)
 	if err != nil {
 		logger.Error(err, "Failed to update ApiCheck status")
 		return ctrl.Result{}, err
 	}
 	logger.V(1).Info("New checkly check created with", "checkly ID", apiCheck.Status.ID, "spec", apiCheck.Spec)
+	recordEvent(r.Recorder, apiCheck, corev1.EventTypeNormal, eventReasonCreated, fmt.Sprintf("Created checkly check %s", apiCheck.Status.ID))
 
-	return ctrl.Result{}, nil
+	return ctrl.Result{RequeueAfter: r.ResyncInterval}, nil
+}
+
+// resolveKeyValues turns the ApiCheck key value pairs into checkly key values, reading secret references
+// from the namespace of the ApiCheck. Values read from secrets are locked so they're hidden in the checklyhq.com UI.
+func (r *ApiCheckReconciler) resolveKeyValues(ctx context.Context, namespace string, keyValues []checklyv1alpha1.ApiCheckKeyValue) (resolved []checkly.KeyValue, err error) {
+	for _, keyValue := range keyValues {
+		if keyValue.ValueFrom == nil {
+			resolved = append(resolved, checkly.KeyValue{
+				Key:   keyValue.Key,
+				Value: keyValue.Value,
+			})
+			continue
+		}
+
+		secret := &corev1.Secret{}
+		err = r.Get(ctx, types.NamespacedName{Name: keyValue.ValueFrom.Name, Namespace: namespace}, secret)
+		if err != nil {
+			return
+		}
+
+		secretValue, exists := secret.Data[keyValue.ValueFrom.Key]
+		if !exists {
+			err = fmt.Errorf("key %s not found in secret %s", keyValue.ValueFrom.Key, keyValue.ValueFrom.Name)
+			return
+		}
+
+		resolved = append(resolved, checkly.KeyValue{
+			Key:    keyValue.Key,
+			Value:  string(secretValue),
+			Locked: true,
+		})
+	}
+
+	return
+}
+
+// apiCheckSecretsHash hashes the header and query parameter values read from secrets, so a rotated secret can be told apart
+// from a resync, the hash is empty if no value is read from a secret
+func apiCheckSecretsHash(headers []checkly.KeyValue, queryParameters []checkly.KeyValue) (hash string, err error) {
+	var locked []checkly.KeyValue
+	for _, keyValue := range slices.Concat(headers, queryParameters) {
+		if keyValue.Locked {
+			locked = append(locked, keyValue)
+		}
+	}
+	if len(locked) == 0 {
+		return
+	}
+
+	return hashValues(locked)
+}
+
+// findApiChecksForGroup maps a Group or CheckGroup to the ApiChecks referencing it, a cluster scoped Group can be
+// referenced from every namespace
+func (r *ApiCheckReconciler) findApiChecksForGroup(ctx context.Context, group client.Object) []reconcile.Request {
+	apiChecks := &checklyv1alpha1.ApiCheckList{}
+	err := r.List(ctx, apiChecks,
+		client.InNamespace(group.GetNamespace()),
+		client.MatchingFields{checkGroupIndex: group.GetName()})
+	if err != nil {
+		return []reconcile.Request{}
+	}
+
+	requests := make([]reconcile.Request, len(apiChecks.Items))
+	for i, item := range apiChecks.Items {
+		requests[i] = reconcile.Request{
+			NamespacedName: types.NamespacedName{
+				Name:      item.Name,
+				Namespace: item.Namespace,
+			},
+		}
+	}
+	return requests
+}
+
+// findApiChecksForSecret maps a Secret to the ApiChecks in its namespace which read header or query parameter values from it,
+// so a rotated secret reaches checklyhq.com without waiting for the resync
+func (r *ApiCheckReconciler) findApiChecksForSecret(ctx context.Context, secret client.Object) []reconcile.Request {
+	apiChecks := &checklyv1alpha1.ApiCheckList{}
+	err := r.List(ctx, apiChecks,
+		client.InNamespace(secret.GetNamespace()),
+		client.MatchingFields{apiCheckSecretIndex: secret.GetName()})
+	if err != nil {
+		return []reconcile.Request{}
+	}
+
+	requests := make([]reconcile.Request, len(apiChecks.Items))
+	for i, item := range apiChecks.Items {
+		requests[i] = reconcile.Request{
+			NamespacedName: types.NamespacedName{
+				Name:      item.Name,
+				Namespace: item.Namespace,
+			},
+		}
+	}
+	return requests
 }
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *ApiCheckReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	err := mgr.GetFieldIndexer().IndexField(context.Background(), &checklyv1alpha1.ApiCheck{}, checkGroupIndex, apiCheckGroupIndexValues)
+	if err != nil {
+		return err
+	}
+	err = mgr.GetFieldIndexer().IndexField(context.Background(), &checklyv1alpha1.ApiCheck{}, apiCheckSecretIndex, apiCheckSecretIndexValues)
+	if err != nil {
+		return err
+	}
+
 	return ctrl.NewControllerManagedBy(mgr).
-		For(&checklyv1alpha1.ApiCheck{}).
+		For(&checklyv1alpha1.ApiCheck{}, specChanged).
+		Watches(
+			&checklyv1alpha1.Group{},
+			handler.EnqueueRequestsFromMapFunc(r.findApiChecksForGroup),
+			dependencyChanged,
+		).
+		Watches(
+			&checklyv1alpha1.CheckGroup{},
+			handler.EnqueueRequestsFromMapFunc(r.findApiChecksForGroup),
+			dependencyChanged,
+		).
+		Watches(
+			&corev1.Secret{},
+			handler.EnqueueRequestsFromMapFunc(r.findApiChecksForSecret),
+		).
 		Complete(r)
 }
@@ -26,6 +26,7 @@ import (
 	. "github.com/onsi/gomega"
 
 	checklyv1alpha1 "github.com/checkly/checkly-operator/api/checkly/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 )
@@ -112,6 +113,17 @@ var _ = Describe("ApiCheck Controller", func() {
 				return true
 			}, timeout, interval).Should(BeTrue())
 
+			// Ready condition should be set
+			By("Expecting Ready condition")
+			Eventually(func() bool {
+				f := &checklyv1alpha1.ApiCheck{}
+				err := k8sClient.Get(context.Background(), key, f)
+				if err != nil {
+					return false
+				}
+				return meta.IsStatusConditionTrue(f.Status.Conditions, checklyv1alpha1.ConditionReady) && f.Status.ObservedGeneration == f.Generation
+			}, timeout, interval).Should(BeTrue())
+
 			// Finalizer should be present
 			By("Expecting finalizer")
 			Eventually(func() bool {
@@ -0,0 +1,497 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package checkly
+
+import (
+	"context"
+	errs "errors"
+	"fmt"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	checklyv1alpha1 "github.com/checkly/checkly-operator/api/checkly/v1alpha1"
+	external "github.com/checkly/checkly-operator/external/checkly"
+)
+
+// browserCheckConfigMapIndex is the field index used to find BrowserChecks referencing a ConfigMap
+const browserCheckConfigMapIndex = ".spec.scriptConfigMap.name"
+
+// BrowserCheckReconciler reconciles a BrowserCheck object
+type BrowserCheckReconciler struct {
+	client.Client
+	Scheme           *runtime.Scheme
+	ApiClient        external.Client
+	ControllerDomain string
+	Recorder         record.EventRecorder
+	// ResyncInterval is how often the resource is compared with checklyhq.com, zero disables the periodic resync
+	ResyncInterval time.Duration
+	// Accounts holds the clients of the ChecklyAccounts, ApiClient is used for resources which don't reference an account
+	Accounts *AccountClients
+	// DeletionPolicy is the default deletion policy of the resources which don't set one, empty deletes them
+	DeletionPolicy string
+	// ClusterID identifies the cluster in the ownership tags of the checks, empty disables the ownership tags
+	ClusterID string
+}
+
+//+kubebuilder:rbac:groups=k8s.checklyhq.com,resources=browserchecks,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=k8s.checklyhq.com,resources=browserchecks/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=k8s.checklyhq.com,resources=browserchecks/finalizers,verbs=update
+//+kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+//+kubebuilder:rbac:groups=k8s.checklyhq.com,resources=checklyaccounts,verbs=get;list;watch
+//+kubebuilder:rbac:groups=k8s.checklyhq.com,resources=groups,verbs=get;list;watch
+//+kubebuilder:rbac:groups=k8s.checklyhq.com,resources=checkgroups,verbs=get;list;watch
+//+kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch
+
+// Reconcile is part of the main kubernetes reconciliation loop which aims to
+// move the current state of the cluster closer to the desired state.
+//
+// For more details, check Reconcile and its Result here:
+// - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.19.0/pkg/reconcile
+func (r *BrowserCheckReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	browserCheckFinalizer := fmt.Sprintf("%s/finalizer", r.ControllerDomain)
+	logger.V(1).Info("Reconciler started")
+
+	browserCheck := &checklyv1alpha1.BrowserCheck{}
+
+	// ////////////////////////////////
+	// Delete Logic
+	// ///////////////////////////////
+	err := r.Get(ctx, req.NamespacedName, browserCheck)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			// The resource has been deleted
+			logger.V(1).Info("Deleted", "checkly ID", browserCheck.Status.ID, "name", browserCheck.Name)
+			return ctrl.Result{}, nil
+		}
+		// Error reading the object
+		logger.Error(err, "can't read the object")
+		return ctrl.Result{}, nil
+	}
+
+	if browserCheck.GetDeletionTimestamp() != nil {
+		if controllerutil.ContainsFinalizer(browserCheck, browserCheckFinalizer) {
+			logger.V(1).Info("Finalizer is present, trying to delete Checkly browser check", "checkly ID", browserCheck.Status.ID)
+			released, err := releaseWithoutRemoteDeletion(ctx, r.Client, r.Recorder, browserCheck, browserCheckFinalizer, r.ControllerDomain, browserCheck.Status.ID != "")
+			if released || err != nil {
+				return ctrl.Result{}, err
+			}
+
+			apiClient, err := accountAPIClient(ctx, r.Accounts, r.ApiClient, browserCheck.Status.Account)
+			if err != nil {
+				logger.Error(err, "Unable to get the checkly client of the account", "account", browserCheck.Status.Account)
+				recordEvent(r.Recorder, browserCheck, corev1.EventTypeWarning, eventReasonDeleteFailed, fmt.Sprintf("Failed to delete checkly browser check %s: %s", browserCheck.Status.ID, err))
+				return ctrl.Result{}, err
+			}
+			switch effectiveDeletionPolicy(browserCheck.Spec.DeletionPolicy, r.DeletionPolicy) {
+			case checklyv1alpha1.DeletionPolicyRetain:
+				err = external.TagCheck(ctx, browserCheck.Status.ID, retainedTag(r.ControllerDomain, browserCheck), apiClient)
+				if err != nil {
+					logger.Error(err, "Failed to tag retained checkly browser check")
+					recordEvent(r.Recorder, browserCheck, corev1.EventTypeWarning, eventReasonDeleteFailed, fmt.Sprintf("Failed to tag retained checkly browser check %s: %s", browserCheck.Status.ID, err))
+					return syncFailedResult(err)
+				}
+				logger.Info("Retained checkly browser check", "checkly ID", browserCheck.Status.ID)
+				recordEvent(r.Recorder, browserCheck, corev1.EventTypeNormal, eventReasonRetained, fmt.Sprintf("Retained checkly browser check %s, it can be adopted again with the %s annotation", browserCheck.Status.ID, adoptIDAnnotation(r.ControllerDomain)))
+
+			case checklyv1alpha1.DeletionPolicyOrphan:
+				logger.Info("Orphaned checkly browser check", "checkly ID", browserCheck.Status.ID)
+				recordEvent(r.Recorder, browserCheck, corev1.EventTypeNormal, eventReasonOrphaned, fmt.Sprintf("Left checkly browser check %s in checklyhq.com", browserCheck.Status.ID))
+
+			default:
+				err = external.Delete(ctx, browserCheck.Status.ID, apiClient)
+				if err != nil {
+					logger.Error(err, "Failed to delete checkly browser check")
+					recordEvent(r.Recorder, browserCheck, corev1.EventTypeWarning, eventReasonDeleteFailed, fmt.Sprintf("Failed to delete checkly browser check %s: %s", browserCheck.Status.ID, err))
+					return syncFailedResult(err)
+				}
+
+				logger.Info("Successfully deleted checkly browser check", "checkly ID", browserCheck.Status.ID)
+				recordEvent(r.Recorder, browserCheck, corev1.EventTypeNormal, eventReasonDeleted, fmt.Sprintf("Deleted checkly browser check %s", browserCheck.Status.ID))
+			}
+
+			controllerutil.RemoveFinalizer(browserCheck, browserCheckFinalizer)
+			err = r.Update(ctx, browserCheck)
+			if err != nil {
+				logger.Error(err, "Failed to delete finalizer")
+				return ctrl.Result{}, err
+			}
+			logger.V(1).Info("Successfully deleted finalizer")
+		}
+		return ctrl.Result{}, nil
+	}
+
+	// Object found, let's do something with it. It's either updated, or it's new.
+	logger.V(1).Info("Object found", "name", browserCheck.Name)
+
+	// /////////////////////////////
+	// Finalizer logic
+	// ////////////////////////////
+	if !controllerutil.ContainsFinalizer(browserCheck, browserCheckFinalizer) {
+		controllerutil.AddFinalizer(browserCheck, browserCheckFinalizer)
+		err = r.Update(ctx, browserCheck)
+		if err != nil {
+			logger.Error(err, "Failed to update BrowserCheck finalizer")
+			return ctrl.Result{}, err
+		}
+		logger.V(1).Info("Added finalizer", "checkly ID", browserCheck.Status.ID)
+		return ctrl.Result{Requeue: true}, nil
+	}
+
+	// /////////////////////////////
+	// Lookup script
+	// ////////////////////////////
+	script, err := r.getScript(ctx, browserCheck)
+	if err != nil {
+		logger.Error(err, "Unable to read the browser check script")
+		setDependenciesNotResolved(&browserCheck.Status.SyncStatus, browserCheck.Generation, checklyv1alpha1.ReasonDependencyNotFound, err.Error())
+		recordEvent(r.Recorder, browserCheck, corev1.EventTypeWarning, eventReasonWaitingForDependency, err.Error())
+		updateStatus(ctx, r.Client, browserCheck)
+		return ctrl.Result{}, err
+	}
+
+	// /////////////////////////////
+	// Lookup group ID
+	// ////////////////////////////
+	group, allowed, err := getGroup(ctx, r.Client, browserCheck.Namespace, browserCheck.Spec.Group, browserCheck.Spec.GroupKind)
+	if err != nil {
+		if _, ambiguous := err.(*ambiguousGroupError); ambiguous {
+			logger.Error(err, "Group reference is ambiguous", "name", browserCheck.Spec.Group)
+			setDependenciesNotResolved(&browserCheck.Status.SyncStatus, browserCheck.Generation, checklyv1alpha1.ReasonDependencyAmbiguous, err.Error())
+			recordEvent(r.Recorder, browserCheck, corev1.EventTypeWarning, eventReasonWaitingForDependency, err.Error())
+			updateStatus(ctx, r.Client, browserCheck)
+			return ctrl.Result{}, err
+		}
+		if errors.IsNotFound(err) {
+			// The resource has been deleted
+			logger.Error(err, "Group not found, probably deleted or does not exist", "name", browserCheck.Spec.Group)
+			setDependenciesNotResolved(&browserCheck.Status.SyncStatus, browserCheck.Generation, checklyv1alpha1.ReasonDependencyNotFound, fmt.Sprintf("Group %s not found", browserCheck.Spec.Group))
+			recordEvent(r.Recorder, browserCheck, corev1.EventTypeWarning, eventReasonWaitingForDependency, fmt.Sprintf("Group %s not found", browserCheck.Spec.Group))
+			updateStatus(ctx, r.Client, browserCheck)
+			return ctrl.Result{}, err
+		}
+		// Error reading the object
+		logger.Error(err, "can't read the group object")
+		return ctrl.Result{}, err
+	}
+
+	if !allowed {
+		err = fmt.Errorf("group %s does not allow checks from namespace %s", browserCheck.Spec.Group, browserCheck.Namespace)
+		logger.Error(err, "Group not allowed")
+		setDependenciesNotResolved(&browserCheck.Status.SyncStatus, browserCheck.Generation, checklyv1alpha1.ReasonDependencyNotAllowed, err.Error())
+		recordEvent(r.Recorder, browserCheck, corev1.EventTypeWarning, eventReasonDependencyNotAllowed, err.Error())
+		updateStatus(ctx, r.Client, browserCheck)
+		return ctrl.Result{}, err
+	}
+
+	if group.Status.ID == 0 {
+		logger.V(1).Info("Group ID has not been populated, waiting for the group to be synced", "group name", browserCheck.Spec.Group)
+		setDependenciesNotResolved(&browserCheck.Status.SyncStatus, browserCheck.Generation, checklyv1alpha1.ReasonDependencyNotReady, fmt.Sprintf("Group %s is not synced yet", browserCheck.Spec.Group))
+		recordEvent(r.Recorder, browserCheck, corev1.EventTypeWarning, eventReasonWaitingForDependency, fmt.Sprintf("Group %s is not synced yet", browserCheck.Spec.Group))
+		updateStatus(ctx, r.Client, browserCheck)
+		// The group is watched, the BrowserCheck is reconciled again once the group ID is set
+		return ctrl.Result{}, nil
+	}
+
+	if browserCheck.Status.GroupID != 0 && browserCheck.Status.GroupID != group.Status.ID {
+		logger.Info("Group ID changed, moving the checkly browser check", "old group ID", browserCheck.Status.GroupID, "group ID", group.Status.ID)
+	}
+
+	// /////////////////////////////
+	// Account logic
+	// ////////////////////////////
+	account, err := checkAccount(group, browserCheck.Spec.Account, browserCheck.Status.ID != "", browserCheck.Status.Account)
+	if err != nil {
+		logger.Error(err, "Account not allowed")
+		setDependenciesNotResolved(&browserCheck.Status.SyncStatus, browserCheck.Generation, checklyv1alpha1.ReasonDependencyNotAllowed, err.Error())
+		recordEvent(r.Recorder, browserCheck, corev1.EventTypeWarning, eventReasonDependencyNotAllowed, err.Error())
+		updateStatus(ctx, r.Client, browserCheck)
+		return ctrl.Result{}, err
+	}
+
+	apiClient, err := accountAPIClient(ctx, r.Accounts, r.ApiClient, account)
+	if err != nil {
+		logger.Error(err, "Unable to get the checkly client of the account", "account", account)
+		setDependenciesNotResolved(&browserCheck.Status.SyncStatus, browserCheck.Generation, checklyv1alpha1.ReasonDependencyNotFound, err.Error())
+		recordEvent(r.Recorder, browserCheck, corev1.EventTypeWarning, eventReasonWaitingForDependency, err.Error())
+		updateStatus(ctx, r.Client, browserCheck)
+		return ctrl.Result{}, err
+	}
+
+	// Create internal BrowserCheck type
+	internalCheck := external.BrowserCheck{
+		Name:      browserCheck.Name,
+		Namespace: browserCheck.Namespace,
+		Frequency: browserCheck.Spec.Frequency,
+		Script:    script,
+		ID:        browserCheck.Status.ID,
+		GroupID:   group.Status.ID,
+		Muted:     browserCheck.Spec.Muted,
+		Labels:    browserCheck.Labels,
+		OwnerTag:  ownerTag(r.ControllerDomain, r.ClusterID, browserCheck),
+	}
+
+	// /////////////////////////////
+	// Adoption logic
+	// ////////////////////////////
+	if browserCheck.Status.ID == "" {
+		if adoptID, ok := getAdoptID(browserCheck, r.ControllerDomain); ok {
+			found, err := external.CheckExists(ctx, adoptID, apiClient)
+			if err == nil && !found {
+				err = fmt.Errorf("check %s not found in checklyhq.com", adoptID)
+			}
+			if err != nil {
+				logger.Error(err, "Failed to adopt existing checkly browser check", "checkly ID", adoptID)
+				setSyncFailed(&browserCheck.Status.SyncStatus, browserCheck.Generation, err)
+				recordEvent(r.Recorder, browserCheck, corev1.EventTypeWarning, eventReasonSyncFailed, fmt.Sprintf("Failed to sync with checklyhq.com: %s", err))
+				updateStatus(ctx, r.Client, browserCheck)
+				return syncFailedResult(err)
+			}
+			logger.Info("Adopting existing checkly browser check", "checkly ID", adoptID)
+			recordEvent(r.Recorder, browserCheck, corev1.EventTypeNormal, eventReasonAdopted, fmt.Sprintf("Adopted existing checkly browser check %s", adoptID))
+			browserCheck.Status.ID = adoptID
+			internalCheck.ID = adoptID
+		}
+	}
+
+	// /////////////////////////////
+	// Ownership logic
+	// ////////////////////////////
+	if browserCheck.Status.ID == "" && internalCheck.OwnerTag != "" {
+		ownedID, err := external.FindCheck(ctx, internalCheck.OwnerTag, apiClient)
+		if err != nil {
+			logger.Error(err, "Failed to look up the checkly browser check by its ownership tag")
+			setSyncFailed(&browserCheck.Status.SyncStatus, browserCheck.Generation, err)
+			recordEvent(r.Recorder, browserCheck, corev1.EventTypeWarning, eventReasonSyncFailed, fmt.Sprintf("Failed to sync with checklyhq.com: %s", err))
+			updateStatus(ctx, r.Client, browserCheck)
+			return syncFailedResult(err)
+		}
+		if ownedID != "" {
+			logger.Info("Relinking checkly browser check found by its ownership tag", "checkly ID", ownedID)
+			recordEvent(r.Recorder, browserCheck, corev1.EventTypeNormal, eventReasonRelinked, fmt.Sprintf("Relinked existing checkly browser check %s found by its ownership tag", ownedID))
+			browserCheck.Status.ID = ownedID
+			internalCheck.ID = ownedID
+		}
+	}
+
+	// /////////////////////////////
+	// Update logic
+	// ////////////////////////////
+
+	// Determine if it's a new object or if it's an update to an existing object
+	if browserCheck.Status.ID != "" {
+		// Existing object, we need to update it
+		logger.V(1).Info("Existing object, with ID", "checkly ID", browserCheck.Status.ID)
+		drift, found, err := external.BrowserCheckDrift(ctx, internalCheck, apiClient)
+		if err != nil {
+			logger.Error(err, "Failed to get the checkly browser check")
+			setSyncFailed(&browserCheck.Status.SyncStatus, browserCheck.Generation, err)
+			recordEvent(r.Recorder, browserCheck, corev1.EventTypeWarning, eventReasonSyncFailed, fmt.Sprintf("Failed to sync with checklyhq.com: %s", err))
+			updateStatus(ctx, r.Client, browserCheck)
+			return syncFailedResult(err)
+		}
+
+		if found {
+			if len(drift) != 0 {
+				logger.Info("Checkly browser check changed outside of the operator, overwriting", "checkly ID", browserCheck.Status.ID, "fields", drift)
+				recordEvent(r.Recorder, browserCheck, corev1.EventTypeWarning, eventReasonDriftCorrected, fmt.Sprintf("Checkly browser check %s was changed outside of the operator, reverting: %s", browserCheck.Status.ID, strings.Join(drift, ", ")))
+			}
+			// A resync of an unchanged check only compares it, updating it anyway would spend the rate limit of the account
+			if len(drift) != 0 || !isSynced(&browserCheck.Status.SyncStatus, browserCheck.Generation) {
+				err = external.UpdateBrowserCheck(ctx, internalCheck, apiClient)
+				if err != nil {
+					logger.Error(err, "Failed to update the checkly browser check")
+					setSyncFailed(&browserCheck.Status.SyncStatus, browserCheck.Generation, err)
+					recordEvent(r.Recorder, browserCheck, corev1.EventTypeWarning, eventReasonSyncFailed, fmt.Sprintf("Failed to sync with checklyhq.com: %s", err))
+					updateStatus(ctx, r.Client, browserCheck)
+					return syncFailedResult(err)
+				}
+				logger.Info("Updated checkly browser check", "checkly ID", browserCheck.Status.ID)
+				if !isSynced(&browserCheck.Status.SyncStatus, browserCheck.Generation) {
+					recordEvent(r.Recorder, browserCheck, corev1.EventTypeNormal, eventReasonUpdated, fmt.Sprintf("Updated checkly browser check %s", browserCheck.Status.ID))
+				}
+			}
+
+			browserCheck.Status.GroupID = group.Status.ID
+			browserCheck.Status.Account = account
+			setDrift(&browserCheck.Status.SyncStatus, browserCheck.Generation, drift)
+			setSynced(&browserCheck.Status.SyncStatus, browserCheck.Generation)
+			err = r.Status().Update(ctx, browserCheck)
+			if err != nil {
+				logger.Error(err, "Failed to update BrowserCheck status")
+				return ctrl.Result{}, err
+			}
+			return ctrl.Result{RequeueAfter: r.ResyncInterval}, nil
+		}
+
+		// The check was deleted outside of the operator, we create it again
+		logger.Info("Checkly browser check not found, recreating", "checkly ID", browserCheck.Status.ID)
+		recordEvent(r.Recorder, browserCheck, corev1.EventTypeWarning, eventReasonRecreated, fmt.Sprintf("Checkly browser check %s was deleted outside of the operator, recreating", browserCheck.Status.ID))
+		setRecreated(&browserCheck.Status.SyncStatus, browserCheck.Generation)
+	}
+
+	// /////////////////////////////
+	// Create logic
+	// ////////////////////////////
+
+	checklyID, err := external.CreateBrowserCheck(ctx, internalCheck, apiClient)
+	if err != nil {
+		logger.Error(err, "Failed to create checkly browser check")
+		setSyncFailed(&browserCheck.Status.SyncStatus, browserCheck.Generation, err)
+		recordEvent(r.Recorder, browserCheck, corev1.EventTypeWarning, eventReasonSyncFailed, fmt.Sprintf("Failed to sync with checklyhq.com: %s", err))
+		updateStatus(ctx, r.Client, browserCheck)
+		return syncFailedResult(err)
+	}
+
+	// Update the custom resource Status with the returned ID
+	browserCheck.Status.ID = checklyID
+	browserCheck.Status.GroupID = group.Status.ID
+	browserCheck.Status.Account = account
+	setSynced(&browserCheck.Status.SyncStatus, browserCheck.Generation)
+	err = r.Status().Update(ctx, browserCheck)
+	if err != nil {
+		logger.Error(err, "Failed to update BrowserCheck status")
+		return ctrl.Result{}, err
+	}
+	logger.V(1).Info("New checkly browser check created with", "checkly ID", browserCheck.Status.ID)
+	recordEvent(r.Recorder, browserCheck, corev1.EventTypeNormal, eventReasonCreated, fmt.Sprintf("Created checkly browser check %s", browserCheck.Status.ID))
+
+	return ctrl.Result{RequeueAfter: r.ResyncInterval}, nil
+}
+
+// getScript returns the Playwright script, either from the referenced ConfigMap or the inline value
+func (r *BrowserCheckReconciler) getScript(ctx context.Context, browserCheck *checklyv1alpha1.BrowserCheck) (script string, err error) {
+	if browserCheck.Spec.ScriptConfigMap == nil {
+		script = browserCheck.Spec.Script
+		if script == "" {
+			err = errs.New("either script or scriptConfigMap has to be set")
+		}
+		return
+	}
+
+	configMap := &corev1.ConfigMap{}
+	err = r.Get(ctx,
+		types.NamespacedName{
+			Name:      browserCheck.Spec.ScriptConfigMap.Name,
+			Namespace: browserCheck.Namespace},
+		configMap)
+	if err != nil {
+		return
+	}
+
+	script = configMap.Data[browserCheck.Spec.ScriptConfigMap.Key]
+	if script == "" {
+		err = fmt.Errorf("key %s in configmap %s is empty", browserCheck.Spec.ScriptConfigMap.Key, configMap.Name)
+	}
+
+	return
+}
+
+// findBrowserChecksForConfigMap maps a ConfigMap to the BrowserChecks which read their script from it
+func (r *BrowserCheckReconciler) findBrowserChecksForConfigMap(ctx context.Context, configMap client.Object) []reconcile.Request {
+	browserChecks := &checklyv1alpha1.BrowserCheckList{}
+	err := r.List(ctx, browserChecks,
+		client.InNamespace(configMap.GetNamespace()),
+		client.MatchingFields{browserCheckConfigMapIndex: configMap.GetName()})
+	if err != nil {
+		return []reconcile.Request{}
+	}
+
+	requests := make([]reconcile.Request, len(browserChecks.Items))
+	for i, item := range browserChecks.Items {
+		requests[i] = reconcile.Request{
+			NamespacedName: types.NamespacedName{
+				Name:      item.Name,
+				Namespace: item.Namespace,
+			},
+		}
+	}
+	return requests
+}
+
+// findBrowserChecksForGroup maps a Group or CheckGroup to the BrowserChecks referencing it, a cluster scoped Group can be
+// referenced from every namespace
+func (r *BrowserCheckReconciler) findBrowserChecksForGroup(ctx context.Context, group client.Object) []reconcile.Request {
+	browserChecks := &checklyv1alpha1.BrowserCheckList{}
+	err := r.List(ctx, browserChecks,
+		client.InNamespace(group.GetNamespace()),
+		client.MatchingFields{checkGroupIndex: group.GetName()})
+	if err != nil {
+		return []reconcile.Request{}
+	}
+
+	requests := make([]reconcile.Request, len(browserChecks.Items))
+	for i, item := range browserChecks.Items {
+		requests[i] = reconcile.Request{
+			NamespacedName: types.NamespacedName{
+				Name:      item.Name,
+				Namespace: item.Namespace,
+			},
+		}
+	}
+	return requests
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *BrowserCheckReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	err := mgr.GetFieldIndexer().IndexField(context.Background(), &checklyv1alpha1.BrowserCheck{}, browserCheckConfigMapIndex, func(rawObj client.Object) []string {
+		browserCheck := rawObj.(*checklyv1alpha1.BrowserCheck)
+		if browserCheck.Spec.ScriptConfigMap == nil {
+			return nil
+		}
+		return []string{browserCheck.Spec.ScriptConfigMap.Name}
+	})
+	if err != nil {
+		return err
+	}
+
+	err = mgr.GetFieldIndexer().IndexField(context.Background(), &checklyv1alpha1.BrowserCheck{}, checkGroupIndex, browserCheckGroupIndexValues)
+	if err != nil {
+		return err
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&checklyv1alpha1.BrowserCheck{}, specChanged).
+		Watches(
+			&corev1.ConfigMap{},
+			handler.EnqueueRequestsFromMapFunc(r.findBrowserChecksForConfigMap),
+		).
+		Watches(
+			&checklyv1alpha1.Group{},
+			handler.EnqueueRequestsFromMapFunc(r.findBrowserChecksForGroup),
+			dependencyChanged,
+		).
+		Watches(
+			&checklyv1alpha1.CheckGroup{},
+			handler.EnqueueRequestsFromMapFunc(r.findBrowserChecksForGroup),
+			dependencyChanged,
+		).
+		Complete(r)
+}
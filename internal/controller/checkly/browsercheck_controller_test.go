@@ -0,0 +1,141 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package checkly
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	checklyv1alpha1 "github.com/checkly/checkly-operator/api/checkly/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+var _ = Describe("BrowserCheck Controller", func() {
+
+	// Define utility constants for object names and testing timeouts/durations and intervals.
+	const (
+		timeout  = time.Second * 10
+		duration = time.Second * 10
+		interval = time.Millisecond * 250
+	)
+
+	Context("BrowserCheck", func() {
+		It("Full reconciliation", func() {
+
+			key := types.NamespacedName{
+				Name:      "test-browsercheck",
+				Namespace: "default",
+			}
+
+			groupKey := types.NamespacedName{
+				Name: "test-browsercheck-group",
+			}
+
+			configMapKey := types.NamespacedName{
+				Name:      "test-browsercheck-script",
+				Namespace: "default",
+			}
+
+			group := &checklyv1alpha1.Group{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: groupKey.Name,
+				},
+			}
+
+			configMap := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      configMapKey.Name,
+					Namespace: configMapKey.Namespace,
+				},
+				Data: map[string]string{
+					"script.js": "console.log('foo')",
+				},
+			}
+
+			browserCheck := &checklyv1alpha1.BrowserCheck{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      key.Name,
+					Namespace: key.Namespace,
+				},
+				Spec: checklyv1alpha1.BrowserCheckSpec{
+					Group: groupKey.Name,
+					Muted: true,
+					ScriptConfigMap: &corev1.ConfigMapKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{
+							Name: configMapKey.Name,
+						},
+						Key: "script.js",
+					},
+				},
+			}
+
+			// Create
+			Expect(k8sClient.Create(context.Background(), group)).Should(Succeed())
+			Expect(k8sClient.Create(context.Background(), configMap)).Should(Succeed())
+			Expect(k8sClient.Create(context.Background(), browserCheck)).Should(Succeed())
+
+			// Status.ID should be present
+			By("Expecting check ID")
+			Eventually(func() bool {
+				f := &checklyv1alpha1.BrowserCheck{}
+				err := k8sClient.Get(context.Background(), key, f)
+				if err != nil {
+					return false
+				}
+				return f.Status.ID == "2"
+			}, timeout, interval).Should(BeTrue())
+
+			// Finalizer should be present
+			By("Expecting finalizer")
+			Eventually(func() bool {
+				f := &checklyv1alpha1.BrowserCheck{}
+				err := k8sClient.Get(context.Background(), key, f)
+				if err != nil {
+					return false
+				}
+
+				for _, finalizer := range f.Finalizers {
+					Expect(finalizer).To(Equal("testing.domain.tld/finalizer"), "Finalizer should match")
+				}
+
+				return true
+			}, timeout, interval).Should(BeTrue())
+
+			// Delete
+			Expect(k8sClient.Delete(context.Background(), group)).Should(Succeed())
+			Expect(k8sClient.Delete(context.Background(), configMap)).Should(Succeed())
+
+			By("Expecting to delete successfully")
+			Eventually(func() error {
+				f := &checklyv1alpha1.BrowserCheck{}
+				k8sClient.Get(context.Background(), key, f)
+				return k8sClient.Delete(context.Background(), f)
+			}, timeout, interval).Should(Succeed())
+
+			By("Expecting delete to finish")
+			Eventually(func() error {
+				f := &checklyv1alpha1.BrowserCheck{}
+				return k8sClient.Get(context.Background(), key, f)
+			}, timeout, interval).ShouldNot(Succeed())
+		})
+	})
+})
@@ -0,0 +1,121 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package checkly
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	checklyv1alpha1 "github.com/checkly/checkly-operator/api/checkly/v1alpha1"
+)
+
+// CheckGroupReconciler reconciles a CheckGroup object, it shares the reconcile logic of the GroupReconciler
+type CheckGroupReconciler struct {
+	GroupReconciler
+}
+
+//+kubebuilder:rbac:groups=k8s.checklyhq.com,resources=checkgroups,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=k8s.checklyhq.com,resources=checkgroups/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=k8s.checklyhq.com,resources=checkgroups/finalizers,verbs=update
+//+kubebuilder:rbac:groups=k8s.checklyhq.com,resources=namespacedalertchannels,verbs=get;list;watch
+//+kubebuilder:rbac:groups=k8s.checklyhq.com,resources=alertchannels,verbs=get;list;watch
+//+kubebuilder:rbac:groups=k8s.checklyhq.com,resources=apichecks,verbs=get;list;watch;update;patch;delete
+//+kubebuilder:rbac:groups=k8s.checklyhq.com,resources=browserchecks,verbs=get;list;watch;update;patch;delete
+
+// Reconcile is part of the main kubernetes reconciliation loop which aims to
+// move the current state of the cluster closer to the desired state.
+func (r *CheckGroupReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	logger.V(1).Info("Reconciler started")
+
+	group := &checklyv1alpha1.CheckGroup{}
+
+	err := r.Get(ctx, req.NamespacedName, group)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			// The resource has been deleted
+			logger.Info("Deleted", "group ID", group.Status.ID, "name", req.Name)
+			return ctrl.Result{}, nil
+		}
+		// Error reading the object
+		logger.Error(err, "can't read the CheckGroup object")
+		return ctrl.Result{}, nil
+	}
+
+	return r.reconcileGroup(ctx, group, &group.Spec, &group.Status)
+}
+
+// findCheckGroupsForAlertChannel maps an AlertChannel or NamespacedAlertChannel to the CheckGroups subscribed to it, a
+// cluster scoped AlertChannel can be referenced from every namespace
+func (r *CheckGroupReconciler) findCheckGroupsForAlertChannel(ctx context.Context, alertChannel client.Object) []reconcile.Request {
+	checkGroups := &checklyv1alpha1.CheckGroupList{}
+	err := r.List(ctx, checkGroups,
+		client.InNamespace(alertChannel.GetNamespace()),
+		client.MatchingFields{groupAlertChannelIndex: alertChannel.GetName()})
+	if err != nil {
+		return []reconcile.Request{}
+	}
+
+	requests := make([]reconcile.Request, len(checkGroups.Items))
+	for i, item := range checkGroups.Items {
+		requests[i] = reconcile.Request{
+			NamespacedName: types.NamespacedName{
+				Name:      item.Name,
+				Namespace: item.Namespace,
+			},
+		}
+	}
+	return requests
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *CheckGroupReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	err := mgr.GetFieldIndexer().IndexField(context.Background(), &checklyv1alpha1.CheckGroup{}, groupAlertChannelIndex, checkGroupAlertChannelIndexValues)
+	if err != nil {
+		return err
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&checklyv1alpha1.CheckGroup{}, specChanged).
+		Watches(
+			&checklyv1alpha1.AlertChannel{},
+			handler.EnqueueRequestsFromMapFunc(r.findCheckGroupsForAlertChannel),
+			dependencyChanged,
+		).
+		Watches(
+			&checklyv1alpha1.NamespacedAlertChannel{},
+			handler.EnqueueRequestsFromMapFunc(r.findCheckGroupsForAlertChannel),
+			dependencyChanged,
+		).
+		Watches(
+			&checklyv1alpha1.ApiCheck{},
+			handler.EnqueueRequestsFromMapFunc(r.findDeletingCheckGroupsForCheck),
+		).
+		Watches(
+			&checklyv1alpha1.BrowserCheck{},
+			handler.EnqueueRequestsFromMapFunc(r.findDeletingCheckGroupsForCheck),
+		).
+		Complete(r)
+}
@@ -0,0 +1,152 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package checkly
+
+import (
+	"context"
+	"time"
+
+	"github.com/checkly/checkly-go-sdk"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	checklyv1alpha1 "github.com/checkly/checkly-operator/api/checkly/v1alpha1"
+)
+
+var _ = Describe("CheckGroup Controller", func() {
+
+	// Define utility constants for object names and testing timeouts/durations and intervals.
+	const (
+		timeout  = time.Second * 10
+		duration = time.Second * 10
+		interval = time.Millisecond * 250
+	)
+
+	Context("CheckGroup", func() {
+		It("Full reconciliation", func() {
+
+			groupKey := types.NamespacedName{
+				Name:      "test-checkgroup",
+				Namespace: "default",
+			}
+
+			alertChannelKey := types.NamespacedName{
+				Name:      "test-namespacedalertchannel",
+				Namespace: "default",
+			}
+
+			apiCheckKey := types.NamespacedName{
+				Name:      "test-checkgroup-apicheck",
+				Namespace: "default",
+			}
+
+			alertChannel := &checklyv1alpha1.NamespacedAlertChannel{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      alertChannelKey.Name,
+					Namespace: alertChannelKey.Namespace,
+				},
+				Spec: checklyv1alpha1.AlertChannelSpec{
+					Email: checkly.AlertChannelEmail{
+						Address: "foo@bar.baz",
+					},
+				},
+			}
+
+			group := &checklyv1alpha1.CheckGroup{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      groupKey.Name,
+					Namespace: groupKey.Namespace,
+				},
+				Spec: checklyv1alpha1.GroupSpec{
+					Locations:     []string{"eu-west-1"},
+					AlertChannels: []string{alertChannelKey.Name},
+				},
+			}
+
+			apiCheck := &checklyv1alpha1.ApiCheck{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      apiCheckKey.Name,
+					Namespace: apiCheckKey.Namespace,
+				},
+				Spec: checklyv1alpha1.ApiCheckSpec{
+					Endpoint: "http://bar.baz/quoz",
+					Success:  "200",
+					Group:    groupKey.Name,
+				},
+			}
+
+			// Create
+			Expect(k8sClient.Create(context.Background(), alertChannel)).Should(Succeed())
+			Expect(k8sClient.Create(context.Background(), group)).Should(Succeed())
+			Expect(k8sClient.Create(context.Background(), apiCheck)).Should(Succeed())
+
+			// Status.ID should be present
+			By("Expecting alert channel ID")
+			Eventually(func() bool {
+				f := &checklyv1alpha1.NamespacedAlertChannel{}
+				err := k8sClient.Get(context.Background(), alertChannelKey, f)
+				return err == nil && f.Status.ID == 3
+			}, timeout, interval).Should(BeTrue())
+
+			By("Expecting group ID")
+			Eventually(func() bool {
+				f := &checklyv1alpha1.CheckGroup{}
+				err := k8sClient.Get(context.Background(), groupKey, f)
+				if err != nil {
+					return false
+				}
+				return f.Status.ID == 1 && meta.IsStatusConditionTrue(f.Status.Conditions, checklyv1alpha1.ConditionReady)
+			}, timeout, interval).Should(BeTrue())
+
+			// The check resolves the CheckGroup in its own namespace
+			By("Expecting check group ID")
+			Eventually(func() bool {
+				f := &checklyv1alpha1.ApiCheck{}
+				err := k8sClient.Get(context.Background(), apiCheckKey, f)
+				return err == nil && f.Status.GroupID == 1
+			}, timeout, interval).Should(BeTrue())
+
+			// Delete
+			By("Expecting to delete successfully")
+			Eventually(func() error {
+				f := &checklyv1alpha1.ApiCheck{}
+				k8sClient.Get(context.Background(), apiCheckKey, f)
+				return k8sClient.Delete(context.Background(), f)
+			}, timeout, interval).Should(Succeed())
+
+			Eventually(func() error {
+				f := &checklyv1alpha1.CheckGroup{}
+				k8sClient.Get(context.Background(), groupKey, f)
+				return k8sClient.Delete(context.Background(), f)
+			}, timeout, interval).Should(Succeed())
+
+			Eventually(func() error {
+				f := &checklyv1alpha1.NamespacedAlertChannel{}
+				k8sClient.Get(context.Background(), alertChannelKey, f)
+				return k8sClient.Delete(context.Background(), f)
+			}, timeout, interval).Should(Succeed())
+
+			By("Expecting delete to finish")
+			Eventually(func() error {
+				f := &checklyv1alpha1.CheckGroup{}
+				return k8sClient.Get(context.Background(), groupKey, f)
+			}, timeout, interval).ShouldNot(Succeed())
+		})
+	})
+})
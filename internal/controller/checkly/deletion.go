@@ -0,0 +1,407 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package checkly
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	checklyv1alpha1 "github.com/checkly/checkly-operator/api/checkly/v1alpha1"
+)
+
+// maxBlockingDependents limits the number of dependents listed in the status and events of a blocked deletion
+const maxBlockingDependents = 5
+
+// effectiveDeletionPolicy returns the deletion policy of a resource, the default policy of the operator applies if it's not set
+func effectiveDeletionPolicy(policy string, defaultPolicy string) string {
+	if policy != "" {
+		return policy
+	}
+	if defaultPolicy != "" {
+		return defaultPolicy
+	}
+	return checklyv1alpha1.DeletionPolicyDelete
+}
+
+// retainedTag returns the tag of the checks and groups kept in checklyhq.com after their resource was deleted, ex.
+// k8s.checklyhq.com/retained:team-a/foo, so they can be found and adopted again
+func retainedTag(controllerDomain string, obj client.Object) string {
+	name := obj.GetName()
+	if obj.GetNamespace() != "" {
+		name = fmt.Sprintf("%s/%s", obj.GetNamespace(), name)
+	}
+	return fmt.Sprintf("%s/retained:%s", controllerDomain, name)
+}
+
+// releaseWithoutRemoteDeletion removes the finalizer of a deleted resource without calling checklyhq.com if the resource is
+// abandoned or never got a checklyhq.com ID, released is false if the checklyhq.com resource still has to be handled
+func releaseWithoutRemoteDeletion(ctx context.Context, c client.Client, recorder record.EventRecorder, obj client.Object, finalizer string, controllerDomain string, synced bool) (released bool, err error) {
+	logger := log.FromContext(ctx)
+
+	switch {
+	case isAbandoned(obj, controllerDomain):
+		logger.Info("Abandoned, removing the finalizer without touching checklyhq.com")
+		recordEvent(recorder, obj, corev1.EventTypeWarning, eventReasonAbandoned, fmt.Sprintf("Removed the finalizer because of the %s annotation, checklyhq.com was left untouched", abandonAnnotation(controllerDomain)))
+	case !synced:
+		logger.Info("Never synced with checklyhq.com, nothing to delete")
+	default:
+		return
+	}
+
+	controllerutil.RemoveFinalizer(obj, finalizer)
+	err = c.Update(ctx, obj)
+	if err != nil {
+		logger.Error(err, "Failed to delete finalizer")
+		return
+	}
+	logger.V(1).Info("Successfully deleted finalizer")
+
+	released = true
+	return
+}
+
+// checkGroupReference returns the group name of an ApiCheck or BrowserCheck, the checklyhq.com group ID and account it was last synced to
+func checkGroupReference(obj client.Object) (group string, kind string, groupID int64, account string) {
+	switch check := obj.(type) {
+	case *checklyv1alpha1.ApiCheck:
+		return check.Spec.Group, check.Spec.GroupKind, check.Status.GroupID, check.Status.Account
+	case *checklyv1alpha1.BrowserCheck:
+		return check.Spec.Group, check.Spec.GroupKind, check.Status.GroupID, check.Status.Account
+	}
+	return "", "", 0, ""
+}
+
+// groupAlertChannels returns the alert channels a Group or CheckGroup is subscribed to
+func groupAlertChannels(obj client.Object) []string {
+	switch group := obj.(type) {
+	case *checklyv1alpha1.Group:
+		return group.Spec.AlertChannels
+	case *checklyv1alpha1.CheckGroup:
+		return group.Spec.AlertChannels
+	}
+	return nil
+}
+
+// dependentName describes a dependent in status messages and events, ex. ApiCheck team-a/foo
+func dependentName(obj client.Object) string {
+	kind := "Group"
+	switch obj.(type) {
+	case *checklyv1alpha1.ApiCheck:
+		kind = "ApiCheck"
+	case *checklyv1alpha1.BrowserCheck:
+		kind = "BrowserCheck"
+	case *checklyv1alpha1.CheckGroup:
+		kind = "CheckGroup"
+	}
+	return fmt.Sprintf("%s %s", kind, client.ObjectKeyFromObject(obj))
+}
+
+// dependentNames lists the first dependents for status messages and events
+func dependentNames(dependents []client.Object) string {
+	var names []string
+	for i, dependent := range dependents {
+		if i == maxBlockingDependents {
+			names = append(names, fmt.Sprintf("%d more", len(dependents)-maxBlockingDependents))
+			break
+		}
+		names = append(names, dependentName(dependent))
+	}
+	return strings.Join(names, ", ")
+}
+
+// groupDependents returns the ApiChecks and BrowserChecks of a Group or CheckGroup: the checks whose group reference resolves
+// to it and the checks which are still in its checklyhq.com group, ex. while they're moved to another group
+func groupDependents(ctx context.Context, c client.Client, obj client.Object, status *checklyv1alpha1.GroupStatus, account string) (dependents []client.Object, err error) {
+	var checks []client.Object
+
+	// A cluster scoped Group can be referenced from every namespace
+	apiChecks := &checklyv1alpha1.ApiCheckList{}
+	err = c.List(ctx, apiChecks, client.InNamespace(obj.GetNamespace()))
+	if err != nil {
+		return
+	}
+	for i := range apiChecks.Items {
+		checks = append(checks, &apiChecks.Items[i])
+	}
+
+	browserChecks := &checklyv1alpha1.BrowserCheckList{}
+	err = c.List(ctx, browserChecks, client.InNamespace(obj.GetNamespace()))
+	if err != nil {
+		return
+	}
+	for i := range browserChecks.Items {
+		checks = append(checks, &browserChecks.Items[i])
+	}
+
+	for _, check := range checks {
+		name, kind, groupID, checkAccount := checkGroupReference(check)
+		if status.ID != 0 && groupID == status.ID && checkAccount == account {
+			dependents = append(dependents, check)
+			continue
+		}
+		if name != obj.GetName() {
+			continue
+		}
+
+		// An ambiguous reference isn't synced, the group the check was synced to is found by its ID above
+		group, _, groupErr := getGroup(ctx, c, check.GetNamespace(), name, kind)
+		if groupErr != nil {
+			if _, ambiguous := groupErr.(*ambiguousGroupError); ambiguous || errors.IsNotFound(groupErr) {
+				continue
+			}
+			err = groupErr
+			return
+		}
+		if group.UID == obj.GetUID() {
+			dependents = append(dependents, check)
+		}
+	}
+
+	return
+}
+
+// alertChannelDependents returns the Groups and CheckGroups subscribed to an AlertChannel or NamespacedAlertChannel
+func alertChannelDependents(ctx context.Context, c client.Client, obj client.Object) (dependents []client.Object, err error) {
+	if obj.GetNamespace() == "" {
+		groups := &checklyv1alpha1.GroupList{}
+		err = c.List(ctx, groups)
+		if err != nil {
+			return
+		}
+		for i := range groups.Items {
+			if slices.Contains(groups.Items[i].Spec.AlertChannels, obj.GetName()) {
+				dependents = append(dependents, &groups.Items[i])
+			}
+		}
+	}
+
+	// A cluster scoped AlertChannel can be referenced by the CheckGroups of every namespace
+	checkGroups := &checklyv1alpha1.CheckGroupList{}
+	err = c.List(ctx, checkGroups, client.InNamespace(obj.GetNamespace()))
+	if err != nil {
+		return
+	}
+	for i := range checkGroups.Items {
+		checkGroup := &checkGroups.Items[i]
+		if !slices.Contains(checkGroup.Spec.AlertChannels, obj.GetName()) {
+			continue
+		}
+
+		// A NamespacedAlertChannel in the namespace of the CheckGroup takes precedence over a cluster scoped AlertChannel
+		if obj.GetNamespace() == "" {
+			namespacedAlertChannel := &checklyv1alpha1.NamespacedAlertChannel{}
+			getErr := c.Get(ctx, types.NamespacedName{Name: obj.GetName(), Namespace: checkGroup.Namespace}, namespacedAlertChannel)
+			if getErr == nil {
+				continue
+			}
+			if !errors.IsNotFound(getErr) {
+				err = getErr
+				return
+			}
+		}
+
+		dependents = append(dependents, checkGroup)
+	}
+
+	return
+}
+
+// handleGroupDependents applies the dependents policy of a Group or CheckGroup which is deleted while checks still belong to it,
+// the deletion stays blocked until the checks are gone or moved. The group is reconciled again when one of its checks changes.
+func (r *GroupReconciler) handleGroupDependents(ctx context.Context, obj client.Object, spec *checklyv1alpha1.GroupSpec, status *checklyv1alpha1.GroupStatus, dependents []client.Object) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	message := fmt.Sprintf("Deletion is blocked by %s, delete them or move them to another group", dependentNames(dependents))
+
+	switch spec.DependentsPolicy {
+	case checklyv1alpha1.DependentsPolicyDelete:
+		for _, dependent := range dependents {
+			if dependent.GetDeletionTimestamp() != nil {
+				continue
+			}
+			err := r.Delete(ctx, dependent)
+			if err != nil && !errors.IsNotFound(err) {
+				logger.Error(err, "Failed to delete the check of the group", "check", dependentName(dependent))
+				return ctrl.Result{}, err
+			}
+			recordEvent(r.Recorder, obj, corev1.EventTypeNormal, eventReasonDependentDeleted, fmt.Sprintf("Deleted %s of the deleted group", dependentName(dependent)))
+		}
+		message = fmt.Sprintf("Waiting for %s to be deleted", dependentNames(dependents))
+
+	case checklyv1alpha1.DependentsPolicyReparent:
+		for _, dependent := range dependents {
+			name, _, _, _ := checkGroupReference(dependent)
+			if name != obj.GetName() || dependent.GetDeletionTimestamp() != nil {
+				continue
+			}
+			patch := client.MergeFrom(dependent.DeepCopyObject().(client.Object))
+			switch check := dependent.(type) {
+			case *checklyv1alpha1.ApiCheck:
+				check.Spec.Group = spec.ReparentTo
+				check.Spec.GroupKind = ""
+			case *checklyv1alpha1.BrowserCheck:
+				check.Spec.Group = spec.ReparentTo
+				check.Spec.GroupKind = ""
+			}
+			err := r.Patch(ctx, dependent, patch)
+			if err != nil && !errors.IsNotFound(err) {
+				logger.Error(err, "Failed to move the check to another group", "check", dependentName(dependent), "group", spec.ReparentTo)
+				return ctrl.Result{}, err
+			}
+			recordEvent(r.Recorder, obj, corev1.EventTypeNormal, eventReasonDependentReparented, fmt.Sprintf("Moved %s to group %s", dependentName(dependent), spec.ReparentTo))
+		}
+		message = fmt.Sprintf("Waiting for %s to be moved to group %s", dependentNames(dependents), spec.ReparentTo)
+	}
+
+	logger.Info("Group deletion is blocked", "dependents", len(dependents), "policy", spec.DependentsPolicy)
+	setDeletionBlocked(&status.SyncStatus, obj.GetGeneration(), message)
+	recordEvent(r.Recorder, obj, corev1.EventTypeWarning, eventReasonDeletionBlocked, message)
+	updateStatus(ctx, r.Client, obj)
+
+	return ctrl.Result{}, nil
+}
+
+// handleAlertChannelDependents applies the dependents policy of an alert channel which is deleted while groups are still
+// subscribed to it, false is returned if the deletion is blocked. The alert channel is reconciled again when one of its groups changes.
+func (r *AlertChannelReconciler) handleAlertChannelDependents(ctx context.Context, obj client.Object, spec *checklyv1alpha1.AlertChannelSpec, status *checklyv1alpha1.AlertChannelStatus, dependents []client.Object) (bool, error) {
+	logger := log.FromContext(ctx)
+
+	if spec.DependentsPolicy != checklyv1alpha1.DependentsPolicyUnsubscribe {
+		message := fmt.Sprintf("Deletion is blocked by %s, remove the alert channel from them first", dependentNames(dependents))
+		logger.Info("Alert channel deletion is blocked", "dependents", len(dependents))
+		setDeletionBlocked(&status.SyncStatus, obj.GetGeneration(), message)
+		recordEvent(r.Recorder, obj, corev1.EventTypeWarning, eventReasonDeletionBlocked, message)
+		updateStatus(ctx, r.Client, obj)
+		return false, nil
+	}
+
+	// The groups drop the subscription in checklyhq.com on their next sync, deleting the alert channel removes it as well
+	for _, dependent := range dependents {
+		patch := client.MergeFrom(dependent.DeepCopyObject().(client.Object))
+		switch group := dependent.(type) {
+		case *checklyv1alpha1.Group:
+			group.Spec.AlertChannels = slices.DeleteFunc(group.Spec.AlertChannels, func(name string) bool { return name == obj.GetName() })
+		case *checklyv1alpha1.CheckGroup:
+			group.Spec.AlertChannels = slices.DeleteFunc(group.Spec.AlertChannels, func(name string) bool { return name == obj.GetName() })
+		}
+		err := r.Patch(ctx, dependent, patch)
+		if err != nil && !errors.IsNotFound(err) {
+			logger.Error(err, "Failed to unsubscribe the group from the alert channel", "group", dependentName(dependent))
+			return false, err
+		}
+		recordEvent(r.Recorder, obj, corev1.EventTypeNormal, eventReasonUnsubscribed, fmt.Sprintf("Unsubscribed %s from the deleted alert channel", dependentName(dependent)))
+	}
+
+	return true, nil
+}
+
+// findDeletingGroupsForCheck maps an ApiCheck or BrowserCheck to the deleted Groups waiting for it, either the group it
+// references or the group it's still in
+func (r *GroupReconciler) findDeletingGroupsForCheck(ctx context.Context, check client.Object) []reconcile.Request {
+	groups := &checklyv1alpha1.GroupList{}
+	err := r.List(ctx, groups)
+	if err != nil {
+		return []reconcile.Request{}
+	}
+
+	name, _, groupID, _ := checkGroupReference(check)
+	requests := []reconcile.Request{}
+	for _, item := range groups.Items {
+		if item.DeletionTimestamp == nil || (item.Name != name && (item.Status.ID == 0 || item.Status.ID != groupID)) {
+			continue
+		}
+		requests = append(requests, reconcile.Request{
+			NamespacedName: types.NamespacedName{
+				Name: item.Name,
+			},
+		})
+	}
+	return requests
+}
+
+// findDeletingCheckGroupsForCheck maps an ApiCheck or BrowserCheck to the deleted CheckGroups of its namespace waiting for it
+func (r *CheckGroupReconciler) findDeletingCheckGroupsForCheck(ctx context.Context, check client.Object) []reconcile.Request {
+	checkGroups := &checklyv1alpha1.CheckGroupList{}
+	err := r.List(ctx, checkGroups, client.InNamespace(check.GetNamespace()))
+	if err != nil {
+		return []reconcile.Request{}
+	}
+
+	name, _, groupID, _ := checkGroupReference(check)
+	requests := []reconcile.Request{}
+	for _, item := range checkGroups.Items {
+		if item.DeletionTimestamp == nil || (item.Name != name && (item.Status.ID == 0 || item.Status.ID != groupID)) {
+			continue
+		}
+		requests = append(requests, reconcile.Request{
+			NamespacedName: types.NamespacedName{
+				Name:      item.Name,
+				Namespace: item.Namespace,
+			},
+		})
+	}
+	return requests
+}
+
+// findDeletingAlertChannelsForGroup maps a Group or CheckGroup to the deleted AlertChannels it's subscribed to
+func (r *AlertChannelReconciler) findDeletingAlertChannelsForGroup(ctx context.Context, group client.Object) []reconcile.Request {
+	requests := []reconcile.Request{}
+	for _, name := range groupAlertChannels(group) {
+		alertChannel := &checklyv1alpha1.AlertChannel{}
+		err := r.Get(ctx, types.NamespacedName{Name: name}, alertChannel)
+		if err != nil || alertChannel.DeletionTimestamp == nil {
+			continue
+		}
+		requests = append(requests, reconcile.Request{
+			NamespacedName: types.NamespacedName{
+				Name: name,
+			},
+		})
+	}
+	return requests
+}
+
+// findDeletingNamespacedAlertChannelsForGroup maps a CheckGroup to the deleted NamespacedAlertChannels of its namespace it's subscribed to
+func (r *NamespacedAlertChannelReconciler) findDeletingNamespacedAlertChannelsForGroup(ctx context.Context, group client.Object) []reconcile.Request {
+	requests := []reconcile.Request{}
+	for _, name := range groupAlertChannels(group) {
+		alertChannel := &checklyv1alpha1.NamespacedAlertChannel{}
+		err := r.Get(ctx, types.NamespacedName{Name: name, Namespace: group.GetNamespace()}, alertChannel)
+		if err != nil || alertChannel.DeletionTimestamp == nil {
+			continue
+		}
+		requests = append(requests, reconcile.Request{
+			NamespacedName: types.NamespacedName{
+				Name:      name,
+				Namespace: group.GetNamespace(),
+			},
+		})
+	}
+	return requests
+}
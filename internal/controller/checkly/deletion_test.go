@@ -0,0 +1,501 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package checkly
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/checkly/checkly-go-sdk"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	checklyv1alpha1 "github.com/checkly/checkly-operator/api/checkly/v1alpha1"
+	fakechecklyclient "github.com/checkly/checkly-operator/external/checkly/fake"
+)
+
+const testFinalizer = "testing.domain.tld/finalizer"
+
+// deletionBlocked returns true if the Ready condition reports a blocked deletion
+func deletionBlocked(status checklyv1alpha1.SyncStatus) bool {
+	condition := meta.FindStatusCondition(status.Conditions, checklyv1alpha1.ConditionReady)
+	return condition != nil && condition.Reason == checklyv1alpha1.ReasonDeletionBlocked
+}
+
+func TestGroupDeletionPolicies(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := checklyv1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		name   string
+		policy string
+	}{
+		{"block", ""},
+		{"delete", checklyv1alpha1.DependentsPolicyDelete},
+		{"reparent", checklyv1alpha1.DependentsPolicyReparent},
+	}
+
+	for _, tc := range testCases {
+		apiClient := fakechecklyclient.NewClient()
+		groupID := apiClient.SetGroup(checkly.Group{Name: "shared"})
+
+		now := metav1.Now()
+		group := &checklyv1alpha1.Group{
+			ObjectMeta: metav1.ObjectMeta{Name: "shared", UID: "shared-uid", Finalizers: []string{testFinalizer}, DeletionTimestamp: &now},
+			Spec:       checklyv1alpha1.GroupSpec{DependentsPolicy: tc.policy},
+			Status:     checklyv1alpha1.GroupStatus{ID: groupID},
+		}
+		if tc.policy == checklyv1alpha1.DependentsPolicyReparent {
+			group.Spec.ReparentTo = "other"
+		}
+		objects := []client.Object{
+			group,
+			&checklyv1alpha1.Group{ObjectMeta: metav1.ObjectMeta{Name: "other", UID: "other-uid"}},
+			&checklyv1alpha1.ApiCheck{
+				ObjectMeta: metav1.ObjectMeta{Name: "check-a", Namespace: "team-a"},
+				Spec:       checklyv1alpha1.ApiCheckSpec{Group: "shared"},
+				Status:     checklyv1alpha1.ApiCheckStatus{GroupID: groupID},
+			},
+			// Referencing the CheckGroup of its namespace
+			&checklyv1alpha1.ApiCheck{
+				ObjectMeta: metav1.ObjectMeta{Name: "check-b", Namespace: "team-b"},
+				Spec:       checklyv1alpha1.ApiCheckSpec{Group: "shared"},
+			},
+			&checklyv1alpha1.CheckGroup{ObjectMeta: metav1.ObjectMeta{Name: "shared", Namespace: "team-b", UID: "team-b-uid"}},
+			&checklyv1alpha1.ApiCheck{
+				ObjectMeta: metav1.ObjectMeta{Name: "check-c", Namespace: "team-b"},
+				Spec:       checklyv1alpha1.ApiCheckSpec{Group: "other"},
+			},
+		}
+		c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).
+			WithStatusSubresource(&checklyv1alpha1.Group{}, &checklyv1alpha1.ApiCheck{}).Build()
+
+		r := &GroupReconciler{
+			Client:           c,
+			Scheme:           scheme,
+			ApiClient:        apiClient,
+			ControllerDomain: "testing.domain.tld",
+			Recorder:         record.NewFakeRecorder(10),
+		}
+		ctx := context.Background()
+		req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "shared"}}
+
+		dependents, err := groupDependents(ctx, c, group, &group.Status, "")
+		if err != nil {
+			t.Fatalf("%s: expected no error, got %s", tc.name, err)
+		}
+		if got := dependentNames(dependents); got != "ApiCheck team-a/check-a" {
+			t.Errorf("%s: expected %s, got %s", tc.name, "ApiCheck team-a/check-a", got)
+		}
+
+		if _, err := r.Reconcile(ctx, req); err != nil {
+			t.Fatalf("%s: expected no error, got %s", tc.name, err)
+		}
+
+		if _, ok := apiClient.Groups()[groupID]; !ok {
+			t.Errorf("%s: expected the checkly group to exist", tc.name)
+		}
+		blocked := &checklyv1alpha1.Group{}
+		if err := c.Get(ctx, req.NamespacedName, blocked); err != nil {
+			t.Fatalf("%s: expected the group to exist, got %s", tc.name, err)
+		}
+		if !deletionBlocked(blocked.Status.SyncStatus) {
+			t.Errorf("%s: expected the deletion to be blocked, got %v", tc.name, blocked.Status.Conditions)
+		}
+
+		apiCheck := &checklyv1alpha1.ApiCheck{}
+		err = c.Get(ctx, types.NamespacedName{Name: "check-a", Namespace: "team-a"}, apiCheck)
+		switch tc.policy {
+		case "":
+			if err != nil || apiCheck.Spec.Group != "shared" {
+				t.Fatalf("%s: expected the check to be unchanged, got %v %s", tc.name, apiCheck.Spec, err)
+			}
+			continue
+		case checklyv1alpha1.DependentsPolicyDelete:
+			if !errors.IsNotFound(err) {
+				t.Fatalf("%s: expected the check to be deleted, got %s", tc.name, err)
+			}
+		case checklyv1alpha1.DependentsPolicyReparent:
+			if err != nil || apiCheck.Spec.Group != "other" {
+				t.Fatalf("%s: expected the check to be moved, got %v %s", tc.name, apiCheck.Spec, err)
+			}
+
+			// The check is still in the checklyhq.com group until the ApiCheck controller moved it
+			if _, err := r.Reconcile(ctx, req); err != nil {
+				t.Fatalf("%s: expected no error, got %s", tc.name, err)
+			}
+			if _, ok := apiClient.Groups()[groupID]; !ok {
+				t.Errorf("%s: expected the checkly group to exist until the check is moved", tc.name)
+			}
+			apiCheck.Status.GroupID = groupID + 100
+			if err := c.Status().Update(ctx, apiCheck); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		if _, err := r.Reconcile(ctx, req); err != nil {
+			t.Fatalf("%s: expected no error, got %s", tc.name, err)
+		}
+		if _, ok := apiClient.Groups()[groupID]; ok {
+			t.Errorf("%s: expected the checkly group to be deleted", tc.name)
+		}
+		if err := c.Get(ctx, req.NamespacedName, &checklyv1alpha1.Group{}); !errors.IsNotFound(err) {
+			t.Errorf("%s: expected the group to be deleted, got %s", tc.name, err)
+		}
+	}
+}
+
+func TestAlertChannelDeletionPolicies(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := checklyv1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, policy := range []string{"", checklyv1alpha1.DependentsPolicyUnsubscribe} {
+		apiClient := fakechecklyclient.NewClient()
+		alertChannelID := apiClient.SetAlertChannel(checkly.AlertChannel{Type: "EMAIL"})
+
+		now := metav1.Now()
+		alertChannel := &checklyv1alpha1.AlertChannel{
+			ObjectMeta: metav1.ObjectMeta{Name: "slack", Finalizers: []string{testFinalizer}, DeletionTimestamp: &now},
+			Spec:       checklyv1alpha1.AlertChannelSpec{DependentsPolicy: policy},
+			Status:     checklyv1alpha1.AlertChannelStatus{ID: alertChannelID},
+		}
+		objects := []client.Object{
+			alertChannel,
+			&checklyv1alpha1.Group{
+				ObjectMeta: metav1.ObjectMeta{Name: "shared"},
+				Spec:       checklyv1alpha1.GroupSpec{AlertChannels: []string{"slack", "email"}},
+			},
+			&checklyv1alpha1.CheckGroup{
+				ObjectMeta: metav1.ObjectMeta{Name: "team-group", Namespace: "team-b"},
+				Spec:       checklyv1alpha1.GroupSpec{AlertChannels: []string{"slack"}},
+			},
+			// Subscribed to the NamespacedAlertChannel of its namespace
+			&checklyv1alpha1.NamespacedAlertChannel{ObjectMeta: metav1.ObjectMeta{Name: "slack", Namespace: "team-c"}},
+			&checklyv1alpha1.CheckGroup{
+				ObjectMeta: metav1.ObjectMeta{Name: "shadowed", Namespace: "team-c"},
+				Spec:       checklyv1alpha1.GroupSpec{AlertChannels: []string{"slack"}},
+			},
+		}
+		c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).
+			WithStatusSubresource(&checklyv1alpha1.AlertChannel{}).Build()
+
+		r := &AlertChannelReconciler{
+			Client:           c,
+			Scheme:           scheme,
+			ApiClient:        apiClient,
+			ControllerDomain: "testing.domain.tld",
+			Recorder:         record.NewFakeRecorder(10),
+		}
+		ctx := context.Background()
+		req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "slack"}}
+
+		dependents, err := alertChannelDependents(ctx, c, alertChannel)
+		if err != nil {
+			t.Fatalf("%s: expected no error, got %s", policy, err)
+		}
+		var names []string
+		for _, dependent := range dependents {
+			names = append(names, dependentName(dependent))
+		}
+		sort.Strings(names)
+		if expected := []string{"CheckGroup team-b/team-group", "Group /shared"}; !reflect.DeepEqual(names, expected) {
+			t.Errorf("%s: expected %v, got %v", policy, expected, names)
+		}
+
+		if _, err := r.Reconcile(ctx, req); err != nil {
+			t.Fatalf("%s: expected no error, got %s", policy, err)
+		}
+
+		group := &checklyv1alpha1.Group{}
+		if err := c.Get(ctx, types.NamespacedName{Name: "shared"}, group); err != nil {
+			t.Fatal(err)
+		}
+		shadowed := &checklyv1alpha1.CheckGroup{}
+		if err := c.Get(ctx, types.NamespacedName{Name: "shadowed", Namespace: "team-c"}, shadowed); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(shadowed.Spec.AlertChannels, []string{"slack"}) {
+			t.Errorf("%s: expected %v, got %v", policy, []string{"slack"}, shadowed.Spec.AlertChannels)
+		}
+
+		if policy == "" {
+			blocked := &checklyv1alpha1.AlertChannel{}
+			if err := c.Get(ctx, req.NamespacedName, blocked); err != nil {
+				t.Fatalf("expected the alert channel to exist, got %s", err)
+			}
+			if !deletionBlocked(blocked.Status.SyncStatus) {
+				t.Errorf("Expected the deletion to be blocked, got %v", blocked.Status.Conditions)
+			}
+			if _, ok := apiClient.AlertChannels()[alertChannelID]; !ok {
+				t.Error("Expected the checkly alert channel to exist")
+			}
+			if !reflect.DeepEqual(group.Spec.AlertChannels, []string{"slack", "email"}) {
+				t.Errorf("Expected %v, got %v", []string{"slack", "email"}, group.Spec.AlertChannels)
+			}
+			continue
+		}
+
+		if !reflect.DeepEqual(group.Spec.AlertChannels, []string{"email"}) {
+			t.Errorf("Expected %v, got %v", []string{"email"}, group.Spec.AlertChannels)
+		}
+		checkGroup := &checklyv1alpha1.CheckGroup{}
+		if err := c.Get(ctx, types.NamespacedName{Name: "team-group", Namespace: "team-b"}, checkGroup); err != nil {
+			t.Fatal(err)
+		}
+		if len(checkGroup.Spec.AlertChannels) != 0 {
+			t.Errorf("Expected no alert channels, got %v", checkGroup.Spec.AlertChannels)
+		}
+		if _, ok := apiClient.AlertChannels()[alertChannelID]; ok {
+			t.Error("Expected the checkly alert channel to be deleted")
+		}
+		if err := c.Get(ctx, req.NamespacedName, &checklyv1alpha1.AlertChannel{}); !errors.IsNotFound(err) {
+			t.Errorf("Expected the alert channel to be deleted, got %s", err)
+		}
+	}
+}
+
+func TestAlertChannelRetainDefault(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := checklyv1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, policy := range []string{checklyv1alpha1.DeletionPolicyRetain, checklyv1alpha1.DeletionPolicyOrphan} {
+		apiClient := fakechecklyclient.NewClient()
+		alertChannelID := apiClient.SetAlertChannel(checkly.AlertChannel{Type: "EMAIL"})
+
+		now := metav1.Now()
+		alertChannel := &checklyv1alpha1.AlertChannel{
+			ObjectMeta: metav1.ObjectMeta{Name: "email", Finalizers: []string{testFinalizer}, DeletionTimestamp: &now},
+			Status:     checklyv1alpha1.AlertChannelStatus{ID: alertChannelID},
+		}
+		c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(alertChannel).
+			WithStatusSubresource(&checklyv1alpha1.AlertChannel{}).Build()
+
+		recorder := record.NewFakeRecorder(10)
+		r := &AlertChannelReconciler{
+			Client:           c,
+			Scheme:           scheme,
+			ApiClient:        apiClient,
+			ControllerDomain: "testing.domain.tld",
+			Recorder:         recorder,
+			DeletionPolicy:   policy,
+		}
+		if _, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: types.NamespacedName{Name: "email"}}); err != nil {
+			t.Fatalf("%s: expected no error, got %s", policy, err)
+		}
+
+		if _, ok := apiClient.AlertChannels()[alertChannelID]; !ok {
+			t.Errorf("%s: expected the checkly alert channel to exist", policy)
+		}
+		// Alert channels can't be tagged, Retain falls back to Orphan with a warning
+		expected := fmt.Sprintf("Normal Orphaned Left checkly alert channel %d in checklyhq.com", alertChannelID)
+		if policy == checklyv1alpha1.DeletionPolicyRetain {
+			expected = fmt.Sprintf("Warning Orphaned Left checkly alert channel %d in checklyhq.com, alert channels can't be tagged so the Retain deletion policy falls back to Orphan", alertChannelID)
+		}
+		if event := <-recorder.Events; event != expected {
+			t.Errorf("%s: expected %q, got %q", policy, expected, event)
+		}
+	}
+}
+
+func TestDeletionPolicies(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := checklyv1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		name          string
+		policy        string
+		defaultPolicy string
+		deleted       bool
+		tags          []string
+	}{
+		{"default", "", "", true, nil},
+		{"delete", checklyv1alpha1.DeletionPolicyDelete, checklyv1alpha1.DeletionPolicyRetain, true, nil},
+		{"retain", checklyv1alpha1.DeletionPolicyRetain, "", false, []string{"environment:dev", "testing.domain.tld/retained:team-a/foo"}},
+		{"orphan", checklyv1alpha1.DeletionPolicyOrphan, "", false, []string{"environment:dev"}},
+		{"default retain", "", checklyv1alpha1.DeletionPolicyRetain, false, []string{"environment:dev", "testing.domain.tld/retained:team-a/foo"}},
+	}
+
+	for _, tc := range testCases {
+		apiClient := fakechecklyclient.NewClient()
+		checkID := apiClient.SetCheck(checkly.Check{Name: "foo", Tags: []string{"environment:dev"}})
+
+		now := metav1.Now()
+		apiCheck := &checklyv1alpha1.ApiCheck{
+			ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "team-a", Finalizers: []string{testFinalizer}, DeletionTimestamp: &now},
+			Spec:       checklyv1alpha1.ApiCheckSpec{Group: "shared", DeletionPolicy: tc.policy},
+			Status:     checklyv1alpha1.ApiCheckStatus{ID: checkID},
+		}
+		c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(apiCheck).Build()
+
+		r := &ApiCheckReconciler{
+			Client:           c,
+			Scheme:           scheme,
+			ApiClient:        apiClient,
+			ControllerDomain: "testing.domain.tld",
+			Recorder:         record.NewFakeRecorder(10),
+			DeletionPolicy:   tc.defaultPolicy,
+		}
+		req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "foo", Namespace: "team-a"}}
+		if _, err := r.Reconcile(context.Background(), req); err != nil {
+			t.Fatalf("%s: expected no error, got %s", tc.name, err)
+		}
+
+		if err := c.Get(context.Background(), req.NamespacedName, &checklyv1alpha1.ApiCheck{}); !errors.IsNotFound(err) {
+			t.Errorf("%s: expected the ApiCheck to be deleted, got %s", tc.name, err)
+		}
+		check, found := apiClient.Checks()[checkID]
+		if found == tc.deleted {
+			t.Errorf("%s: expected the checkly check to be deleted %t, got %t", tc.name, tc.deleted, !found)
+			continue
+		}
+		if found && !reflect.DeepEqual(check.Tags, tc.tags) {
+			t.Errorf("%s: expected %v, got %v", tc.name, tc.tags, check.Tags)
+		}
+	}
+
+	// Alert channels can't be tagged, they're kept as they are
+	apiClient := fakechecklyclient.NewClient()
+	alertChannelID := apiClient.SetAlertChannel(checkly.AlertChannel{Type: "EMAIL"})
+	now := metav1.Now()
+	alertChannel := &checklyv1alpha1.AlertChannel{
+		ObjectMeta: metav1.ObjectMeta{Name: "email", Finalizers: []string{testFinalizer}, DeletionTimestamp: &now},
+		Spec:       checklyv1alpha1.AlertChannelSpec{DeletionPolicy: checklyv1alpha1.DeletionPolicyRetain},
+		Status:     checklyv1alpha1.AlertChannelStatus{ID: alertChannelID},
+	}
+	r := &AlertChannelReconciler{
+		Client:           fake.NewClientBuilder().WithScheme(scheme).WithObjects(alertChannel).Build(),
+		Scheme:           scheme,
+		ApiClient:        apiClient,
+		ControllerDomain: "testing.domain.tld",
+		Recorder:         record.NewFakeRecorder(10),
+	}
+	if _, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: types.NamespacedName{Name: "email"}}); err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+	if _, ok := apiClient.AlertChannels()[alertChannelID]; !ok {
+		t.Error("Expected the checkly alert channel to be kept")
+	}
+}
+
+func TestRetainedTag(t *testing.T) {
+	if tag := retainedTag("testing.domain.tld", &checklyv1alpha1.Group{ObjectMeta: metav1.ObjectMeta{Name: "foo"}}); tag != "testing.domain.tld/retained:foo" {
+		t.Errorf("Expected %s, got %s", "testing.domain.tld/retained:foo", tag)
+	}
+}
+
+func TestReleaseWithoutRemoteDeletion(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := checklyv1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+
+	apiClient := fakechecklyclient.NewClient()
+	groupID := apiClient.SetGroup(checkly.Group{Name: "abandoned"})
+	checkID := apiClient.SetCheck(checkly.Check{Name: "synced"})
+	apiClient.RemoveCheck(checkID)
+
+	now := metav1.Now()
+	deleted := metav1.ObjectMeta{Finalizers: []string{testFinalizer}, DeletionTimestamp: &now}
+	objects := []client.Object{
+		// The account and the alert channel are gone, the group is left in checklyhq.com
+		&checklyv1alpha1.Group{
+			ObjectMeta: *deleted.DeepCopy(),
+			Spec:       checklyv1alpha1.GroupSpec{Account: "removed", AlertChannels: []string{"removed"}},
+			Status:     checklyv1alpha1.GroupStatus{ID: groupID},
+		},
+		// Never synced
+		&checklyv1alpha1.ApiCheck{
+			ObjectMeta: *deleted.DeepCopy(),
+			Spec:       checklyv1alpha1.ApiCheckSpec{Group: "abandoned"},
+		},
+		// Deleted in the checklyhq.com UI
+		&checklyv1alpha1.ApiCheck{
+			ObjectMeta: *deleted.DeepCopy(),
+			Spec:       checklyv1alpha1.ApiCheckSpec{Group: "abandoned"},
+			Status:     checklyv1alpha1.ApiCheckStatus{ID: checkID},
+		},
+		// Never synced, the account is gone
+		&checklyv1alpha1.AlertChannel{
+			ObjectMeta: *deleted.DeepCopy(),
+			Spec:       checklyv1alpha1.AlertChannelSpec{Account: "removed"},
+		},
+	}
+	objects[0].SetName("abandoned")
+	objects[0].SetAnnotations(map[string]string{"testing.domain.tld/abandon": "true"})
+	objects[1].SetName("never-synced")
+	objects[1].SetNamespace("default")
+	objects[2].SetName("deleted-remotely")
+	objects[2].SetNamespace("default")
+	objects[3].SetName("never-synced")
+
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build()
+	recorder := record.NewFakeRecorder(10)
+	ctx := context.Background()
+
+	groupReconciler := &GroupReconciler{Client: c, Scheme: scheme, ApiClient: apiClient, ControllerDomain: "testing.domain.tld", Recorder: recorder}
+	if _, err := groupReconciler.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: "abandoned"}}); err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+	if _, ok := apiClient.Groups()[groupID]; !ok {
+		t.Error("Expected the abandoned checkly group to be kept")
+	}
+	if count := apiClient.CallCount(fakechecklyclient.MethodDeleteGroup) + apiClient.CallCount(fakechecklyclient.MethodGetGroup); count != 0 {
+		t.Errorf("Expected no checkly group calls, got %d", count)
+	}
+	if event := <-recorder.Events; event != "Warning Abandoned Removed the finalizer because of the testing.domain.tld/abandon annotation, checklyhq.com was left untouched" {
+		t.Errorf("Unexpected event %s", event)
+	}
+
+	apiCheckReconciler := &ApiCheckReconciler{Client: c, Scheme: scheme, ApiClient: apiClient, ControllerDomain: "testing.domain.tld", Recorder: recorder}
+	for _, name := range []string{"never-synced", "deleted-remotely"} {
+		if _, err := apiCheckReconciler.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: name, Namespace: "default"}}); err != nil {
+			t.Fatalf("%s: expected no error, got %s", name, err)
+		}
+	}
+	if count := apiClient.CallCount(fakechecklyclient.MethodDelete); count != 1 {
+		t.Errorf("Expected %d delete call, got %d", 1, count)
+	}
+
+	alertChannelReconciler := &AlertChannelReconciler{Client: c, Scheme: scheme, ApiClient: apiClient, ControllerDomain: "testing.domain.tld", Recorder: recorder}
+	if _, err := alertChannelReconciler.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: "never-synced"}}); err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+
+	for _, obj := range objects {
+		if err := c.Get(ctx, client.ObjectKeyFromObject(obj), obj); !errors.IsNotFound(err) {
+			t.Errorf("Expected %s to be deleted, got %v", dependentName(obj), err)
+		}
+	}
+}
@@ -0,0 +1,94 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package checkly
+
+import (
+	"slices"
+
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	checklyv1alpha1 "github.com/checkly/checkly-operator/api/checkly/v1alpha1"
+)
+
+// checkGroupIndex is the field index used to find the ApiChecks and BrowserChecks referencing a Group or CheckGroup by name
+const checkGroupIndex = ".spec.group"
+
+// groupAlertChannelIndex is the field index used to find the Groups and CheckGroups subscribed to an alert channel by name
+const groupAlertChannelIndex = ".spec.alertchannel"
+
+// apiCheckSecretIndex is the field index used to find the ApiChecks reading header or query parameter values from a Secret by name
+const apiCheckSecretIndex = ".spec.valueFrom"
+
+// dependencyChangedPredicate lets the create and delete events of a Group or alert channel through, and the updates which
+// change its spec or its checklyhq.com ID, so the resources referencing it are reconciled once it's synced instead of polling it
+var dependencyChangedPredicate = predicate.Funcs{
+	UpdateFunc: func(e event.UpdateEvent) bool {
+		return e.ObjectOld.GetGeneration() != e.ObjectNew.GetGeneration() || dependencyID(e.ObjectOld) != dependencyID(e.ObjectNew)
+	},
+}
+
+// dependencyChanged filters the events of the Groups and alert channels watched by the resources referencing them
+var dependencyChanged = builder.WithPredicates(dependencyChangedPredicate)
+
+// dependencyID returns the checklyhq.com ID of a Group, CheckGroup, AlertChannel or NamespacedAlertChannel
+func dependencyID(obj client.Object) int64 {
+	switch dependency := obj.(type) {
+	case *checklyv1alpha1.Group:
+		return dependency.Status.ID
+	case *checklyv1alpha1.CheckGroup:
+		return dependency.Status.ID
+	case *checklyv1alpha1.AlertChannel:
+		return dependency.Status.ID
+	case *checklyv1alpha1.NamespacedAlertChannel:
+		return dependency.Status.ID
+	}
+	return 0
+}
+
+// apiCheckGroupIndexValues returns the group name of an ApiCheck for the checkGroupIndex
+func apiCheckGroupIndexValues(obj client.Object) []string {
+	return []string{obj.(*checklyv1alpha1.ApiCheck).Spec.Group}
+}
+
+// apiCheckSecretIndexValues returns the names of the secrets an ApiCheck reads header and query parameter values from, for the apiCheckSecretIndex
+func apiCheckSecretIndexValues(obj client.Object) (values []string) {
+	apiCheck := obj.(*checklyv1alpha1.ApiCheck)
+	for _, keyValue := range append(slices.Clone(apiCheck.Spec.Headers), apiCheck.Spec.QueryParameters...) {
+		if keyValue.ValueFrom != nil && !slices.Contains(values, keyValue.ValueFrom.Name) {
+			values = append(values, keyValue.ValueFrom.Name)
+		}
+	}
+	return
+}
+
+// browserCheckGroupIndexValues returns the group name of a BrowserCheck for the checkGroupIndex
+func browserCheckGroupIndexValues(obj client.Object) []string {
+	return []string{obj.(*checklyv1alpha1.BrowserCheck).Spec.Group}
+}
+
+// groupAlertChannelIndexValues returns the alert channel names of a Group for the groupAlertChannelIndex
+func groupAlertChannelIndexValues(obj client.Object) []string {
+	return obj.(*checklyv1alpha1.Group).Spec.AlertChannels
+}
+
+// checkGroupAlertChannelIndexValues returns the alert channel names of a CheckGroup for the groupAlertChannelIndex
+func checkGroupAlertChannelIndexValues(obj client.Object) []string {
+	return obj.(*checklyv1alpha1.CheckGroup).Spec.AlertChannels
+}
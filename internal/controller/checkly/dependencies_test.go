@@ -0,0 +1,190 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package checkly
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	checklyv1alpha1 "github.com/checkly/checkly-operator/api/checkly/v1alpha1"
+)
+
+func TestDependencyChanged(t *testing.T) {
+	old := &checklyv1alpha1.Group{
+		ObjectMeta: metav1.ObjectMeta{Name: "foo", Generation: 1},
+	}
+
+	idSet := old.DeepCopy()
+	idSet.Status.ID = 1
+
+	specChanged := old.DeepCopy()
+	specChanged.Generation = 2
+
+	statusChanged := old.DeepCopy()
+	statusChanged.Status.LastError = "failed"
+
+	alertChannel := &checklyv1alpha1.NamespacedAlertChannel{ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "bar"}}
+	alertChannelIDSet := alertChannel.DeepCopy()
+	alertChannelIDSet.Status.ID = 2
+
+	testCases := []struct {
+		name     string
+		old      *checklyv1alpha1.Group
+		new      *checklyv1alpha1.Group
+		expected bool
+	}{
+		{"ID set", old, idSet, true},
+		{"spec changed", old, specChanged, true},
+		{"status changed", old, statusChanged, false},
+	}
+
+	p := dependencyChangedPredicate
+	for _, tc := range testCases {
+		if got := p.Update(event.UpdateEvent{ObjectOld: tc.old, ObjectNew: tc.new}); got != tc.expected {
+			t.Errorf("%s: expected %t, got %t", tc.name, tc.expected, got)
+		}
+	}
+
+	if !p.Update(event.UpdateEvent{ObjectOld: alertChannel, ObjectNew: alertChannelIDSet}) {
+		t.Error("Expected the alert channel ID change to pass")
+	}
+	if !p.Create(event.CreateEvent{Object: old}) || !p.Delete(event.DeleteEvent{Object: old}) {
+		t.Error("Expected create and delete events to pass")
+	}
+}
+
+func TestFindDependents(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := checklyv1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+
+	objects := []runtime.Object{
+		&checklyv1alpha1.ApiCheck{
+			ObjectMeta: metav1.ObjectMeta{Name: "check-a", Namespace: "team-a"},
+			Spec: checklyv1alpha1.ApiCheckSpec{Group: "shared", Headers: []checklyv1alpha1.ApiCheckKeyValue{
+				{Key: "Authorization", ValueFrom: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "token"}, Key: "header"}},
+			}},
+		},
+		&checklyv1alpha1.ApiCheck{
+			ObjectMeta: metav1.ObjectMeta{Name: "check-b", Namespace: "team-b"},
+			Spec:       checklyv1alpha1.ApiCheckSpec{Group: "shared"},
+		},
+		&checklyv1alpha1.ApiCheck{
+			ObjectMeta: metav1.ObjectMeta{Name: "check-c", Namespace: "team-b"},
+			Spec: checklyv1alpha1.ApiCheckSpec{Group: "other", QueryParameters: []checklyv1alpha1.ApiCheckKeyValue{
+				{Key: "token", ValueFrom: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "token"}, Key: "query"}},
+			}},
+		},
+		&checklyv1alpha1.BrowserCheck{
+			ObjectMeta: metav1.ObjectMeta{Name: "browser-b", Namespace: "team-b"},
+			Spec:       checklyv1alpha1.BrowserCheckSpec{Group: "shared"},
+		},
+		&checklyv1alpha1.Group{
+			ObjectMeta: metav1.ObjectMeta{Name: "shared"},
+			Spec:       checklyv1alpha1.GroupSpec{AlertChannels: []string{"email", "slack"}},
+		},
+		&checklyv1alpha1.CheckGroup{
+			ObjectMeta: metav1.ObjectMeta{Name: "team-group", Namespace: "team-b"},
+			Spec:       checklyv1alpha1.GroupSpec{AlertChannels: []string{"slack"}},
+		},
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(objects...).
+		WithIndex(&checklyv1alpha1.ApiCheck{}, checkGroupIndex, apiCheckGroupIndexValues).
+		WithIndex(&checklyv1alpha1.ApiCheck{}, apiCheckSecretIndex, apiCheckSecretIndexValues).
+		WithIndex(&checklyv1alpha1.BrowserCheck{}, checkGroupIndex, browserCheckGroupIndexValues).
+		WithIndex(&checklyv1alpha1.Group{}, groupAlertChannelIndex, groupAlertChannelIndexValues).
+		WithIndex(&checklyv1alpha1.CheckGroup{}, groupAlertChannelIndex, checkGroupAlertChannelIndexValues).
+		Build()
+
+	ctx := context.Background()
+	names := func(requests []reconcile.Request) (names []string) {
+		for _, request := range requests {
+			names = append(names, request.NamespacedName.String())
+		}
+		sort.Strings(names)
+		return
+	}
+
+	apiCheckReconciler := &ApiCheckReconciler{Client: c}
+	testCases := []struct {
+		name     string
+		got      []reconcile.Request
+		expected []string
+	}{
+		{
+			"cluster group",
+			apiCheckReconciler.findApiChecksForGroup(ctx, &checklyv1alpha1.Group{ObjectMeta: metav1.ObjectMeta{Name: "shared"}}),
+			[]string{"team-a/check-a", "team-b/check-b"},
+		},
+		{
+			"check group",
+			apiCheckReconciler.findApiChecksForGroup(ctx, &checklyv1alpha1.CheckGroup{ObjectMeta: metav1.ObjectMeta{Name: "shared", Namespace: "team-b"}}),
+			[]string{"team-b/check-b"},
+		},
+		{
+			"secret",
+			apiCheckReconciler.findApiChecksForSecret(ctx, &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "token", Namespace: "team-b"}}),
+			[]string{"team-b/check-c"},
+		},
+		{
+			"unused secret",
+			apiCheckReconciler.findApiChecksForSecret(ctx, &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "team-a"}}),
+			nil,
+		},
+		{
+			"browser checks",
+			(&BrowserCheckReconciler{Client: c}).findBrowserChecksForGroup(ctx, &checklyv1alpha1.Group{ObjectMeta: metav1.ObjectMeta{Name: "shared"}}),
+			[]string{"team-b/browser-b"},
+		},
+		{
+			"groups",
+			(&GroupReconciler{Client: c}).findGroupsForAlertChannel(ctx, &checklyv1alpha1.AlertChannel{ObjectMeta: metav1.ObjectMeta{Name: "slack"}}),
+			[]string{"/shared"},
+		},
+		{
+			"check groups",
+			(&CheckGroupReconciler{GroupReconciler{Client: c}}).findCheckGroupsForAlertChannel(ctx, &checklyv1alpha1.NamespacedAlertChannel{ObjectMeta: metav1.ObjectMeta{Name: "slack", Namespace: "team-b"}}),
+			[]string{"team-b/team-group"},
+		},
+		{
+			"check groups of another namespace",
+			(&CheckGroupReconciler{GroupReconciler{Client: c}}).findCheckGroupsForAlertChannel(ctx, &checklyv1alpha1.NamespacedAlertChannel{ObjectMeta: metav1.ObjectMeta{Name: "slack", Namespace: "team-a"}}),
+			nil,
+		},
+		{
+			"unused alert channel",
+			(&GroupReconciler{Client: c}).findGroupsForAlertChannel(ctx, &checklyv1alpha1.AlertChannel{ObjectMeta: metav1.ObjectMeta{Name: "pagerduty"}}),
+			nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		if got := names(tc.got); !reflect.DeepEqual(got, tc.expected) {
+			t.Errorf("%s: expected %v, got %v", tc.name, tc.expected, got)
+		}
+	}
+}
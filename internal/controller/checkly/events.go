@@ -0,0 +1,68 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package checkly
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Reasons of the events recorded on the checkly resources
+const (
+	eventReasonCreated              = "Created"
+	eventReasonUpdated              = "Updated"
+	eventReasonDeleted              = "Deleted"
+	eventReasonAdopted              = "Adopted"
+	eventReasonRecreated            = "Recreated"
+	eventReasonDriftCorrected       = "DriftCorrected"
+	eventReasonSecretRotated        = "SecretRotated"
+	eventReasonSyncFailed           = "SyncFailed"
+	eventReasonDeleteFailed         = "DeleteFailed"
+	eventReasonWaitingForDependency = "WaitingForDependency"
+	eventReasonDependencyNotAllowed = "DependencyNotAllowed"
+	eventReasonDeletionBlocked      = "DeletionBlocked"
+	eventReasonDependentDeleted     = "DependentDeleted"
+	eventReasonDependentReparented  = "DependentReparented"
+	eventReasonUnsubscribed         = "Unsubscribed"
+	eventReasonRetained             = "Retained"
+	eventReasonOrphaned             = "Orphaned"
+	eventReasonAbandoned            = "Abandoned"
+	eventReasonRelinked             = "Relinked"
+)
+
+// recordEvent records an event on the object and on its controller owner, ex. the Ingress an ApiCheck was generated from,
+// so app teams see what happened to their checks with kubectl describe
+func recordEvent(recorder record.EventRecorder, obj client.Object, eventType string, reason string, message string) {
+	recorder.Event(obj, eventType, reason, message)
+
+	for _, owner := range obj.GetOwnerReferences() {
+		if owner.Controller == nil || !*owner.Controller {
+			continue
+		}
+		ownerRef := &corev1.ObjectReference{
+			APIVersion: owner.APIVersion,
+			Kind:       owner.Kind,
+			Name:       owner.Name,
+			Namespace:  obj.GetNamespace(),
+			UID:        owner.UID,
+		}
+		recorder.Event(ownerRef, eventType, reason, fmt.Sprintf("%s: %s", obj.GetName(), message))
+	}
+}
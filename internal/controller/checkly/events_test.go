@@ -0,0 +1,66 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package checkly
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	checklyv1alpha1 "github.com/checkly/checkly-operator/api/checkly/v1alpha1"
+)
+
+func TestRecordEvent(t *testing.T) {
+	recorder := record.NewFakeRecorder(10)
+
+	apiCheck := &checklyv1alpha1.ApiCheck{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "foo",
+			Namespace: "bar",
+		},
+	}
+	recordEvent(recorder, apiCheck, corev1.EventTypeNormal, eventReasonCreated, "Created checkly check 2")
+
+	if len(recorder.Events) != 1 {
+		t.Fatalf("Expected %d event, got %d", 1, len(recorder.Events))
+	}
+	if event := <-recorder.Events; event != "Normal Created Created checkly check 2" {
+		t.Errorf("Unexpected event %s", event)
+	}
+
+	// Checks generated from an Ingress also record the event on the Ingress
+	controller := true
+	apiCheck.OwnerReferences = []metav1.OwnerReference{
+		{
+			APIVersion: "networking.k8s.io/v1",
+			Kind:       "Ingress",
+			Name:       "baz",
+			Controller: &controller,
+		},
+	}
+	recordEvent(recorder, apiCheck, corev1.EventTypeWarning, eventReasonSyncFailed, "Failed to sync with checklyhq.com: boom")
+
+	if len(recorder.Events) != 2 {
+		t.Fatalf("Expected %d events, got %d", 2, len(recorder.Events))
+	}
+	<-recorder.Events
+	if event := <-recorder.Events; event != "Warning SyncFailed foo: Failed to sync with checklyhq.com: boom" {
+		t.Errorf("Unexpected event %s", event)
+	}
+}
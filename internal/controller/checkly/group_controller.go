@@ -19,15 +19,21 @@ package checkly
 import (
 	"context"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/checkly/checkly-go-sdk"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	checklyv1alpha1 "github.com/checkly/checkly-operator/api/checkly/v1alpha1"
 	external "github.com/checkly/checkly-operator/external/checkly"
@@ -37,13 +43,28 @@ import (
 type GroupReconciler struct {
 	client.Client
 	Scheme           *runtime.Scheme
-	ApiClient        checkly.Client
+	ApiClient        external.Client
 	ControllerDomain string
+	Recorder         record.EventRecorder
+	// ResyncInterval is how often the resource is compared with checklyhq.com, zero disables the periodic resync
+	ResyncInterval time.Duration
+	// Accounts holds the clients of the ChecklyAccounts, ApiClient is used for resources which don't reference an account
+	Accounts *AccountClients
+	// DeletionPolicy is the default deletion policy of the resources which don't set one, empty deletes them
+	DeletionPolicy string
+	// ClusterID identifies the cluster in the ownership tags of the groups, empty disables the ownership tags
+	ClusterID string
 }
 
 //+kubebuilder:rbac:groups=k8s.checklyhq.com,resources=groups,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=k8s.checklyhq.com,resources=groups/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=k8s.checklyhq.com,resources=groups/finalizers,verbs=update
+//+kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+//+kubebuilder:rbac:groups=k8s.checklyhq.com,resources=checklyaccounts,verbs=get;list;watch
+//+kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch
+//+kubebuilder:rbac:groups=k8s.checklyhq.com,resources=alertchannels,verbs=get;list;watch
+//+kubebuilder:rbac:groups=k8s.checklyhq.com,resources=apichecks,verbs=get;list;watch;update;patch;delete
+//+kubebuilder:rbac:groups=k8s.checklyhq.com,resources=browserchecks,verbs=get;list;watch;update;patch;delete
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -55,13 +76,10 @@ func (r *GroupReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl
 
 	logger.V(1).Info("Reconciler started")
 
-	groupFinalizer := fmt.Sprintf("%s/finalizer", r.ControllerDomain)
-
 	group := &checklyv1alpha1.Group{}
 
 	// ////////////////////////////////
 	// Delete Logic
-	// TODO: Add logic to determine if there are any checks that are part of the group. If yes, throw error and do not delete the group until the checks have been deleted first.
 	// ///////////////////////////////
 	err := r.Get(ctx, req.NamespacedName, group)
 	if err != nil {
@@ -75,20 +93,78 @@ func (r *GroupReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl
 		return ctrl.Result{}, nil
 	}
 
+	return r.reconcileGroup(ctx, group, &group.Spec, &group.Status)
+}
+
+// reconcileGroup syncs a Group or a CheckGroup with checklyhq.com, obj is the resource itself, spec and status point into it
+func (r *GroupReconciler) reconcileGroup(ctx context.Context, obj client.Object, spec *checklyv1alpha1.GroupSpec, status *checklyv1alpha1.GroupStatus) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	groupFinalizer := fmt.Sprintf("%s/finalizer", r.ControllerDomain)
+
+	// An abandoned or never synced group doesn't need the client of its account, which may be gone already
+	if obj.GetDeletionTimestamp() != nil && controllerutil.ContainsFinalizer(obj, groupFinalizer) {
+		released, err := releaseWithoutRemoteDeletion(ctx, r.Client, r.Recorder, obj, groupFinalizer, r.ControllerDomain, status.ID != 0)
+		if released || err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	// /////////////////////////////
+	// Account logic
+	// ////////////////////////////
+	apiClient, err := accountAPIClient(ctx, r.Accounts, r.ApiClient, spec.Account)
+	if err != nil {
+		logger.Error(err, "Unable to get the checkly client of the account", "account", spec.Account)
+		setDependenciesNotResolved(&status.SyncStatus, obj.GetGeneration(), checklyv1alpha1.ReasonDependencyNotFound, err.Error())
+		recordEvent(r.Recorder, obj, corev1.EventTypeWarning, eventReasonWaitingForDependency, err.Error())
+		updateStatus(ctx, r.Client, obj)
+		return ctrl.Result{}, err
+	}
+
 	// If DeletionTimestamp is present, the object is marked for deletion, we need to remove the finalizer
-	if group.GetDeletionTimestamp() != nil {
-		if controllerutil.ContainsFinalizer(group, groupFinalizer) {
-			logger.V(1).Info("Finalizer is present, trying to delete Checkly group", "checkly group ID", group.Status.ID)
-			err := external.GroupDelete(group.Status.ID, r.ApiClient)
-			if err != nil {
-				logger.Error(err, "Failed to delete checkly group")
-				return ctrl.Result{}, err
-			}
+	if obj.GetDeletionTimestamp() != nil {
+		if controllerutil.ContainsFinalizer(obj, groupFinalizer) {
+			switch effectiveDeletionPolicy(spec.DeletionPolicy, r.DeletionPolicy) {
+			case checklyv1alpha1.DeletionPolicyRetain:
+				err = external.TagGroup(ctx, status.ID, retainedTag(r.ControllerDomain, obj), apiClient)
+				if err != nil {
+					logger.Error(err, "Failed to tag retained checkly group")
+					recordEvent(r.Recorder, obj, corev1.EventTypeWarning, eventReasonDeleteFailed, fmt.Sprintf("Failed to tag retained checkly group %d: %s", status.ID, err))
+					return syncFailedResult(err)
+				}
+				logger.Info("Retained checkly group", "checkly group ID", status.ID)
+				recordEvent(r.Recorder, obj, corev1.EventTypeNormal, eventReasonRetained, fmt.Sprintf("Retained checkly group %d, it can be adopted again with the %s annotation", status.ID, adoptIDAnnotation(r.ControllerDomain)))
+
+			case checklyv1alpha1.DeletionPolicyOrphan:
+				logger.Info("Orphaned checkly group", "checkly group ID", status.ID)
+				recordEvent(r.Recorder, obj, corev1.EventTypeNormal, eventReasonOrphaned, fmt.Sprintf("Left checkly group %d in checklyhq.com", status.ID))
+
+			default:
+				// The checks of the group have to be deleted or moved first, checklyhq.com deletes them together with the group
+				dependents, err := groupDependents(ctx, r.Client, obj, status, spec.Account)
+				if err != nil {
+					logger.Error(err, "Failed to list the checks of the group")
+					return ctrl.Result{}, err
+				}
+				if len(dependents) > 0 {
+					return r.handleGroupDependents(ctx, obj, spec, status, dependents)
+				}
+
+				logger.V(1).Info("Finalizer is present, trying to delete Checkly group", "checkly group ID", status.ID)
+				err = external.GroupDelete(ctx, status.ID, apiClient)
+				if err != nil {
+					logger.Error(err, "Failed to delete checkly group")
+					recordEvent(r.Recorder, obj, corev1.EventTypeWarning, eventReasonDeleteFailed, fmt.Sprintf("Failed to delete checkly group %d: %s", status.ID, err))
+					return syncFailedResult(err)
+				}
 
-			logger.Info("Successfully deleted checkly group", "checkly group ID", group.Status.ID)
+				logger.Info("Successfully deleted checkly group", "checkly group ID", status.ID)
+				recordEvent(r.Recorder, obj, corev1.EventTypeNormal, eventReasonDeleted, fmt.Sprintf("Deleted checkly group %d", status.ID))
+			}
 
-			controllerutil.RemoveFinalizer(group, groupFinalizer)
-			err = r.Update(ctx, group)
+			controllerutil.RemoveFinalizer(obj, groupFinalizer)
+			err = r.Update(ctx, obj)
 			if err != nil {
 				logger.Error(err, "Failed to delete finalizer")
 				return ctrl.Result{}, err
@@ -104,15 +180,15 @@ func (r *GroupReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl
 	// /////////////////////////////
 	// Finalizer logic
 	// ////////////////////////////
-	if !controllerutil.ContainsFinalizer(group, groupFinalizer) {
-		controllerutil.AddFinalizer(group, groupFinalizer)
-		err = r.Update(ctx, group)
+	if !controllerutil.ContainsFinalizer(obj, groupFinalizer) {
+		controllerutil.AddFinalizer(obj, groupFinalizer)
+		err := r.Update(ctx, obj)
 		if err != nil {
 			logger.Error(err, "Failed to add Group finalizer")
 			return ctrl.Result{}, err
 		}
-		logger.V(1).Info("Added finalizer", "checkly group ID", group.Status.ID)
-		return ctrl.Result{}, nil
+		logger.V(1).Info("Added finalizer", "checkly group ID", status.ID)
+		return ctrl.Result{Requeue: true}, nil
 	}
 
 	// /////////////////////////////
@@ -120,34 +196,100 @@ func (r *GroupReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl
 	// ////////////////////////////
 	var alertChannels []checkly.AlertChannelSubscription
 
-	if len(group.Spec.AlertChannels) != 0 {
-		for _, alertChannel := range group.Spec.AlertChannels {
-			ac := &checklyv1alpha1.AlertChannel{}
-			err := r.Get(ctx, types.NamespacedName{Name: alertChannel}, ac)
+	if len(spec.AlertChannels) != 0 {
+		for _, alertChannel := range spec.AlertChannels {
+			alertChannelID, alertChannelAccount, err := r.alertChannelID(ctx, obj.GetNamespace(), alertChannel)
 			if err != nil {
 				logger.Error(err, "Could not find alertChannel resource", "name", alertChannel)
+				setDependenciesNotResolved(&status.SyncStatus, obj.GetGeneration(), checklyv1alpha1.ReasonDependencyNotFound, fmt.Sprintf("AlertChannel %s not found", alertChannel))
+				recordEvent(r.Recorder, obj, corev1.EventTypeWarning, eventReasonWaitingForDependency, fmt.Sprintf("AlertChannel %s not found", alertChannel))
+				updateStatus(ctx, r.Client, obj)
 				return ctrl.Result{}, err
 			}
-			if ac.Status.ID == 0 {
-				logger.Info("AlertChannel ID not yet populated, we'll retry")
-				return ctrl.Result{Requeue: true}, nil
+			if alertChannelAccount != spec.Account {
+				err = fmt.Errorf("AlertChannel %s belongs to %s, the group to %s", alertChannel, accountDisplayName(alertChannelAccount), accountDisplayName(spec.Account))
+				logger.Error(err, "AlertChannel not allowed")
+				setDependenciesNotResolved(&status.SyncStatus, obj.GetGeneration(), checklyv1alpha1.ReasonDependencyNotAllowed, err.Error())
+				recordEvent(r.Recorder, obj, corev1.EventTypeWarning, eventReasonDependencyNotAllowed, err.Error())
+				updateStatus(ctx, r.Client, obj)
+				return ctrl.Result{}, err
+			}
+			if alertChannelID == 0 {
+				logger.Info("AlertChannel ID not yet populated, waiting for the alert channel to be synced")
+				setDependenciesNotResolved(&status.SyncStatus, obj.GetGeneration(), checklyv1alpha1.ReasonDependencyNotReady, fmt.Sprintf("AlertChannel %s is not synced yet", alertChannel))
+				recordEvent(r.Recorder, obj, corev1.EventTypeWarning, eventReasonWaitingForDependency, fmt.Sprintf("AlertChannel %s is not synced yet", alertChannel))
+				updateStatus(ctx, r.Client, obj)
+				// The alert channel is watched, the group is reconciled again once the alert channel ID is set
+				return ctrl.Result{}, nil
 			}
 			alertChannels = append(alertChannels, checkly.AlertChannelSubscription{
-				ChannelID: ac.Status.ID,
+				ChannelID: alertChannelID,
 				Activated: true,
 			})
 		}
 	}
 
+	alertSettings, useGlobalAlertSettings := checklyAlertSettings(spec.AlertSettings)
+
 	// Create internal Check type
 	internalCheck := external.Group{
-		Name:             group.Name,
-		Activated:        group.Spec.Activated,
-		Locations:        group.Spec.Locations,
-		PrivateLocations: group.Spec.PrivateLocations,
-		AlertChannels:    alertChannels,
-		ID:               group.Status.ID,
-		Labels:           group.Labels,
+		Name:                   obj.GetName(),
+		Activated:              spec.Activated,
+		Locations:              spec.Locations,
+		PrivateLocations:       spec.PrivateLocations,
+		AlertChannels:          alertChannels,
+		ID:                     status.ID,
+		Labels:                 obj.GetLabels(),
+		AlertSettings:          alertSettings,
+		UseGlobalAlertSettings: useGlobalAlertSettings,
+		OwnerTag:               ownerTag(r.ControllerDomain, r.ClusterID, obj),
+	}
+
+	// /////////////////////////////
+	// Adoption logic
+	// ////////////////////////////
+	if status.ID == 0 {
+		adoptID, ok, err := getAdoptGroupID(obj, r.ControllerDomain)
+		if ok {
+			var found bool
+			if err == nil {
+				found, err = external.GroupExists(ctx, adoptID, apiClient)
+			}
+			if err == nil && !found {
+				err = fmt.Errorf("group %d not found in checklyhq.com", adoptID)
+			}
+			if err != nil {
+				logger.Error(err, "Failed to adopt existing checkly group", "checkly group ID", adoptID)
+				setSyncFailed(&status.SyncStatus, obj.GetGeneration(), err)
+				recordEvent(r.Recorder, obj, corev1.EventTypeWarning, eventReasonSyncFailed, fmt.Sprintf("Failed to sync with checklyhq.com: %s", err))
+				updateStatus(ctx, r.Client, obj)
+				return syncFailedResult(err)
+			}
+			logger.Info("Adopting existing checkly group", "checkly group ID", adoptID)
+			recordEvent(r.Recorder, obj, corev1.EventTypeNormal, eventReasonAdopted, fmt.Sprintf("Adopted existing checkly group %d", adoptID))
+			status.ID = adoptID
+			internalCheck.ID = adoptID
+		}
+	}
+
+	// /////////////////////////////
+	// Ownership logic
+	// ////////////////////////////
+	if status.ID == 0 && internalCheck.OwnerTag != "" {
+		ownedID, err := external.FindGroup(ctx, internalCheck.OwnerTag, apiClient)
+		if err != nil {
+			logger.Error(err, "Failed to look up the checkly group by its ownership tag")
+			setSyncFailed(&status.SyncStatus, obj.GetGeneration(), err)
+			recordEvent(r.Recorder, obj, corev1.EventTypeWarning, eventReasonSyncFailed, fmt.Sprintf("Failed to sync with checklyhq.com: %s", err))
+			updateStatus(ctx, r.Client, obj)
+			return syncFailedResult(err)
+		}
+		if ownedID != 0 {
+			logger.Info("Relinking checkly group found by its ownership tag", "checkly group ID", ownedID)
+			recordEvent(r.Recorder, obj, corev1.EventTypeNormal, eventReasonRelinked, fmt.Sprintf("Relinked existing checkly group %d found by its ownership tag", ownedID))
+			status.ID = ownedID
+			internalCheck.ID = ownedID
+		}
 	}
 
 	// /////////////////////////////
@@ -155,42 +297,149 @@ func (r *GroupReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl
 	// ////////////////////////////
 
 	// Determine if it's a new object or if it's an update to an existing object
-	if group.Status.ID != 0 {
+	if status.ID != 0 {
 		// Existing object, we need to update it
-		logger.V(1).Info("Existing object, with ID", "checkly group ID", group.Status.ID)
-		err := external.GroupUpdate(internalCheck, r.ApiClient)
+		logger.V(1).Info("Existing object, with ID", "checkly group ID", status.ID)
+		drift, found, err := external.GroupDrift(ctx, internalCheck, apiClient)
 		if err != nil {
-			logger.Error(err, "Failed to update the checkly group")
-			return ctrl.Result{}, err
+			logger.Error(err, "Failed to get the checkly group")
+			setSyncFailed(&status.SyncStatus, obj.GetGeneration(), err)
+			recordEvent(r.Recorder, obj, corev1.EventTypeWarning, eventReasonSyncFailed, fmt.Sprintf("Failed to sync with checklyhq.com: %s", err))
+			updateStatus(ctx, r.Client, obj)
+			return syncFailedResult(err)
 		}
-		logger.V(1).Info("Updated checkly check", "checkly group ID", group.Status.ID)
-		return ctrl.Result{}, nil
+
+		if found {
+			if len(drift) != 0 {
+				logger.Info("Checkly group changed outside of the operator, overwriting", "checkly group ID", status.ID, "fields", drift)
+				recordEvent(r.Recorder, obj, corev1.EventTypeWarning, eventReasonDriftCorrected, fmt.Sprintf("Checkly group %d was changed outside of the operator, reverting: %s", status.ID, strings.Join(drift, ", ")))
+			}
+			// A resync of an unchanged group only compares it, updating it anyway would spend the rate limit of the account
+			if len(drift) != 0 || !isSynced(&status.SyncStatus, obj.GetGeneration()) {
+				err = external.GroupUpdate(ctx, internalCheck, apiClient)
+				if err != nil {
+					logger.Error(err, "Failed to update the checkly group")
+					setSyncFailed(&status.SyncStatus, obj.GetGeneration(), err)
+					recordEvent(r.Recorder, obj, corev1.EventTypeWarning, eventReasonSyncFailed, fmt.Sprintf("Failed to sync with checklyhq.com: %s", err))
+					updateStatus(ctx, r.Client, obj)
+					return syncFailedResult(err)
+				}
+				logger.V(1).Info("Updated checkly check", "checkly group ID", status.ID)
+				if !isSynced(&status.SyncStatus, obj.GetGeneration()) {
+					recordEvent(r.Recorder, obj, corev1.EventTypeNormal, eventReasonUpdated, fmt.Sprintf("Updated checkly group %d", status.ID))
+				}
+			}
+
+			setDrift(&status.SyncStatus, obj.GetGeneration(), drift)
+			setSynced(&status.SyncStatus, obj.GetGeneration())
+			err = r.Status().Update(ctx, obj)
+			if err != nil {
+				logger.Error(err, "Failed to update group status", "ID", status.ID)
+				return ctrl.Result{}, err
+			}
+			return ctrl.Result{RequeueAfter: r.ResyncInterval}, nil
+		}
+
+		// The group was deleted outside of the operator, we create it again, the checks pick up the new ID on their next reconcile
+		logger.Info("Checkly group not found, recreating", "checkly group ID", status.ID)
+		recordEvent(r.Recorder, obj, corev1.EventTypeWarning, eventReasonRecreated, fmt.Sprintf("Checkly group %d was deleted outside of the operator, recreating", status.ID))
+		setRecreated(&status.SyncStatus, obj.GetGeneration())
 	}
 
 	// /////////////////////////////
 	// Create logic
 	// ////////////////////////////
-	checklyID, err := external.GroupCreate(internalCheck, r.ApiClient)
+	checklyID, err := external.GroupCreate(ctx, internalCheck, apiClient)
 	if err != nil {
 		logger.Error(err, "Failed to create checkly group")
-		return ctrl.Result{}, err
+		setSyncFailed(&status.SyncStatus, obj.GetGeneration(), err)
+		recordEvent(r.Recorder, obj, corev1.EventTypeWarning, eventReasonSyncFailed, fmt.Sprintf("Failed to sync with checklyhq.com: %s", err))
+		updateStatus(ctx, r.Client, obj)
+		return syncFailedResult(err)
 	}
 
 	// Update the custom resource Status with the returned ID
-	group.Status.ID = checklyID
-	err = r.Status().Update(ctx, group)
+	status.ID = checklyID
+	setSynced(&status.SyncStatus, obj.GetGeneration())
+	err = r.Status().Update(ctx, obj)
 	if err != nil {
-		logger.Error(err, "Failed to update group status", "ID", group.Status.ID)
+		logger.Error(err, "Failed to update group status", "ID", status.ID)
 		return ctrl.Result{}, err
 	}
-	logger.Info("New checkly group created", "ID", group.Status.ID)
+	logger.Info("New checkly group created", "ID", status.ID)
+	recordEvent(r.Recorder, obj, corev1.EventTypeNormal, eventReasonCreated, fmt.Sprintf("Created checkly group %d", status.ID))
 
-	return ctrl.Result{}, nil
+	return ctrl.Result{RequeueAfter: r.ResyncInterval}, nil
+}
+
+// alertChannelID returns the checklyhq.com ID of the referenced alert channel, zero if it's not synced yet, and the account it belongs to.
+// A CheckGroup, which has a namespace, prefers a NamespacedAlertChannel in its own namespace over a cluster scoped AlertChannel.
+func (r *GroupReconciler) alertChannelID(ctx context.Context, namespace string, name string) (ID int64, account string, err error) {
+	if namespace != "" {
+		namespacedAlertChannel := &checklyv1alpha1.NamespacedAlertChannel{}
+		err = r.Get(ctx, types.NamespacedName{Name: name, Namespace: namespace}, namespacedAlertChannel)
+		if err == nil {
+			ID = namespacedAlertChannel.Status.ID
+			account = namespacedAlertChannel.Spec.Account
+			return
+		}
+		if !errors.IsNotFound(err) {
+			return
+		}
+	}
+
+	alertChannel := &checklyv1alpha1.AlertChannel{}
+	err = r.Get(ctx, types.NamespacedName{Name: name}, alertChannel)
+	if err != nil {
+		return
+	}
+	ID = alertChannel.Status.ID
+	account = alertChannel.Spec.Account
+
+	return
+}
+
+// findGroupsForAlertChannel maps an AlertChannel to the Groups subscribed to it
+func (r *GroupReconciler) findGroupsForAlertChannel(ctx context.Context, alertChannel client.Object) []reconcile.Request {
+	groups := &checklyv1alpha1.GroupList{}
+	err := r.List(ctx, groups,
+		client.MatchingFields{groupAlertChannelIndex: alertChannel.GetName()})
+	if err != nil {
+		return []reconcile.Request{}
+	}
+
+	requests := make([]reconcile.Request, len(groups.Items))
+	for i, item := range groups.Items {
+		requests[i] = reconcile.Request{
+			NamespacedName: types.NamespacedName{
+				Name: item.Name,
+			},
+		}
+	}
+	return requests
 }
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *GroupReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	err := mgr.GetFieldIndexer().IndexField(context.Background(), &checklyv1alpha1.Group{}, groupAlertChannelIndex, groupAlertChannelIndexValues)
+	if err != nil {
+		return err
+	}
+
 	return ctrl.NewControllerManagedBy(mgr).
-		For(&checklyv1alpha1.Group{}).
+		For(&checklyv1alpha1.Group{}, specChanged).
+		Watches(
+			&checklyv1alpha1.AlertChannel{},
+			handler.EnqueueRequestsFromMapFunc(r.findGroupsForAlertChannel),
+			dependencyChanged,
+		).
+		Watches(
+			&checklyv1alpha1.ApiCheck{},
+			handler.EnqueueRequestsFromMapFunc(r.findDeletingGroupsForCheck),
+		).
+		Watches(
+			&checklyv1alpha1.BrowserCheck{},
+			handler.EnqueueRequestsFromMapFunc(r.findDeletingGroupsForCheck),
+		).
 		Complete(r)
 }
@@ -22,6 +22,7 @@ import (
 	"github.com/checkly/checkly-go-sdk"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 
@@ -109,6 +110,17 @@ var _ = Describe("ApiCheck Controller", func() {
 				}
 			}, timeout, interval).Should(BeTrue())
 
+			// Ready condition should be set
+			By("Expecting Ready condition")
+			Eventually(func() bool {
+				f := &checklyv1alpha1.Group{}
+				err := k8sClient.Get(context.Background(), groupKey, f)
+				if err != nil {
+					return false
+				}
+				return meta.IsStatusConditionTrue(f.Status.Conditions, checklyv1alpha1.ConditionReady) && f.Status.ObservedGeneration == f.Generation
+			}, timeout, interval).Should(BeTrue())
+
 			// Finalizer should be present
 			By("Expecting finalizer")
 			Eventually(func() bool {
@@ -0,0 +1,102 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package checkly
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	checklyv1alpha1 "github.com/checkly/checkly-operator/api/checkly/v1alpha1"
+)
+
+// ambiguousGroupError is returned by getGroup when a check without a group kind references a name used by a CheckGroup
+// in its namespace and by a cluster scoped Group
+type ambiguousGroupError struct {
+	name      string
+	namespace string
+}
+
+func (e *ambiguousGroupError) Error() string {
+	return fmt.Sprintf("group %s matches the CheckGroup in namespace %s and the cluster scoped Group, set groupKind to %s or %s",
+		e.name, e.namespace, checklyv1alpha1.GroupKindCheckGroup, checklyv1alpha1.GroupKindGroup)
+}
+
+// getGroup looks up the group a check references, kind selects a CheckGroup in the namespace of the check or a cluster scoped Group.
+// Without a kind both are looked up and an *ambiguousGroupError is returned if both exist, so creating a CheckGroup never moves
+// a check away from its Group. A CheckGroup is returned as a Group. Allowed is false if the Group does not accept checks from
+// the namespace of the check.
+func getGroup(ctx context.Context, c client.Reader, namespace string, name string, kind string) (group *checklyv1alpha1.Group, allowed bool, err error) {
+	switch kind {
+	case checklyv1alpha1.GroupKindCheckGroup:
+		return getCheckGroup(ctx, c, namespace, name)
+	case checklyv1alpha1.GroupKindGroup:
+		return getClusterGroup(ctx, c, namespace, name)
+	}
+
+	group, allowed, err = getCheckGroup(ctx, c, namespace, name)
+	if err != nil {
+		if !errors.IsNotFound(err) {
+			return
+		}
+		return getClusterGroup(ctx, c, namespace, name)
+	}
+
+	err = c.Get(ctx, types.NamespacedName{Name: name}, &checklyv1alpha1.Group{})
+	switch {
+	case err == nil:
+		return nil, false, &ambiguousGroupError{name: name, namespace: namespace}
+	case errors.IsNotFound(err):
+		return group, allowed, nil
+	}
+
+	return nil, false, err
+}
+
+// getCheckGroup looks up a CheckGroup in the namespace of the check and returns it as a Group
+func getCheckGroup(ctx context.Context, c client.Reader, namespace string, name string) (group *checklyv1alpha1.Group, allowed bool, err error) {
+	checkGroup := &checklyv1alpha1.CheckGroup{}
+	err = c.Get(ctx, types.NamespacedName{Name: name, Namespace: namespace}, checkGroup)
+	if err != nil {
+		return
+	}
+
+	group = &checklyv1alpha1.Group{
+		ObjectMeta: checkGroup.ObjectMeta,
+		Spec:       checkGroup.Spec,
+		Status:     checkGroup.Status,
+	}
+	allowed = true
+
+	return
+}
+
+// getClusterGroup looks up a cluster scoped Group, allowed is false if it does not accept checks from the namespace
+func getClusterGroup(ctx context.Context, c client.Reader, namespace string, name string) (group *checklyv1alpha1.Group, allowed bool, err error) {
+	group = &checklyv1alpha1.Group{}
+	err = c.Get(ctx, types.NamespacedName{Name: name}, group)
+	if err != nil {
+		return
+	}
+	allowed = len(group.Spec.AllowedNamespaces) == 0 || slices.Contains(group.Spec.AllowedNamespaces, namespace)
+
+	return
+}
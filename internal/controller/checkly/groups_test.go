@@ -0,0 +1,96 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package checkly
+
+import (
+	"context"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	checklyv1alpha1 "github.com/checkly/checkly-operator/api/checkly/v1alpha1"
+)
+
+func TestGetGroup(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := checklyv1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+
+	objects := []runtime.Object{
+		&checklyv1alpha1.Group{
+			ObjectMeta: metav1.ObjectMeta{Name: "shared"},
+			Status:     checklyv1alpha1.GroupStatus{ID: 1},
+		},
+		&checklyv1alpha1.Group{
+			ObjectMeta: metav1.ObjectMeta{Name: "restricted"},
+			Spec:       checklyv1alpha1.GroupSpec{AllowedNamespaces: []string{"team-a"}},
+			Status:     checklyv1alpha1.GroupStatus{ID: 2},
+		},
+		&checklyv1alpha1.CheckGroup{
+			ObjectMeta: metav1.ObjectMeta{Name: "shared", Namespace: "team-b"},
+			Status:     checklyv1alpha1.GroupStatus{ID: 3},
+		},
+		&checklyv1alpha1.CheckGroup{
+			ObjectMeta: metav1.ObjectMeta{Name: "local", Namespace: "team-b"},
+			Status:     checklyv1alpha1.GroupStatus{ID: 4},
+		},
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(objects...).Build()
+
+	testCases := []struct {
+		name      string
+		namespace string
+		group     string
+		kind      string
+		ID        int64
+		allowed   bool
+		err       bool
+	}{
+		{"cluster group", "team-a", "shared", "", 1, true, false},
+		{"check group", "team-b", "local", "", 4, true, false},
+		{"ambiguous name", "team-b", "shared", "", 0, false, true},
+		{"explicit check group", "team-b", "shared", checklyv1alpha1.GroupKindCheckGroup, 3, true, false},
+		{"explicit cluster group", "team-b", "shared", checklyv1alpha1.GroupKindGroup, 1, true, false},
+		{"missing check group", "team-a", "shared", checklyv1alpha1.GroupKindCheckGroup, 0, false, true},
+		{"allowed namespace", "team-a", "restricted", "", 2, true, false},
+		{"not allowed namespace", "team-b", "restricted", "", 2, false, false},
+		{"missing group", "team-a", "missing", "", 0, false, true},
+	}
+
+	for _, tc := range testCases {
+		group, allowed, err := getGroup(context.Background(), c, tc.namespace, tc.group, tc.kind)
+		if tc.err {
+			if err == nil {
+				t.Errorf("%s: expected error, got none", tc.name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: expected no error, got %s", tc.name, err)
+			continue
+		}
+		if group.Status.ID != tc.ID {
+			t.Errorf("%s: expected group %d, got %d", tc.name, tc.ID, group.Status.ID)
+		}
+		if allowed != tc.allowed {
+			t.Errorf("%s: expected allowed %t, got %t", tc.name, tc.allowed, allowed)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package checkly
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	checklyv1alpha1 "github.com/checkly/checkly-operator/api/checkly/v1alpha1"
+)
+
+// NamespacedAlertChannelReconciler reconciles a NamespacedAlertChannel object, it shares the reconcile logic of the AlertChannelReconciler
+type NamespacedAlertChannelReconciler struct {
+	AlertChannelReconciler
+}
+
+//+kubebuilder:rbac:groups=k8s.checklyhq.com,resources=namespacedalertchannels,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=k8s.checklyhq.com,resources=namespacedalertchannels/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=k8s.checklyhq.com,resources=namespacedalertchannels/finalizers,verbs=update
+//+kubebuilder:rbac:groups=k8s.checklyhq.com,resources=checkgroups,verbs=get;list;watch;update;patch
+
+// Reconcile is part of the main kubernetes reconciliation loop which aims to
+// move the current state of the cluster closer to the desired state.
+func (r *NamespacedAlertChannelReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	logger.V(1).Info("Reconciler started")
+
+	ac := &checklyv1alpha1.NamespacedAlertChannel{}
+
+	err := r.Get(ctx, req.NamespacedName, ac)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			// The resource has been deleted
+			logger.V(1).Info("Deleted", "checkly AlertChannel ID", ac.Status.ID)
+			return ctrl.Result{}, nil
+		}
+		// Error reading the object
+		logger.Error(err, "can't read the object")
+		return ctrl.Result{}, nil
+	}
+
+	return r.reconcileAlertChannel(ctx, ac, &ac.Spec, &ac.Status)
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *NamespacedAlertChannelReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	err := mgr.GetFieldIndexer().IndexField(context.Background(), &checklyv1alpha1.NamespacedAlertChannel{}, alertChannelSecretIndex, func(rawObj client.Object) []string {
+		ac := rawObj.(*checklyv1alpha1.NamespacedAlertChannel)
+		return alertChannelSecretIndexValues(&checklyv1alpha1.AlertChannel{ObjectMeta: ac.ObjectMeta, Spec: ac.Spec})
+	})
+	if err != nil {
+		return err
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&checklyv1alpha1.NamespacedAlertChannel{}, specChanged).
+		Watches(
+			&corev1.Secret{},
+			handler.EnqueueRequestsFromMapFunc(r.findNamespacedAlertChannelsForSecret),
+		).
+		Watches(
+			&checklyv1alpha1.CheckGroup{},
+			handler.EnqueueRequestsFromMapFunc(r.findDeletingNamespacedAlertChannelsForGroup),
+		).
+		Complete(r)
+}
@@ -0,0 +1,70 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package checkly
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	checklyv1alpha1 "github.com/checkly/checkly-operator/api/checkly/v1alpha1"
+)
+
+//+kubebuilder:rbac:groups="",resources=namespaces,verbs=get
+
+// ClusterID returns the UID of the kube-system namespace, it identifies the cluster in the ownership tags and
+// doesn't change for the lifetime of the cluster
+func ClusterID(ctx context.Context, reader client.Reader) (string, error) {
+	namespace := &corev1.Namespace{}
+	err := reader.Get(ctx, types.NamespacedName{Name: metav1.NamespaceSystem}, namespace)
+	if err != nil {
+		return "", err
+	}
+	return string(namespace.UID), nil
+}
+
+// ownerTag returns the checklyhq.com tag which ties a check or group to its resource, it's used to find the check or
+// group again when the status of the resource is lost, ex. after a restore from a backup. Empty if clusterID is empty.
+func ownerTag(controllerDomain string, clusterID string, obj client.Object) string {
+	if clusterID == "" {
+		return ""
+	}
+
+	var kind string
+	switch obj.(type) {
+	case *checklyv1alpha1.ApiCheck:
+		kind = "apicheck"
+	case *checklyv1alpha1.BrowserCheck:
+		kind = "browsercheck"
+	case *checklyv1alpha1.Group:
+		kind = "group"
+	case *checklyv1alpha1.CheckGroup:
+		kind = "checkgroup"
+	default:
+		return ""
+	}
+
+	name := obj.GetName()
+	if obj.GetNamespace() != "" {
+		name = fmt.Sprintf("%s/%s", obj.GetNamespace(), name)
+	}
+	return fmt.Sprintf("%s/owner:%s/%s/%s", controllerDomain, clusterID, kind, name)
+}
@@ -0,0 +1,132 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package checkly
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/checkly/checkly-go-sdk"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	checklyv1alpha1 "github.com/checkly/checkly-operator/api/checkly/v1alpha1"
+	fakechecklyclient "github.com/checkly/checkly-operator/external/checkly/fake"
+)
+
+func TestOwnerTag(t *testing.T) {
+	testCases := []struct {
+		obj       client.Object
+		clusterID string
+		want      string
+	}{
+		{&checklyv1alpha1.ApiCheck{ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "team-a"}}, "1234", "testing.domain.tld/owner:1234/apicheck/team-a/foo"},
+		{&checklyv1alpha1.BrowserCheck{ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "team-a"}}, "1234", "testing.domain.tld/owner:1234/browsercheck/team-a/foo"},
+		{&checklyv1alpha1.Group{ObjectMeta: metav1.ObjectMeta{Name: "foo"}}, "1234", "testing.domain.tld/owner:1234/group/foo"},
+		{&checklyv1alpha1.CheckGroup{ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "team-a"}}, "1234", "testing.domain.tld/owner:1234/checkgroup/team-a/foo"},
+		{&checklyv1alpha1.ApiCheck{ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "team-a"}}, "", ""},
+		{&checklyv1alpha1.AlertChannel{ObjectMeta: metav1.ObjectMeta{Name: "foo"}}, "1234", ""},
+	}
+
+	for _, tc := range testCases {
+		if got := ownerTag("testing.domain.tld", tc.clusterID, tc.obj); got != tc.want {
+			t.Errorf("Expected %s, got %s", tc.want, got)
+		}
+	}
+}
+
+func TestClusterID(t *testing.T) {
+	c := fake.NewClientBuilder().WithObjects(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "kube-system", UID: "1234"}}).Build()
+	clusterID, err := ClusterID(context.Background(), c)
+	if err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+	if clusterID != "1234" {
+		t.Errorf("Expected %s, got %s", "1234", clusterID)
+	}
+}
+
+func TestOwnershipRelink(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := checklyv1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	apiClient := fakechecklyclient.NewClient()
+
+	// The resources were restored from a backup without their status
+	groupID := apiClient.SetGroup(checkly.Group{Name: "shared", Tags: []string{"checkly-operator", "testing.domain.tld/owner:1234/group/shared"}})
+	checkID := apiClient.SetCheck(checkly.Check{Name: "foo", Tags: []string{"checkly-operator", "team-a", "testing.domain.tld/owner:1234/apicheck/team-a/foo"}})
+	group := &checklyv1alpha1.Group{
+		ObjectMeta: metav1.ObjectMeta{Name: "shared", Finalizers: []string{testFinalizer}},
+	}
+	apiCheck := &checklyv1alpha1.ApiCheck{
+		ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "team-a", Finalizers: []string{testFinalizer}},
+		Spec:       checklyv1alpha1.ApiCheckSpec{Group: "shared", Endpoint: "https://foo.example.com", Success: "200"},
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(group, apiCheck).
+		WithStatusSubresource(&checklyv1alpha1.Group{}, &checklyv1alpha1.ApiCheck{}).Build()
+
+	groupReconciler := &GroupReconciler{
+		Client:           c,
+		Scheme:           scheme,
+		ApiClient:        apiClient,
+		ControllerDomain: "testing.domain.tld",
+		Recorder:         record.NewFakeRecorder(10),
+		ClusterID:        "1234",
+	}
+	if _, err := groupReconciler.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: "shared"}}); err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+	apiCheckReconciler := &ApiCheckReconciler{
+		Client:           c,
+		Scheme:           scheme,
+		ApiClient:        apiClient,
+		ControllerDomain: "testing.domain.tld",
+		Recorder:         record.NewFakeRecorder(10),
+		ClusterID:        "1234",
+	}
+	if _, err := apiCheckReconciler.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: "foo", Namespace: "team-a"}}); err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+
+	if count := apiClient.CallCount(fakechecklyclient.MethodCreateGroup) + apiClient.CallCount(fakechecklyclient.MethodCreate); count != 0 {
+		t.Errorf("Expected no create, got %d", count)
+	}
+	if err := c.Get(ctx, types.NamespacedName{Name: "shared"}, group); err != nil {
+		t.Fatal(err)
+	}
+	if group.Status.ID != groupID {
+		t.Errorf("Expected %d, got %d", groupID, group.Status.ID)
+	}
+	if err := c.Get(ctx, types.NamespacedName{Name: "foo", Namespace: "team-a"}, apiCheck); err != nil {
+		t.Fatal(err)
+	}
+	if apiCheck.Status.ID != checkID {
+		t.Errorf("Expected %s, got %s", checkID, apiCheck.Status.ID)
+	}
+	if tags := apiClient.Checks()[checkID].Tags; !slices.Contains(tags, "testing.domain.tld/owner:1234/apicheck/team-a/foo") {
+		t.Errorf("Expected the ownership tag to be kept, got %v", tags)
+	}
+}
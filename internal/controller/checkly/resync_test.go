@@ -0,0 +1,114 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package checkly
+
+import (
+	"context"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	checklyv1alpha1 "github.com/checkly/checkly-operator/api/checkly/v1alpha1"
+	fakechecklyclient "github.com/checkly/checkly-operator/external/checkly/fake"
+)
+
+func TestResyncWithoutChanges(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := checklyv1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	apiClient := fakechecklyclient.NewClient()
+
+	group := &checklyv1alpha1.Group{
+		ObjectMeta: metav1.ObjectMeta{Name: "shared", Generation: 1, Finalizers: []string{testFinalizer}},
+	}
+	apiCheck := &checklyv1alpha1.ApiCheck{
+		ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "team-a", Generation: 1, Finalizers: []string{testFinalizer}},
+		Spec:       checklyv1alpha1.ApiCheckSpec{Group: "shared", Endpoint: "https://foo.example.com", Success: "200"},
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(group, apiCheck).
+		WithStatusSubresource(&checklyv1alpha1.Group{}, &checklyv1alpha1.ApiCheck{}).Build()
+
+	groupReconciler := &GroupReconciler{
+		Client:           c,
+		Scheme:           scheme,
+		ApiClient:        apiClient,
+		ControllerDomain: "testing.domain.tld",
+		Recorder:         record.NewFakeRecorder(10),
+	}
+	apiCheckReconciler := &ApiCheckReconciler{
+		Client:           c,
+		Scheme:           scheme,
+		ApiClient:        apiClient,
+		ControllerDomain: "testing.domain.tld",
+		Recorder:         record.NewFakeRecorder(10),
+	}
+	groupRequest := ctrl.Request{NamespacedName: types.NamespacedName{Name: "shared"}}
+	apiCheckRequest := ctrl.Request{NamespacedName: types.NamespacedName{Name: "foo", Namespace: "team-a"}}
+
+	// Create, then resync twice without changes on either side
+	for range 3 {
+		if _, err := groupReconciler.Reconcile(ctx, groupRequest); err != nil {
+			t.Fatalf("Expected no error, got %s", err)
+		}
+		if _, err := apiCheckReconciler.Reconcile(ctx, apiCheckRequest); err != nil {
+			t.Fatalf("Expected no error, got %s", err)
+		}
+	}
+	if count := apiClient.CallCount(fakechecklyclient.MethodUpdateGroup); count != 0 {
+		t.Errorf("Expected no group update, got %d", count)
+	}
+	if count := apiClient.CallCount(fakechecklyclient.MethodUpdate); count != 0 {
+		t.Errorf("Expected no check update, got %d", count)
+	}
+
+	// A check changed in checklyhq.com is updated
+	if err := c.Get(ctx, apiCheckRequest.NamespacedName, apiCheck); err != nil {
+		t.Fatal(err)
+	}
+	remote := apiClient.Checks()[apiCheck.Status.ID]
+	remote.Muted = true
+	apiClient.SetCheck(remote)
+	if _, err := apiCheckReconciler.Reconcile(ctx, apiCheckRequest); err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+	if count := apiClient.CallCount(fakechecklyclient.MethodUpdate); count != 1 {
+		t.Errorf("Expected %d check update, got %d", 1, count)
+	}
+
+	// A changed spec is updated
+	if err := c.Get(ctx, groupRequest.NamespacedName, group); err != nil {
+		t.Fatal(err)
+	}
+	group.Spec.Locations = []string{"eu-west-1"}
+	group.Generation = 2
+	if err := c.Update(ctx, group); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := groupReconciler.Reconcile(ctx, groupRequest); err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+	if count := apiClient.CallCount(fakechecklyclient.MethodUpdateGroup); count != 1 {
+		t.Errorf("Expected %d group update, got %d", 1, count)
+	}
+}
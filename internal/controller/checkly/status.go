@@ -0,0 +1,123 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package checkly
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	checklyv1alpha1 "github.com/checkly/checkly-operator/api/checkly/v1alpha1"
+	external "github.com/checkly/checkly-operator/external/checkly"
+)
+
+// specChanged filters out the events caused by status and finalizer updates, labels are watched as they become checklyhq.com tags
+// and annotations as they control the lifecycle of the checklyhq.com resource, ex. adoption
+var specChanged = builder.WithPredicates(predicate.Or(predicate.GenerationChangedPredicate{}, predicate.LabelChangedPredicate{}, predicate.AnnotationChangedPredicate{}))
+
+// updateStatus writes the status of the object on error paths, a failure is only logged as the caller returns the original error
+func updateStatus(ctx context.Context, c client.Client, obj client.Object) {
+	err := c.Status().Update(ctx, obj)
+	if err != nil {
+		log.FromContext(ctx).Error(err, "Failed to update status")
+	}
+}
+
+// setDependenciesNotResolved records that a referenced resource is missing or not ready yet
+func setDependenciesNotResolved(status *checklyv1alpha1.SyncStatus, generation int64, reason string, message string) {
+	status.ObservedGeneration = generation
+	setCondition(status, generation, checklyv1alpha1.ConditionDependenciesResolved, metav1.ConditionFalse, reason, message)
+	setCondition(status, generation, checklyv1alpha1.ConditionReady, metav1.ConditionFalse, reason, message)
+}
+
+// setDeletionBlocked records that the resource is deleted but still referenced by other resources
+func setDeletionBlocked(status *checklyv1alpha1.SyncStatus, generation int64, message string) {
+	status.ObservedGeneration = generation
+	setCondition(status, generation, checklyv1alpha1.ConditionReady, metav1.ConditionFalse, checklyv1alpha1.ReasonDeletionBlocked, message)
+}
+
+// setSyncFailed records a failed checklyhq.com API call
+func setSyncFailed(status *checklyv1alpha1.SyncStatus, generation int64, err error) {
+	reason := checklyv1alpha1.ReasonSyncFailed
+	if external.IsRateLimited(err) {
+		reason = checklyv1alpha1.ReasonRateLimited
+	}
+
+	status.ObservedGeneration = generation
+	status.LastError = err.Error()
+	setCondition(status, generation, checklyv1alpha1.ConditionDependenciesResolved, metav1.ConditionTrue, checklyv1alpha1.ReasonDependenciesResolved, "All referenced resources are ready")
+	setCondition(status, generation, checklyv1alpha1.ConditionSynced, metav1.ConditionFalse, reason, err.Error())
+	setCondition(status, generation, checklyv1alpha1.ConditionReady, metav1.ConditionFalse, reason, err.Error())
+}
+
+// syncFailedResult requeues after a failed checklyhq.com API call, a rate limited call is tried again after the delay
+// checklyhq.com asked for, other failures use the exponential backoff of the controller
+func syncFailedResult(err error) (ctrl.Result, error) {
+	if delay, ok := external.RequeueAfter(err); ok {
+		return ctrl.Result{RequeueAfter: delay}, nil
+	}
+	return ctrl.Result{}, err
+}
+
+// setSynced records a successful sync with checklyhq.com
+func setSynced(status *checklyv1alpha1.SyncStatus, generation int64) {
+	now := metav1.Now()
+	status.ObservedGeneration = generation
+	status.LastSyncTime = &now
+	status.LastError = ""
+	setCondition(status, generation, checklyv1alpha1.ConditionDependenciesResolved, metav1.ConditionTrue, checklyv1alpha1.ReasonDependenciesResolved, "All referenced resources are ready")
+	setCondition(status, generation, checklyv1alpha1.ConditionSynced, metav1.ConditionTrue, checklyv1alpha1.ReasonSynced, "Synced with checklyhq.com")
+	setCondition(status, generation, checklyv1alpha1.ConditionReady, metav1.ConditionTrue, checklyv1alpha1.ReasonSynced, "Synced with checklyhq.com")
+}
+
+// isSynced determines if the given generation of the resource was already synced with checklyhq.com
+func isSynced(status *checklyv1alpha1.SyncStatus, generation int64) bool {
+	condition := meta.FindStatusCondition(status.Conditions, checklyv1alpha1.ConditionSynced)
+	return condition != nil && condition.Status == metav1.ConditionTrue && condition.ObservedGeneration == generation
+}
+
+// setDrift records the result of comparing the resource with its checklyhq.com counterpart, the drifted fields are overwritten by the following update
+func setDrift(status *checklyv1alpha1.SyncStatus, generation int64, drift []string) {
+	if len(drift) == 0 {
+		setCondition(status, generation, checklyv1alpha1.ConditionDrifted, metav1.ConditionFalse, checklyv1alpha1.ReasonNoDrift, "In sync with checklyhq.com")
+		return
+	}
+	setCondition(status, generation, checklyv1alpha1.ConditionDrifted, metav1.ConditionTrue, checklyv1alpha1.ReasonDriftCorrected, fmt.Sprintf("Changed in checklyhq.com and reverted: %s", strings.Join(drift, ", ")))
+}
+
+// setRecreated records that the resource was deleted in checklyhq.com and is created again
+func setRecreated(status *checklyv1alpha1.SyncStatus, generation int64) {
+	setCondition(status, generation, checklyv1alpha1.ConditionDrifted, metav1.ConditionTrue, checklyv1alpha1.ReasonRecreated, "Deleted in checklyhq.com and created again")
+}
+
+func setCondition(status *checklyv1alpha1.SyncStatus, generation int64, conditionType string, conditionStatus metav1.ConditionStatus, reason string, message string) {
+	meta.SetStatusCondition(&status.Conditions, metav1.Condition{
+		Type:               conditionType,
+		Status:             conditionStatus,
+		ObservedGeneration: generation,
+		Reason:             reason,
+		Message:            message,
+	})
+}
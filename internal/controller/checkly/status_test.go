@@ -0,0 +1,126 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package checkly
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+
+	checklyv1alpha1 "github.com/checkly/checkly-operator/api/checkly/v1alpha1"
+	external "github.com/checkly/checkly-operator/external/checkly"
+)
+
+func TestSyncStatus(t *testing.T) {
+	status := checklyv1alpha1.SyncStatus{}
+
+	setDependenciesNotResolved(&status, 1, checklyv1alpha1.ReasonDependencyNotFound, "Group foo not found")
+	if meta.IsStatusConditionTrue(status.Conditions, checklyv1alpha1.ConditionReady) {
+		t.Error("Expected Ready to be false with missing dependencies")
+	}
+	if meta.IsStatusConditionTrue(status.Conditions, checklyv1alpha1.ConditionDependenciesResolved) {
+		t.Error("Expected DependenciesResolved to be false with missing dependencies")
+	}
+	if status.ObservedGeneration != 1 {
+		t.Errorf("Expected %d, got %d", 1, status.ObservedGeneration)
+	}
+
+	syncErr := errors.New("unexpected response status 500")
+	setSyncFailed(&status, 2, syncErr)
+	if status.LastError != syncErr.Error() {
+		t.Errorf("Expected %s, got %s", syncErr.Error(), status.LastError)
+	}
+	if !meta.IsStatusConditionTrue(status.Conditions, checklyv1alpha1.ConditionDependenciesResolved) {
+		t.Error("Expected DependenciesResolved to be true after a sync attempt")
+	}
+	if condition := meta.FindStatusCondition(status.Conditions, checklyv1alpha1.ConditionReady); condition == nil || condition.Reason != checklyv1alpha1.ReasonSyncFailed {
+		t.Errorf("Expected Ready reason %s, got %v", checklyv1alpha1.ReasonSyncFailed, condition)
+	}
+	if status.LastSyncTime != nil {
+		t.Error("Expected no last sync time before a successful sync")
+	}
+
+	setSynced(&status, 3)
+	for _, conditionType := range []string{checklyv1alpha1.ConditionReady, checklyv1alpha1.ConditionSynced, checklyv1alpha1.ConditionDependenciesResolved} {
+		if !meta.IsStatusConditionTrue(status.Conditions, conditionType) {
+			t.Errorf("Expected %s to be true after a sync", conditionType)
+		}
+	}
+	if status.LastError != "" {
+		t.Errorf("Expected the last error to be cleared, got %s", status.LastError)
+	}
+	if status.LastSyncTime == nil {
+		t.Error("Expected the last sync time to be set")
+	}
+	if status.ObservedGeneration != 3 {
+		t.Errorf("Expected %d, got %d", 3, status.ObservedGeneration)
+	}
+}
+
+func TestDriftStatus(t *testing.T) {
+	status := checklyv1alpha1.SyncStatus{}
+
+	setDrift(&status, 1, nil)
+	if condition := meta.FindStatusCondition(status.Conditions, checklyv1alpha1.ConditionDrifted); condition == nil || condition.Reason != checklyv1alpha1.ReasonNoDrift {
+		t.Errorf("Expected Drifted reason %s, got %v", checklyv1alpha1.ReasonNoDrift, condition)
+	}
+
+	setDrift(&status, 1, []string{"name", "frequency"})
+	condition := meta.FindStatusCondition(status.Conditions, checklyv1alpha1.ConditionDrifted)
+	if condition == nil || condition.Reason != checklyv1alpha1.ReasonDriftCorrected {
+		t.Fatalf("Expected Drifted reason %s, got %v", checklyv1alpha1.ReasonDriftCorrected, condition)
+	}
+	if condition.Message != "Changed in checklyhq.com and reverted: name, frequency" {
+		t.Errorf("Unexpected message %s", condition.Message)
+	}
+
+	setRecreated(&status, 2)
+	if !meta.IsStatusConditionTrue(status.Conditions, checklyv1alpha1.ConditionDrifted) {
+		t.Error("Expected Drifted to be true after a recreate")
+	}
+	if condition := meta.FindStatusCondition(status.Conditions, checklyv1alpha1.ConditionDrifted); condition.Reason != checklyv1alpha1.ReasonRecreated {
+		t.Errorf("Expected Drifted reason %s, got %s", checklyv1alpha1.ReasonRecreated, condition.Reason)
+	}
+}
+
+func TestRateLimitedStatus(t *testing.T) {
+	status := checklyv1alpha1.SyncStatus{}
+	rateLimited := &external.APIError{
+		StatusCode: http.StatusTooManyRequests,
+		RetryAfter: time.Minute,
+		Err:        errors.New("unexpected response status 429"),
+	}
+
+	setSyncFailed(&status, 1, rateLimited)
+	if condition := meta.FindStatusCondition(status.Conditions, checklyv1alpha1.ConditionReady); condition == nil || condition.Reason != checklyv1alpha1.ReasonRateLimited {
+		t.Errorf("Expected Ready reason %s, got %v", checklyv1alpha1.ReasonRateLimited, condition)
+	}
+
+	result, err := syncFailedResult(rateLimited)
+	if err != nil || result.RequeueAfter != time.Minute {
+		t.Errorf("Expected a requeue after %s without error, got %v, %v", time.Minute, result, err)
+	}
+
+	syncErr := errors.New("unexpected response status 500")
+	result, err = syncFailedResult(syncErr)
+	if err != syncErr || result.RequeueAfter != 0 {
+		t.Errorf("Expected the error to be returned, got %v, %v", result, err)
+	}
+}
@@ -103,7 +103,7 @@ var _ = BeforeSuite(func() {
 			r.ParseForm()
 			method := r.Method
 			switch method {
-			case "PUT":
+			case "GET", "PUT":
 				w.WriteHeader(http.StatusOK)
 				w.Header().Set("Content-Type", "application/json")
 				resp := make(map[string]string)
@@ -128,7 +128,7 @@ var _ = BeforeSuite(func() {
 			r.ParseForm()
 			method := r.Method
 			switch method {
-			case "PUT":
+			case "GET", "PUT":
 				w.WriteHeader(http.StatusOK)
 				w.Header().Set("Content-Type", "application/json")
 				resp := make(map[string]interface{})
@@ -153,7 +153,7 @@ var _ = BeforeSuite(func() {
 			r.ParseForm()
 			method := r.Method
 			switch method {
-			case "PUT":
+			case "GET", "PUT":
 				w.WriteHeader(http.StatusOK)
 				w.Header().Set("Content-Type", "application/json")
 				resp := make(map[string]interface{})
@@ -175,6 +175,16 @@ var _ = BeforeSuite(func() {
 		Scheme:           k8sManager.GetScheme(),
 		ApiClient:        testClient,
 		ControllerDomain: testControllerDomain,
+		Recorder:         k8sManager.GetEventRecorderFor("apicheck-controller"),
+	}).SetupWithManager(k8sManager)
+	Expect(err).ToNot(HaveOccurred())
+
+	err = (&BrowserCheckReconciler{
+		Client:           k8sManager.GetClient(),
+		Scheme:           k8sManager.GetScheme(),
+		ApiClient:        testClient,
+		ControllerDomain: testControllerDomain,
+		Recorder:         k8sManager.GetEventRecorderFor("browsercheck-controller"),
 	}).SetupWithManager(k8sManager)
 	Expect(err).ToNot(HaveOccurred())
 
@@ -183,6 +193,7 @@ var _ = BeforeSuite(func() {
 		Scheme:           k8sManager.GetScheme(),
 		ApiClient:        testClient,
 		ControllerDomain: testControllerDomain,
+		Recorder:         k8sManager.GetEventRecorderFor("group-controller"),
 	}).SetupWithManager(k8sManager)
 	Expect(err).ToNot(HaveOccurred())
 
@@ -191,6 +202,29 @@ var _ = BeforeSuite(func() {
 		Scheme:           k8sManager.GetScheme(),
 		ApiClient:        testClient,
 		ControllerDomain: testControllerDomain,
+		Recorder:         k8sManager.GetEventRecorderFor("alertchannel-controller"),
+	}).SetupWithManager(k8sManager)
+	Expect(err).ToNot(HaveOccurred())
+
+	err = (&CheckGroupReconciler{
+		GroupReconciler: GroupReconciler{
+			Client:           k8sManager.GetClient(),
+			Scheme:           k8sManager.GetScheme(),
+			ApiClient:        testClient,
+			ControllerDomain: testControllerDomain,
+			Recorder:         k8sManager.GetEventRecorderFor("checkgroup-controller"),
+		},
+	}).SetupWithManager(k8sManager)
+	Expect(err).ToNot(HaveOccurred())
+
+	err = (&NamespacedAlertChannelReconciler{
+		AlertChannelReconciler: AlertChannelReconciler{
+			Client:           k8sManager.GetClient(),
+			Scheme:           k8sManager.GetScheme(),
+			ApiClient:        testClient,
+			ControllerDomain: testControllerDomain,
+			Recorder:         k8sManager.GetEventRecorderFor("namespacedalertchannel-controller"),
+		},
 	}).SetupWithManager(k8sManager)
 	Expect(err).ToNot(HaveOccurred())
 
@@ -0,0 +1,456 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package networking
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	checklyv1alpha1 "github.com/checkly/checkly-operator/api/checkly/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/yaml"
+)
+
+// apiCheckAnnotations holds the annotations of an Ingress or HTTPRoute which configure the generated ApiChecks
+type apiCheckAnnotations struct {
+	// path replaces the paths of the rules if set
+	path    string
+	hasPath bool
+	// endpoint replaces the hosts of the rules if set
+	endpoint        string
+	success         string
+	group           string
+	muted           bool
+	frequency       int
+	maxResponseTime int
+	method          string
+	expectedBody    string
+	locations       []string
+	headers         []checklyv1alpha1.ApiCheckKeyValue
+	// overrides change the settings of single hosts and paths, later entries take precedence
+	overrides []apiCheckOverride
+}
+
+// apiCheckOverride is an entry of the overrides annotation, it applies to the ApiChecks of the matching host and path,
+// an empty host or path matches every host or path
+type apiCheckOverride struct {
+	Host            string            `json:"host,omitempty"`
+	Path            string            `json:"path,omitempty"`
+	Exclude         bool              `json:"exclude,omitempty"`
+	Success         string            `json:"success,omitempty"`
+	Muted           *bool             `json:"muted,omitempty"`
+	Group           string            `json:"group,omitempty"`
+	Frequency       int               `json:"frequency,omitempty"`
+	MaxResponseTime int               `json:"maxResponseTime,omitempty"`
+	Method          string            `json:"method,omitempty"`
+	ExpectedBody    string            `json:"expectedBody,omitempty"`
+	Locations       []string          `json:"locations,omitempty"`
+	Headers         map[string]string `json:"headers,omitempty"`
+}
+
+// apiCheckMethods holds the HTTP methods accepted by the method annotation
+var apiCheckMethods = []string{"GET", "POST", "PUT", "PATCH", "DELETE", "HEAD", "OPTIONS"}
+
+// readApiCheckAnnotations reads the annotations shared by the Ingress and HTTPRoute reconcilers
+func readApiCheckAnnotations(controllerDomain string, annotations map[string]string) (checkAnnotations apiCheckAnnotations, err error) {
+	annotationPath := fmt.Sprintf("%s/path", controllerDomain)
+	annotationEndpoint := fmt.Sprintf("%s/endpoint", controllerDomain)
+	annotationSuccess := fmt.Sprintf("%s/success", controllerDomain)
+	annotationGroup := fmt.Sprintf("%s/group", controllerDomain)
+	annotationMuted := fmt.Sprintf("%s/muted", controllerDomain)
+	annotationFrequency := fmt.Sprintf("%s/frequency", controllerDomain)
+	annotationMaxResponseTime := fmt.Sprintf("%s/max-response-time", controllerDomain)
+	annotationMethod := fmt.Sprintf("%s/method", controllerDomain)
+	annotationExpectedBody := fmt.Sprintf("%s/expected-body", controllerDomain)
+	annotationLocations := fmt.Sprintf("%s/locations", controllerDomain)
+	annotationHeaders := fmt.Sprintf("%s/headers", controllerDomain)
+	annotationOverrides := fmt.Sprintf("%s/overrides", controllerDomain)
+
+	// Expected success code
+	checkAnnotations.success = annotations[annotationSuccess]
+	if checkAnnotations.success == "" {
+		checkAnnotations.success = "200"
+	}
+
+	// Group
+	checkAnnotations.group = annotations[annotationGroup]
+	if checkAnnotations.group == "" {
+		err = fmt.Errorf("could not find a value for the group annotation, can't continue without one")
+		return
+	}
+
+	// Muted
+	checkAnnotations.muted = annotations[annotationMuted] != "false"
+
+	checkAnnotations.endpoint = annotations[annotationEndpoint]
+	checkAnnotations.path, checkAnnotations.hasPath = annotations[annotationPath]
+
+	// Request settings, empty values use the defaults of the ApiCheck
+	if value := annotations[annotationFrequency]; value != "" {
+		checkAnnotations.frequency, err = strconv.Atoi(value)
+		if err != nil {
+			err = fmt.Errorf("the frequency annotation has to be a number of minutes, got %q", value)
+			return
+		}
+	}
+
+	if value := annotations[annotationMaxResponseTime]; value != "" {
+		checkAnnotations.maxResponseTime, err = strconv.Atoi(value)
+		if err != nil {
+			err = fmt.Errorf("the max-response-time annotation has to be a number of milliseconds, got %q", value)
+			return
+		}
+	}
+
+	checkAnnotations.method, err = readMethod(annotations[annotationMethod])
+	if err != nil {
+		return
+	}
+
+	checkAnnotations.expectedBody = annotations[annotationExpectedBody]
+
+	for _, location := range strings.Split(annotations[annotationLocations], ",") {
+		if location = strings.TrimSpace(location); location != "" {
+			checkAnnotations.locations = append(checkAnnotations.locations, location)
+		}
+	}
+
+	// Headers are a YAML or JSON map of header names to values
+	if value := annotations[annotationHeaders]; value != "" {
+		headers := make(map[string]string)
+		if err = yaml.UnmarshalStrict([]byte(value), &headers); err != nil {
+			err = fmt.Errorf("the headers annotation has to be a map of header names to values: %w", err)
+			return
+		}
+		checkAnnotations.headers = apiCheckHeaders(headers)
+	}
+
+	// Overrides are a YAML or JSON list
+	if value := annotations[annotationOverrides]; value != "" {
+		if err = yaml.UnmarshalStrict([]byte(value), &checkAnnotations.overrides); err != nil {
+			err = fmt.Errorf("the overrides annotation has to be a list of host and path overrides: %w", err)
+			return
+		}
+		for _, override := range checkAnnotations.overrides {
+			if _, err = readMethod(override.Method); err != nil {
+				return
+			}
+		}
+	}
+
+	return
+}
+
+// readMethod returns the upper case HTTP method, an empty method is returned as is
+func readMethod(method string) (string, error) {
+	method = strings.ToUpper(method)
+	if method != "" && !slices.Contains(apiCheckMethods, method) {
+		return "", fmt.Errorf("unsupported method %q, has to be one of %s", method, strings.Join(apiCheckMethods, ", "))
+	}
+	return method, nil
+}
+
+// apiCheckHeaders turns the header map into ApiCheck headers sorted by name, so the ApiCheck spec doesn't change
+// between reconciles
+func apiCheckHeaders(headers map[string]string) (keyValues []checklyv1alpha1.ApiCheckKeyValue) {
+	keys := make([]string, 0, len(headers))
+	for key := range headers {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		keyValues = append(keyValues, checklyv1alpha1.ApiCheckKeyValue{Key: key, Value: headers[key]})
+	}
+	return
+}
+
+// forPath applies the overrides matching the host and path, false is returned if the host and path are excluded
+func (checkAnnotations apiCheckAnnotations) forPath(host string, path string) (apiCheckAnnotations, bool) {
+	included := true
+	for _, override := range checkAnnotations.overrides {
+		if (override.Host != "" && override.Host != host) || (override.Path != "" && override.Path != path) {
+			continue
+		}
+
+		included = !override.Exclude
+		if override.Success != "" {
+			checkAnnotations.success = override.Success
+		}
+		if override.Muted != nil {
+			checkAnnotations.muted = *override.Muted
+		}
+		if override.Group != "" {
+			checkAnnotations.group = override.Group
+		}
+		if override.Frequency != 0 {
+			checkAnnotations.frequency = override.Frequency
+		}
+		if override.MaxResponseTime != 0 {
+			checkAnnotations.maxResponseTime = override.MaxResponseTime
+		}
+		if override.Method != "" {
+			checkAnnotations.method = strings.ToUpper(override.Method)
+		}
+		if override.ExpectedBody != "" {
+			checkAnnotations.expectedBody = override.ExpectedBody
+		}
+		if override.Locations != nil {
+			checkAnnotations.locations = override.Locations
+		}
+		if override.Headers != nil {
+			checkAnnotations.headers = apiCheckHeaders(override.Headers)
+		}
+	}
+
+	return checkAnnotations, included
+}
+
+// newApiCheck builds the ApiCheck of a host and path of the owner, the name is derived from the owner name, host and path.
+// The overrides matching the host and path are applied, nil is returned if they exclude it.
+func newApiCheck(
+	owner metav1.Object,
+	ownerRef metav1.OwnerReference,
+	labels map[string]string,
+	checkAnnotations apiCheckAnnotations,
+	scheme string,
+	host string,
+	path string,
+) *checklyv1alpha1.ApiCheck {
+	checkAnnotations, included := checkAnnotations.forPath(host, path)
+	if !included {
+		return nil
+	}
+
+	// Replace path /
+	path = strings.TrimPrefix(path, "/")
+
+	// Set endpoint
+	endpoint := fmt.Sprintf("%s://%s/%s", scheme, host, path)
+
+	return buildApiCheck(owner, ownerRef, labels, checkAnnotations, apiCheckName(owner.GetName(), host, path), endpoint)
+}
+
+// apiCheckName derives the name of a generated ApiCheck from the owner name, host and path
+func apiCheckName(ownerName string, host string, path string) string {
+	checkName := fmt.Sprintf("%s-%s-%s", ownerName, host, strings.TrimPrefix(path, "/"))
+	checkName = strings.Replace(checkName, "/", "", -1)
+	checkName = strings.Replace(checkName, ".", "", -1)
+	// Ports and IPv6 addresses
+	checkName = strings.NewReplacer(":", "", "[", "", "]", "").Replace(checkName)
+	return strings.Trim(checkName, "-")
+}
+
+// buildApiCheck builds an ApiCheck owned by the owner
+func buildApiCheck(
+	owner metav1.Object,
+	ownerRef metav1.OwnerReference,
+	labels map[string]string,
+	checkAnnotations apiCheckAnnotations,
+	name string,
+	endpoint string,
+) *checklyv1alpha1.ApiCheck {
+	var assertions []checklyv1alpha1.ApiCheckAssertion
+	if checkAnnotations.expectedBody != "" {
+		assertions = append(assertions, checklyv1alpha1.ApiCheckAssertion{
+			Source:     "TEXT_BODY",
+			Comparison: "CONTAINS",
+			Target:     checkAnnotations.expectedBody,
+		})
+	}
+
+	return &checklyv1alpha1.ApiCheck{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            name,
+			Namespace:       owner.GetNamespace(),
+			OwnerReferences: []metav1.OwnerReference{ownerRef},
+			Labels:          labels,
+		},
+		Spec: checklyv1alpha1.ApiCheckSpec{
+			Endpoint:        endpoint,
+			Group:           checkAnnotations.group,
+			Success:         checkAnnotations.success,
+			Muted:           checkAnnotations.muted,
+			Frequency:       checkAnnotations.frequency,
+			MaxResponseTime: checkAnnotations.maxResponseTime,
+			Method:          checkAnnotations.method,
+			Locations:       checkAnnotations.locations,
+			Headers:         checkAnnotations.headers,
+			Assertions:      assertions,
+		},
+	}
+}
+
+// compareApiChecks splits the ApiChecks into the ones which need to be created, deleted or updated,
+// the existing ApiChecks are found by the labels of the owner
+func compareApiChecks(
+	ctx context.Context,
+	c client.Client,
+	namespace string,
+	labels map[string]string,
+	ownerApiChecks []*checklyv1alpha1.ApiCheck,
+) (
+	newApiChecks []*checklyv1alpha1.ApiCheck,
+	deleteApiChecks []*checklyv1alpha1.ApiCheck,
+	updateApiChecks []*checklyv1alpha1.ApiCheck,
+	err error,
+) {
+
+	logger := log.FromContext(ctx)
+
+	var existingApiChecks checklyv1alpha1.ApiCheckList
+
+	err = c.List(ctx, &existingApiChecks, client.InNamespace(namespace), client.MatchingLabels(labels))
+	if err != nil {
+		return
+	}
+
+	existingApiChecksMap := make(map[string]checklyv1alpha1.ApiCheck)
+	for _, existingApiCheck := range existingApiChecks.Items {
+		existingApiChecksMap[existingApiCheck.Name] = existingApiCheck
+	}
+
+	newApiChecksMap := make(map[string]*checklyv1alpha1.ApiCheck)
+	for _, ownerApiCheck := range ownerApiChecks {
+		newApiChecksMap[ownerApiCheck.Name] = ownerApiCheck
+	}
+
+	// Compare items
+	for _, existingApiCheck := range existingApiChecksMap {
+		newApiCheck, exists := newApiChecksMap[existingApiCheck.Name]
+		if exists {
+			if equality.Semantic.DeepEqual(existingApiCheck.Spec, newApiCheck.Spec) {
+				logger.Info("ApiCheck data is identical, no need for update", "ApiCheck Name", existingApiCheck.Name)
+			} else {
+				logger.Info(
+					"ApiCheck data is not identical, update needed", "ApiCheck Name", existingApiCheck.Name, "old spec", existingApiCheck.Spec, "new spec", newApiCheck.Spec)
+				updateApiChecks = append(updateApiChecks, newApiCheck)
+			}
+
+			// Remove items from new api checks map
+			delete(newApiChecksMap, existingApiCheck.Name)
+		} else {
+			logger.Info("ApiCheck is not needed anymore, delete", "ApiCheck Name", existingApiCheck.Name)
+			deleteApiChecks = append(deleteApiChecks, &existingApiCheck)
+		}
+	}
+
+	// Loop over remaining items and add them to the new checks list, these will be created
+	for _, newApiCheck := range newApiChecksMap {
+		newApiChecks = append(newApiChecks, newApiCheck)
+	}
+
+	return
+}
+
+// removeApiChecks deletes the ApiChecks of an owner which is disabled or deleted, failures are logged only
+// as the ApiChecks are garbage collected with their owner anyway
+func removeApiChecks(
+	ctx context.Context,
+	c client.Client,
+	apiCheckResources []*checklyv1alpha1.ApiCheck,
+) {
+
+	logger := log.FromContext(ctx)
+
+	for _, apiCheckResource := range apiCheckResources {
+
+		logger.Info("Checking if ApiCheck was created", "ApiCheck Name", apiCheckResource.Name, "ApiCheck namespace", apiCheckResource.Namespace)
+		err := c.Get(ctx, client.ObjectKeyFromObject(apiCheckResource), apiCheckResource)
+		if err != nil {
+			logger.Info("ApiCheck resource is not present, we don't need to do anything", "ApiCheck Name", apiCheckResource.Name, "ApiCheck namespace", apiCheckResource.Namespace)
+			continue
+		}
+
+		logger.Info("ApiCheck resource is present, we need to delete it", "ApiCheck Name", apiCheckResource.Name, "ApiCheck namespace", apiCheckResource.Namespace)
+		err = c.Delete(ctx, apiCheckResource)
+		if err != nil {
+			logger.Error(err, "Failed to delete ApiCheck", "Name", apiCheckResource.Name, "Namespace", apiCheckResource.Namespace)
+			continue
+		}
+
+		logger.Info("ApiCheck resource deleted successfully", "Name", apiCheckResource.Name, "Namespace", apiCheckResource.Namespace)
+	}
+}
+
+// syncApiChecks creates, updates and deletes the ApiChecks of the owner so they match the desired ApiChecks, an event
+// is recorded on the owner for every change. Without desired ApiChecks all ApiChecks of the owner are deleted.
+func syncApiChecks(
+	ctx context.Context,
+	c client.Client,
+	recorder record.EventRecorder,
+	owner client.Object,
+	labels map[string]string,
+	desiredApiChecks []*checklyv1alpha1.ApiCheck,
+) error {
+
+	logger := log.FromContext(ctx)
+
+	newApiChecks, deleteApiChecks, updateApiChecks, err := compareApiChecks(ctx, c, owner.GetNamespace(), labels, desiredApiChecks)
+	if err != nil {
+		logger.Error(err, "Failed to list existing API checks")
+		return err
+	}
+
+	// Create new Api Checks
+	for _, apiCheck := range newApiChecks {
+		logger.Info("Creating ApiCheck", "ApiCheck Name", apiCheck.Name, "ApiCheck spec", apiCheck.Spec)
+		err = c.Create(ctx, apiCheck)
+		if err != nil {
+			logger.Error(err, "Failed to create ApiCheck", "APICheck name", apiCheck.Name, "Namespace", apiCheck.Namespace)
+			recorder.Event(owner, corev1.EventTypeWarning, "ApiCheckCreateFailed", fmt.Sprintf("Failed to create ApiCheck %s: %s", apiCheck.Name, err))
+			return err
+		}
+		recorder.Event(owner, corev1.EventTypeNormal, "ApiCheckCreated", fmt.Sprintf("Created ApiCheck %s", apiCheck.Name))
+	}
+
+	// Update API checks
+	for _, apiCheck := range updateApiChecks {
+		logger.Info("Updating ApiCheck", "ApiCheck Name", apiCheck.Name)
+		err = c.Update(ctx, apiCheck)
+		if err != nil {
+			logger.Error(err, "Failed to update APICheck resource", "APICheck name", apiCheck.Name, "Namespace", apiCheck.Namespace)
+			recorder.Event(owner, corev1.EventTypeWarning, "ApiCheckUpdateFailed", fmt.Sprintf("Failed to update ApiCheck %s: %s", apiCheck.Name, err))
+			return err
+		}
+		recorder.Event(owner, corev1.EventTypeNormal, "ApiCheckUpdated", fmt.Sprintf("Updated ApiCheck %s", apiCheck.Name))
+	}
+
+	// Delete old API checks
+	for _, apiCheck := range deleteApiChecks {
+		logger.Info("Delete ApiCheck", "ApiCheck Name", apiCheck.Name)
+		err = c.Delete(ctx, apiCheck)
+		if err != nil && !errors.IsNotFound(err) {
+			logger.Error(err, "Failed to delete ApiCheck resource", "APICheck name", apiCheck.Name, "Namespace", apiCheck.Namespace)
+			recorder.Event(owner, corev1.EventTypeWarning, "ApiCheckDeleteFailed", fmt.Sprintf("Failed to delete ApiCheck %s: %s", apiCheck.Name, err))
+			return err
+		}
+		recorder.Event(owner, corev1.EventTypeNormal, "ApiCheckDeleted", fmt.Sprintf("Deleted ApiCheck %s", apiCheck.Name))
+	}
+
+	return nil
+}
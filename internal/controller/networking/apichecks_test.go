@@ -0,0 +1,169 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package networking
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	checklyv1alpha1 "github.com/checkly/checkly-operator/api/checkly/v1alpha1"
+	networkingv1 "k8s.io/api/networking/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+func TestReadApiCheckAnnotations(t *testing.T) {
+	checkAnnotations, err := readApiCheckAnnotations("testing.domain.tld", map[string]string{
+		"testing.domain.tld/group":             "foo",
+		"testing.domain.tld/frequency":         "10",
+		"testing.domain.tld/max-response-time": "2000",
+		"testing.domain.tld/method":            "head",
+		"testing.domain.tld/expected-body":     "ok",
+		"testing.domain.tld/locations":         "eu-west-1, us-east-1",
+		"testing.domain.tld/headers":           "X-Foo: bar\nAccept: application/json",
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %e", err)
+	}
+
+	if checkAnnotations.frequency != 10 || checkAnnotations.maxResponseTime != 2000 {
+		t.Errorf("Expected %d and %d, got %d and %d", 10, 2000, checkAnnotations.frequency, checkAnnotations.maxResponseTime)
+	}
+	if checkAnnotations.method != "HEAD" {
+		t.Errorf("Expected %s, got %s", "HEAD", checkAnnotations.method)
+	}
+	if !reflect.DeepEqual(checkAnnotations.locations, []string{"eu-west-1", "us-east-1"}) {
+		t.Errorf("Expected %v, got %v", []string{"eu-west-1", "us-east-1"}, checkAnnotations.locations)
+	}
+	expectedHeaders := []checklyv1alpha1.ApiCheckKeyValue{{Key: "Accept", Value: "application/json"}, {Key: "X-Foo", Value: "bar"}}
+	if !reflect.DeepEqual(checkAnnotations.headers, expectedHeaders) {
+		t.Errorf("Expected %v, got %v", expectedHeaders, checkAnnotations.headers)
+	}
+
+	apiCheck := buildApiCheck(&metav1.ObjectMeta{Name: "foo"}, metav1.OwnerReference{}, nil, checkAnnotations, "foo", "https://foo.bar")
+	expectedAssertions := []checklyv1alpha1.ApiCheckAssertion{{Source: "TEXT_BODY", Comparison: "CONTAINS", Target: "ok"}}
+	if !reflect.DeepEqual(apiCheck.Spec.Assertions, expectedAssertions) {
+		t.Errorf("Expected %v, got %v", expectedAssertions, apiCheck.Spec.Assertions)
+	}
+
+	invalid := []map[string]string{
+		{"testing.domain.tld/frequency": "often"},
+		{"testing.domain.tld/max-response-time": "2s"},
+		{"testing.domain.tld/method": "FETCH"},
+		{"testing.domain.tld/headers": "- foo"},
+		{"testing.domain.tld/overrides": "path: /foo"},
+		{"testing.domain.tld/overrides": "- pth: /foo"},
+		{"testing.domain.tld/overrides": "- method: FETCH"},
+	}
+	for _, annotations := range invalid {
+		annotations["testing.domain.tld/group"] = "foo"
+		if _, err := readApiCheckAnnotations("testing.domain.tld", annotations); err == nil {
+			t.Errorf("Expected error for %v, got none", annotations)
+		}
+	}
+}
+
+func TestIngressGatherApiCheckDataOverrides(t *testing.T) {
+	r := &IngressReconciler{ControllerDomain: "testing.domain.tld"}
+
+	paths := []networkingv1.HTTPIngressPath{{Path: "/"}, {Path: "/api"}, {Path: "/metrics"}}
+	ingress := &networkingv1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-ingress",
+			Namespace: "default",
+			Annotations: map[string]string{
+				"testing.domain.tld/enabled":   "true",
+				"testing.domain.tld/group":     "foo",
+				"testing.domain.tld/frequency": "10",
+				"testing.domain.tld/overrides": `
+- path: /metrics
+  exclude: true
+- path: /api
+  success: "401"
+  muted: false
+- host: admin.foo.bar
+  path: /api
+  success: "403"
+  locations: [us-east-1]
+`,
+			},
+		},
+		Spec: networkingv1.IngressSpec{
+			Rules: []networkingv1.IngressRule{
+				{Host: "foo.bar", IngressRuleValue: networkingv1.IngressRuleValue{HTTP: &networkingv1.HTTPIngressRuleValue{Paths: paths}}},
+				{Host: "admin.foo.bar", IngressRuleValue: networkingv1.IngressRuleValue{HTTP: &networkingv1.HTTPIngressRuleValue{Paths: paths}}},
+			},
+		},
+	}
+
+	apiChecks, err := r.gatherApiCheckData(ingress)
+	if err != nil {
+		t.Fatalf("Expected no error, got %e", err)
+	}
+
+	specs := make(map[string]checklyv1alpha1.ApiCheckSpec)
+	for _, apiCheck := range apiChecks {
+		specs[apiCheck.Name] = apiCheck.Spec
+	}
+
+	expected := map[string]checklyv1alpha1.ApiCheckSpec{
+		"test-ingress-foobar": {
+			Endpoint: "https://foo.bar/", Group: "foo", Success: "200", Muted: true, Frequency: 10,
+		},
+		"test-ingress-foobar-api": {
+			Endpoint: "https://foo.bar/api", Group: "foo", Success: "401", Muted: false, Frequency: 10,
+		},
+		"test-ingress-adminfoobar": {
+			Endpoint: "https://admin.foo.bar/", Group: "foo", Success: "200", Muted: true, Frequency: 10,
+		},
+		"test-ingress-adminfoobar-api": {
+			Endpoint: "https://admin.foo.bar/api", Group: "foo", Success: "403", Muted: false, Frequency: 10, Locations: []string{"us-east-1"},
+		},
+	}
+	if !reflect.DeepEqual(specs, expected) {
+		t.Errorf("Expected %v, got %v", expected, specs)
+	}
+}
+
+func TestSyncApiChecksAlreadyDeleted(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := checklyv1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	ingress := &networkingv1.Ingress{ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "default"}}
+	apiCheck := &checklyv1alpha1.ApiCheck{
+		ObjectMeta: metav1.ObjectMeta{Name: "foo-0", Namespace: "default", Labels: ingressLabels(ingress)},
+	}
+
+	// The ApiCheck is listed from the cache but was deleted in the meantime
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(apiCheck).WithInterceptorFuncs(interceptor.Funcs{
+		Delete: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.DeleteOption) error {
+			return apierrors.NewNotFound(checklyv1alpha1.GroupVersion.WithResource("apichecks").GroupResource(), obj.GetName())
+		},
+	}).Build()
+
+	err := syncApiChecks(context.Background(), c, record.NewFakeRecorder(10), ingress, ingressLabels(ingress), nil)
+	if err != nil {
+		t.Errorf("Expected no error, got %s", err)
+	}
+}
@@ -0,0 +1,130 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package networking
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	checklyv1alpha1 "github.com/checkly/checkly-operator/api/checkly/v1alpha1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// DeploymentReconciler generates an ApiCheck for the health URL set in the annotations of a Deployment, for workloads
+// which are exposed without an Ingress or LoadBalancer Service. The ApiCheck is garbage collected with the Deployment.
+type DeploymentReconciler struct {
+	client.Client
+	Scheme           *runtime.Scheme
+	ControllerDomain string
+	Recorder         record.EventRecorder
+}
+
+//+kubebuilder:rbac:groups=apps,resources=deployments,verbs=get;list;watch
+//+kubebuilder:rbac:groups=k8s.checklyhq.com,resources=apichecks,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+
+// Reconcile is part of the main kubernetes reconciliation loop which aims to
+// move the current state of the cluster closer to the desired state.
+func (r *DeploymentReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	deployment := appsv1.Deployment{}
+	annotationEnabled := fmt.Sprintf("%s/enabled", r.ControllerDomain)
+	err := r.Get(ctx, req.NamespacedName, &deployment)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		logger.Error(err, "Can't read the Deployment object")
+		return ctrl.Result{}, err
+	}
+
+	// Remove the ApiCheck of disabled deployments
+	if value, exists := deployment.Annotations[annotationEnabled]; !exists || value == "false" {
+		return ctrl.Result{}, syncApiChecks(ctx, r.Client, r.Recorder, &deployment, deploymentLabels(&deployment), nil)
+	}
+
+	apiCheckResources, err := r.gatherApiCheckData(&deployment)
+	if err != nil {
+		logger.Error(err, "unable to gather data for the apiCheck resource", "Deployment Name", deployment.Name, "Deployment namespace", deployment.Namespace)
+		r.Recorder.Event(&deployment, corev1.EventTypeWarning, "InvalidAnnotations", err.Error())
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{}, syncApiChecks(ctx, r.Client, r.Recorder, &deployment, deploymentLabels(&deployment), apiCheckResources)
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *DeploymentReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&appsv1.Deployment{}).
+		Complete(r)
+}
+
+// gatherApiCheckData builds the ApiCheck of the health URL annotation
+func (r *DeploymentReconciler) gatherApiCheckData(
+	deployment *appsv1.Deployment,
+) (
+	apiChecks []*checklyv1alpha1.ApiCheck,
+	err error,
+) {
+
+	annotationHealthURL := fmt.Sprintf("%s/health-url", r.ControllerDomain)
+
+	checkAnnotations, err := readApiCheckAnnotations(r.ControllerDomain, deployment.Annotations)
+	if err != nil {
+		return
+	}
+
+	healthURL := deployment.Annotations[annotationHealthURL]
+	if healthURL == "" {
+		err = fmt.Errorf("could not find a value for the health-url annotation, can't continue without one")
+		return
+	}
+
+	parsed, err := url.Parse(healthURL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		err = fmt.Errorf("the health-url annotation must be an absolute http or https URL, got %q", healthURL)
+		return
+	}
+
+	checkAnnotations, included := checkAnnotations.forPath(parsed.Host, parsed.Path)
+	if !included {
+		return
+	}
+
+	ownerRef := *metav1.NewControllerRef(deployment, appsv1.SchemeGroupVersion.WithKind("Deployment"))
+	name := apiCheckName(deployment.Name, parsed.Host, parsed.Path)
+
+	apiChecks = append(apiChecks, buildApiCheck(deployment, ownerRef, deploymentLabels(deployment), checkAnnotations, name, healthURL))
+
+	return
+}
+
+// deploymentLabels are set on the ApiChecks of the deployment to find them again
+func deploymentLabels(deployment *appsv1.Deployment) map[string]string {
+	return map[string]string{"deployment-controller": deployment.Name}
+}
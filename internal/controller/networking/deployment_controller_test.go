@@ -0,0 +1,74 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package networking
+
+import (
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestDeploymentGatherApiCheckData(t *testing.T) {
+	r := &DeploymentReconciler{ControllerDomain: "testing.domain.tld"}
+
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-deployment",
+			Namespace: "default",
+			Annotations: map[string]string{
+				"testing.domain.tld/enabled":    "true",
+				"testing.domain.tld/group":      "foo",
+				"testing.domain.tld/success":    "204",
+				"testing.domain.tld/muted":      "false",
+				"testing.domain.tld/health-url": "https://foo.bar:8443/status/health?verbose=1",
+			},
+		},
+	}
+
+	apiChecks, err := r.gatherApiCheckData(deployment)
+	if err != nil {
+		t.Fatalf("Expected no error, got %e", err)
+	}
+	if len(apiChecks) != 1 {
+		t.Fatalf("Expected %d ApiCheck, got %d", 1, len(apiChecks))
+	}
+
+	apiCheck := apiChecks[0]
+	if apiCheck.Name != "test-deployment-foobar8443-statushealth" {
+		t.Errorf("Expected %s, got %s", "test-deployment-foobar8443-statushealth", apiCheck.Name)
+	}
+	if apiCheck.Spec.Endpoint != "https://foo.bar:8443/status/health?verbose=1" {
+		t.Errorf("Expected %s, got %s", "https://foo.bar:8443/status/health?verbose=1", apiCheck.Spec.Endpoint)
+	}
+	if apiCheck.Spec.Group != "foo" || apiCheck.Spec.Success != "204" || apiCheck.Spec.Muted {
+		t.Errorf("Unexpected spec %v", apiCheck.Spec)
+	}
+	if apiCheck.Labels["deployment-controller"] != deployment.Name {
+		t.Errorf("Expected label %s, got %v", deployment.Name, apiCheck.Labels)
+	}
+	if len(apiCheck.OwnerReferences) != 1 || apiCheck.OwnerReferences[0].Kind != "Deployment" {
+		t.Errorf("Unexpected owner references %v", apiCheck.OwnerReferences)
+	}
+
+	for _, invalid := range []string{"", "foo.bar/health", "ftp://foo.bar/health", "https:///health"} {
+		deployment.Annotations["testing.domain.tld/health-url"] = invalid
+		if _, err := r.gatherApiCheckData(deployment); err == nil {
+			t.Errorf("Expected error for %q, got none", invalid)
+		}
+	}
+}
@@ -0,0 +1,238 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package networking
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	checklyv1alpha1 "github.com/checkly/checkly-operator/api/checkly/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// HTTPRouteReconciler reconciles a Gateway API HTTPRoute object, it generates ApiChecks the same way as the IngressReconciler
+type HTTPRouteReconciler struct {
+	client.Client
+	Scheme           *runtime.Scheme
+	ControllerDomain string
+	Recorder         record.EventRecorder
+}
+
+//+kubebuilder:rbac:groups=gateway.networking.k8s.io,resources=httproutes,verbs=get;list;watch;update;patch
+//+kubebuilder:rbac:groups=gateway.networking.k8s.io,resources=httproutes/finalizers,verbs=update
+//+kubebuilder:rbac:groups=k8s.checklyhq.com,resources=apichecks,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+
+// Reconcile is part of the main kubernetes reconciliation loop which aims to
+// move the current state of the cluster closer to the desired state.
+func (r *HTTPRouteReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+	logger.Info("Reconciler started")
+
+	// ////////////////////////////////
+	// Setup
+	// ///////////////////////////////
+	route := gatewayv1.HTTPRoute{}
+	annotationEnabled := fmt.Sprintf("%s/enabled", r.ControllerDomain)
+	checklyFinalizer := fmt.Sprintf("%s/finalizer", r.ControllerDomain)
+	// Check if the HTTPRoute object is still present
+	err := r.Get(ctx, req.NamespacedName, &route)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			logger.Info("HTTPRoute got deleted")
+			return ctrl.Result{}, nil
+		}
+		logger.Error(err, "Can't read the HTTPRoute object")
+		return ctrl.Result{}, err
+	}
+	logger.Info("HTTPRoute Object found")
+
+	// ////////////////////////////////
+	// Delete Logic
+	// ///////////////////////////////
+
+	// Disabled or deleted routes don't need valid annotations, all ApiChecks generated from them are removed
+	value, exists := route.Annotations[annotationEnabled]
+	if !exists || value == "false" || route.GetDeletionTimestamp() != nil {
+		logger.Info("Deleting the ApiChecks of the HTTPRoute as we're not handling it", "HTTPRoute Name", route.Name, "HTTPRoute namespace", route.Namespace)
+
+		err = syncApiChecks(ctx, r.Client, r.Recorder, &route, httpRouteLabels(&route), nil)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+
+		if controllerutil.ContainsFinalizer(&route, checklyFinalizer) {
+			logger.Info("Deleting finalizer", "HTTPRoute Name", route.Name, "HTTPRoute namespace", route.Namespace)
+			controllerutil.RemoveFinalizer(&route, checklyFinalizer)
+			err = r.Update(ctx, &route)
+			if err != nil {
+				logger.Error(err, "Failed to delete finalizer", "HTTPRoute Name", route.Name, "HTTPRoute namespace", route.Namespace)
+				return ctrl.Result{}, err
+			}
+			logger.Info("Successfully deleted finalizer", "HTTPRoute Name", route.Name, "HTTPRoute namespace", route.Namespace)
+		}
+		return ctrl.Result{}, nil
+	}
+
+	// Gather data for the checkly check
+	logger.Info("Gathering data for the check")
+	apiCheckResources, err := r.gatherApiCheckData(&route)
+	if err != nil {
+		logger.Error(err, "unable to gather data for the apiCheck resource", "HTTPRoute Name", route.Name, "HTTPRoute namespace", route.Namespace)
+		r.Recorder.Event(&route, corev1.EventTypeWarning, "InvalidAnnotations", err.Error())
+		return ctrl.Result{}, err
+	}
+
+	// /////////////////////////////
+	// Finalizer logic
+	// ////////////////////////////
+	if !controllerutil.ContainsFinalizer(&route, checklyFinalizer) {
+		controllerutil.AddFinalizer(&route, checklyFinalizer)
+		err = r.Update(ctx, &route)
+		if err != nil {
+			logger.Error(err, "Failed to update HTTPRoute finalizer")
+			return ctrl.Result{}, err
+		}
+		logger.Info("Added finalizer", "HTTPRoute", route.Name, "HTTPRoute namespace", route.Namespace)
+		return ctrl.Result{}, nil
+	}
+
+	// /////////////////////////////
+	// Update/Create logic
+	// ////////////////////////////
+
+	err = syncApiChecks(ctx, r.Client, r.Recorder, &route, httpRouteLabels(&route), apiCheckResources)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *HTTPRouteReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&gatewayv1.HTTPRoute{}).
+		Complete(r)
+}
+
+// gatherApiCheckData builds an ApiCheck for every hostname and path match of the route
+func (r *HTTPRouteReconciler) gatherApiCheckData(
+	route *gatewayv1.HTTPRoute,
+) (
+	apiChecks []*checklyv1alpha1.ApiCheck,
+	err error,
+) {
+
+	checkAnnotations, err := readApiCheckAnnotations(r.ControllerDomain, route.Annotations)
+	if err != nil {
+		return
+	}
+
+	// Get the host(s), wildcard hostnames can't be called
+	var hosts []string
+	if checkAnnotations.endpoint != "" {
+		hosts = append(hosts, checkAnnotations.endpoint)
+	} else {
+		for _, hostname := range route.Spec.Hostnames {
+			if !strings.HasPrefix(string(hostname), "*") {
+				hosts = append(hosts, string(hostname))
+			}
+		}
+	}
+	if len(hosts) == 0 {
+		err = fmt.Errorf("could not find a hostname, set spec.hostnames or the endpoint annotation")
+		return
+	}
+
+	// Get the path(s)
+	var paths []string
+	if checkAnnotations.hasPath {
+		paths = append(paths, checkAnnotations.path)
+	} else {
+		paths = httpRoutePaths(route)
+	}
+
+	ownerRef := *metav1.NewControllerRef(route, gatewayv1.SchemeGroupVersion.WithKind("HTTPRoute"))
+	labels := httpRouteLabels(route)
+
+	for _, host := range hosts {
+		for _, path := range paths {
+			if apiCheck := newApiCheck(route, ownerRef, labels, checkAnnotations, "https", host, path); apiCheck != nil {
+				apiChecks = append(apiChecks, apiCheck)
+			}
+		}
+	}
+
+	return
+}
+
+// httpRoutePaths returns the paths of the Exact and PathPrefix matches of the route rules, regular expressions can't be
+// turned into an URL and are skipped. Rules and matches without a path match "/".
+func httpRoutePaths(route *gatewayv1.HTTPRoute) (paths []string) {
+	seen := make(map[string]bool)
+	addPath := func(path string) {
+		if !seen[path] {
+			seen[path] = true
+			paths = append(paths, path)
+		}
+	}
+
+	for _, rule := range route.Spec.Rules {
+		if len(rule.Matches) == 0 {
+			addPath("/")
+			continue
+		}
+
+		for _, match := range rule.Matches {
+			if match.Path == nil {
+				addPath("/")
+				continue
+			}
+			if match.Path.Type != nil && *match.Path.Type == gatewayv1.PathMatchRegularExpression {
+				continue
+			}
+			if match.Path.Value == nil || *match.Path.Value == "" {
+				addPath("/")
+				continue
+			}
+			addPath(*match.Path.Value)
+		}
+	}
+
+	// A route without rules sends every request to the backends of its parents
+	if len(route.Spec.Rules) == 0 {
+		addPath("/")
+	}
+
+	return
+}
+
+// httpRouteLabels are set on the ApiChecks of the route to find them again
+func httpRouteLabels(route *gatewayv1.HTTPRoute) map[string]string {
+	return map[string]string{"httproute-controller": route.Name}
+}
@@ -0,0 +1,216 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package networking
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	checklyv1alpha1 "github.com/checkly/checkly-operator/api/checkly/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func testHTTPRoute(annotations map[string]string) *gatewayv1.HTTPRoute {
+	pathPrefix := gatewayv1.PathMatchPathPrefix
+	exact := gatewayv1.PathMatchExact
+	regex := gatewayv1.PathMatchRegularExpression
+	api := "/api"
+	health := "/healthz"
+	pattern := "/v[0-9]+"
+
+	return &gatewayv1.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "test-route",
+			Namespace:   "default",
+			UID:         "1234",
+			Annotations: annotations,
+		},
+		Spec: gatewayv1.HTTPRouteSpec{
+			Hostnames: []gatewayv1.Hostname{"foo.bar", "*.bar"},
+			Rules: []gatewayv1.HTTPRouteRule{
+				{
+					Matches: []gatewayv1.HTTPRouteMatch{
+						{Path: &gatewayv1.HTTPPathMatch{Type: &pathPrefix, Value: &api}},
+						{Path: &gatewayv1.HTTPPathMatch{Type: &exact, Value: &health}},
+						{Path: &gatewayv1.HTTPPathMatch{Type: &regex, Value: &pattern}},
+					},
+				},
+				{
+					// Same path with a different header match
+					Matches: []gatewayv1.HTTPRouteMatch{
+						{Path: &gatewayv1.HTTPPathMatch{Type: &pathPrefix, Value: &api}},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestHTTPRouteGatherApiCheckData(t *testing.T) {
+	r := &HTTPRouteReconciler{ControllerDomain: "testing.domain.tld"}
+
+	route := testHTTPRoute(map[string]string{
+		"testing.domain.tld/enabled": "true",
+		"testing.domain.tld/group":   "foo",
+		"testing.domain.tld/success": "201",
+	})
+
+	apiChecks, err := r.gatherApiCheckData(route)
+	if err != nil {
+		t.Fatalf("Expected no error, got %e", err)
+	}
+
+	var names, endpoints []string
+	for _, apiCheck := range apiChecks {
+		names = append(names, apiCheck.Name)
+		endpoints = append(endpoints, apiCheck.Spec.Endpoint)
+
+		if apiCheck.Spec.Group != "foo" || apiCheck.Spec.Success != "201" || !apiCheck.Spec.Muted {
+			t.Errorf("Unexpected spec %v", apiCheck.Spec)
+		}
+		if apiCheck.Labels["httproute-controller"] != route.Name {
+			t.Errorf("Expected label %s, got %v", route.Name, apiCheck.Labels)
+		}
+		if len(apiCheck.OwnerReferences) != 1 || apiCheck.OwnerReferences[0].Kind != "HTTPRoute" || apiCheck.OwnerReferences[0].UID != route.UID {
+			t.Errorf("Unexpected owner references %v", apiCheck.OwnerReferences)
+		}
+	}
+
+	expectedNames := []string{"test-route-foobar-api", "test-route-foobar-healthz"}
+	if !reflect.DeepEqual(names, expectedNames) {
+		t.Errorf("Expected %v, got %v", expectedNames, names)
+	}
+	expectedEndpoints := []string{"https://foo.bar/api", "https://foo.bar/healthz"}
+	if !reflect.DeepEqual(endpoints, expectedEndpoints) {
+		t.Errorf("Expected %v, got %v", expectedEndpoints, endpoints)
+	}
+
+	// Annotations replace the hostnames and paths
+	route.Annotations["testing.domain.tld/endpoint"] = "baz.qux"
+	route.Annotations["testing.domain.tld/path"] = "/status"
+	apiChecks, err = r.gatherApiCheckData(route)
+	if err != nil {
+		t.Fatalf("Expected no error, got %e", err)
+	}
+	if len(apiChecks) != 1 || apiChecks[0].Spec.Endpoint != "https://baz.qux/status" {
+		t.Errorf("Expected a single check for %s, got %v", "https://baz.qux/status", apiChecks)
+	}
+
+	// Only wildcard hostnames
+	route = testHTTPRoute(map[string]string{"testing.domain.tld/group": "foo"})
+	route.Spec.Hostnames = []gatewayv1.Hostname{"*.bar"}
+	if _, err := r.gatherApiCheckData(route); err == nil {
+		t.Error("Expected error, got none")
+	}
+
+	// Missing group
+	route = testHTTPRoute(map[string]string{})
+	if _, err := r.gatherApiCheckData(route); err == nil {
+		t.Error("Expected error, got none")
+	}
+}
+
+func TestHTTPRoutePaths(t *testing.T) {
+	route := &gatewayv1.HTTPRoute{}
+	if paths := httpRoutePaths(route); !reflect.DeepEqual(paths, []string{"/"}) {
+		t.Errorf("Expected %v, got %v", []string{"/"}, paths)
+	}
+
+	route.Spec.Rules = []gatewayv1.HTTPRouteRule{
+		{},
+		{Matches: []gatewayv1.HTTPRouteMatch{{}}},
+	}
+	if paths := httpRoutePaths(route); !reflect.DeepEqual(paths, []string{"/"}) {
+		t.Errorf("Expected %v, got %v", []string{"/"}, paths)
+	}
+}
+
+func TestHTTPRouteReconcile(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := checklyv1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := gatewayv1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+
+	route := testHTTPRoute(map[string]string{
+		"testing.domain.tld/enabled": "true",
+		"testing.domain.tld/group":   "foo",
+	})
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(route).Build()
+	r := &HTTPRouteReconciler{
+		Client:           c,
+		Scheme:           scheme,
+		ControllerDomain: "testing.domain.tld",
+		Recorder:         record.NewFakeRecorder(10),
+	}
+
+	ctx := context.Background()
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: route.Name, Namespace: route.Namespace}}
+
+	// Finalizer, then the ApiChecks
+	for i := 0; i < 2; i++ {
+		if _, err := r.Reconcile(ctx, req); err != nil {
+			t.Fatalf("Expected no error, got %e", err)
+		}
+	}
+
+	apiChecks := &checklyv1alpha1.ApiCheckList{}
+	if err := c.List(ctx, apiChecks, client.MatchingLabels{"httproute-controller": route.Name}); err != nil {
+		t.Fatal(err)
+	}
+	if len(apiChecks.Items) != 2 {
+		t.Errorf("Expected %d ApiChecks, got %d", 2, len(apiChecks.Items))
+	}
+
+	// Disabling the route removes the ApiChecks and the finalizer
+	if err := c.Get(ctx, req.NamespacedName, route); err != nil {
+		t.Fatal(err)
+	}
+	if len(route.Finalizers) != 1 {
+		t.Errorf("Expected finalizer, got %v", route.Finalizers)
+	}
+	route.Annotations["testing.domain.tld/enabled"] = "false"
+	if err := c.Update(ctx, route); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("Expected no error, got %e", err)
+	}
+
+	if err := c.List(ctx, apiChecks, client.MatchingLabels{"httproute-controller": route.Name}); err != nil {
+		t.Fatal(err)
+	}
+	if len(apiChecks.Items) != 0 {
+		t.Errorf("Expected no ApiChecks, got %d", len(apiChecks.Items))
+	}
+	if err := c.Get(ctx, req.NamespacedName, route); err != nil {
+		t.Fatal(err)
+	}
+	if len(route.Finalizers) != 0 {
+		t.Errorf("Expected no finalizer, got %v", route.Finalizers)
+	}
+}
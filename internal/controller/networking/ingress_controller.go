@@ -19,13 +19,14 @@ package networking
 import (
 	"context"
 	"fmt"
-	"strings"
 
 	checklyv1alpha1 "github.com/checkly/checkly-operator/api/checkly/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
 	networkingv1 "k8s.io/api/networking/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
@@ -37,6 +38,7 @@ type IngressReconciler struct {
 	client.Client
 	Scheme           *runtime.Scheme
 	ControllerDomain string
+	Recorder         record.EventRecorder
 }
 
 //+kubebuilder:rbac:groups=networking.k8s.io,resources=ingresses,verbs=get;list;watch;update;patch
@@ -44,6 +46,7 @@ type IngressReconciler struct {
 //+kubebuilder:rbac:groups=networking.k8s.io,resources=ingresses/finalizers,verbs=update
 //+kubebuilder:rbac:groups=k8s.checklyhq.com,resources=apichecks,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=k8s.checklyhq.com,resources=apichecks/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups="",resources=events,verbs=create;patch
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -78,6 +81,9 @@ func (r *IngressReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 	apiCheckResources, err := r.gatherApiCheckData(&ingress)
 	if err != nil {
 		logger.Error(err, "unable to gather data for the apiCheck resource", "Ingress Name", ingress.Name, "Ingress namespace", ingress.Namespace)
+		if ingress.Annotations[annotationEnabled] == "true" {
+			r.Recorder.Event(&ingress, corev1.EventTypeWarning, "InvalidAnnotations", err.Error())
+		}
 		return ctrl.Result{}, err
 	}
 
@@ -85,7 +91,7 @@ func (r *IngressReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 	if value, exists := ingress.Annotations[annotationEnabled]; !exists || value == "false" {
 		logger.Info("Checking to see if we need to delete any resources as we're not handling this ingress", "Ingress Name", ingress.Name, "Ingress namespace", ingress.Namespace)
 
-		r.deleteIngressApiChecks(ctx, req, apiCheckResources, ingress)
+		removeApiChecks(ctx, r.Client, apiCheckResources)
 
 		if ingress.GetDeletionTimestamp() == nil {
 			return ctrl.Result{}, nil
@@ -99,7 +105,7 @@ func (r *IngressReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 		if controllerutil.ContainsFinalizer(&ingress, checklyFinalizer) {
 			logger.Info("Finalizer present, need to delete ApiCheck first", "Ingress Name", ingress.Name, "Ingress namespace", ingress.Namespace)
 
-			r.deleteIngressApiChecks(ctx, req, apiCheckResources, ingress)
+			removeApiChecks(ctx, r.Client, apiCheckResources)
 
 			// Delete finalizer logic
 			logger.Info("Deleting finalizer", "Ingress Name", ingress.Name, "Ingress namespace", ingress.Namespace)
@@ -132,43 +138,11 @@ func (r *IngressReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 	// Update/Create logic
 	// ////////////////////////////
 
-	newApiChecks, deleteApiChecks, updateApiChecks, err := r.compareApiChecks(ctx, &ingress, apiCheckResources)
+	err = syncApiChecks(ctx, r.Client, r.Recorder, &ingress, ingressLabels(&ingress), apiCheckResources)
 	if err != nil {
-		logger.Error(err, "Failed to list existing API checks")
 		return ctrl.Result{}, err
 	}
 
-	// Create new Api Checks
-	for _, apiCheck := range newApiChecks {
-		logger.Info("Creating ApiCheck", "ApiCheck Name", apiCheck.Name, "ApiCheck spec", apiCheck.Spec)
-		err = r.Create(ctx, apiCheck)
-		if err != nil {
-			logger.Error(err, "Failed to create ApiCheck", "APICheck name", apiCheck.Name, "Namespace", apiCheck.Namespace, "Ingress name", ingress.Name, "Ingress namespace", ingress.Namespace)
-			return ctrl.Result{}, err
-		}
-	}
-
-	// Update API checks
-	for _, apiCheck := range updateApiChecks {
-		logger.Info("Updating ApiCheck", "ApiCheck Name", apiCheck.Name)
-		err = r.Update(ctx, apiCheck)
-		if err != nil {
-			logger.Error(err, "Failed to update APICheck resource", "APICheck name", apiCheck.Name, "Namespace", apiCheck.Namespace, "Ingress name", ingress.Name, "Ingress namespace", ingress.Namespace)
-			return ctrl.Result{}, err
-		}
-	}
-
-	// Delete old API checks
-	for _, apiCheck := range deleteApiChecks {
-
-		logger.Info("Delete ApiCheck", "ApiCheck Name", apiCheck.Name)
-		err = r.Delete(ctx, apiCheck)
-		if err != nil {
-			logger.Error(err, "Failed to delete ApiCheck resource", "APICheck name", apiCheck.Name, "Namespace", apiCheck.Namespace, "Ingress name", ingress.Name, "Ingress namespace", ingress.Namespace)
-			return ctrl.Result{}, err
-		}
-	}
-
 	return ctrl.Result{}, nil
 }
 
@@ -186,42 +160,19 @@ func (r *IngressReconciler) gatherApiCheckData(
 	err error,
 ) {
 
-	annotationHost := r.ControllerDomain
-	annotationPath := fmt.Sprintf("%s/path", annotationHost)
-	annotationEndpoint := fmt.Sprintf("%s/endpoint", annotationHost)
-	annotationSuccess := fmt.Sprintf("%s/success", annotationHost)
-	annotationGroup := fmt.Sprintf("%s/group", annotationHost)
-	annotationMuted := fmt.Sprintf("%s/muted", annotationHost)
-
-	// Expected success code
-	success := ingress.Annotations[annotationSuccess]
-	if success == "" {
-		success = "200"
-	}
-
-	// Group
-	group := ingress.Annotations[annotationGroup]
-	if group == "" {
-		err = fmt.Errorf("could not find a value for the group annotation, can't continue without one")
+	checkAnnotations, err := readApiCheckAnnotations(r.ControllerDomain, ingress.Annotations)
+	if err != nil {
 		return
 	}
 
-	// Muted
-	var muted bool
-	if ingress.Annotations[annotationMuted] == "false" {
-		muted = false
-	} else {
-		muted = true
-	}
-
-	labels := make(map[string]string)
-	labels["ingress-controller"] = ingress.Name
+	ownerRef := *metav1.NewControllerRef(ingress, networkingv1.SchemeGroupVersion.WithKind("Ingress"))
+	labels := ingressLabels(ingress)
 
 	// Get the host(s) and path(s) from the ingress object
 	for _, rule := range ingress.Spec.Rules {
 
 		// Get the host
-		host := ingress.Annotations[annotationEndpoint]
+		host := checkAnnotations.endpoint
 		if host == "" {
 			host = rule.Host
 		}
@@ -229,8 +180,8 @@ func (r *IngressReconciler) gatherApiCheckData(
 		// Get the path(s)
 		var paths []string
 
-		if value, exists := ingress.Annotations[annotationPath]; exists {
-			paths = append(paths, value)
+		if checkAnnotations.hasPath {
+			paths = append(paths, checkAnnotations.path)
 		} else if rule.HTTP == nil || rule.HTTP.Paths == nil {
 			paths = append(paths, "/")
 		} else {
@@ -244,39 +195,9 @@ func (r *IngressReconciler) gatherApiCheckData(
 		}
 
 		for _, path := range paths {
-			// Replace path /
-			path = strings.TrimPrefix(path, "/")
-
-			// Set apiCheck Name
-			checkName := fmt.Sprintf("%s-%s-%s", ingress.Name, host, path)
-			checkName = strings.Replace(checkName, "/", "", -1)
-			checkName = strings.Replace(checkName, ".", "", -1)
-			checkName = strings.Trim(checkName, "-")
-
-			// Set endpoint
-			endpoint := fmt.Sprintf("https://%s/%s", host, path)
-
-			// Construct ApiCheck Spec
-			apiCheckSpec := &checklyv1alpha1.ApiCheckSpec{
-				Endpoint: endpoint,
-				Group:    group,
-				Success:  success,
-				Muted:    muted,
-			}
-
-			newApiCheck := &checklyv1alpha1.ApiCheck{
-				ObjectMeta: metav1.ObjectMeta{
-					Name:      checkName,
-					Namespace: ingress.Namespace,
-					OwnerReferences: []metav1.OwnerReference{
-						*metav1.NewControllerRef(ingress, networkingv1.SchemeGroupVersion.WithKind("ingress")),
-					},
-					Labels: labels,
-				},
-				Spec: *apiCheckSpec,
+			if apiCheck := newApiCheck(ingress, ownerRef, labels, checkAnnotations, "https", host, path); apiCheck != nil {
+				apiChecks = append(apiChecks, apiCheck)
 			}
-
-			apiChecks = append(apiChecks, newApiCheck)
 		}
 	}
 
@@ -284,89 +205,7 @@ func (r *IngressReconciler) gatherApiCheckData(
 	return
 }
 
-func (r *IngressReconciler) compareApiChecks(
-	ctx context.Context,
-	ingress *networkingv1.Ingress,
-	ingressApiChecks []*checklyv1alpha1.ApiCheck,
-) (
-	newApiChecks []*checklyv1alpha1.ApiCheck,
-	deleteApiChecks []*checklyv1alpha1.ApiCheck,
-	updateApiChecks []*checklyv1alpha1.ApiCheck,
-	err error,
-) {
-
-	logger := log.FromContext(ctx)
-
-	var existingApiChecks checklyv1alpha1.ApiCheckList
-
-	err = r.List(ctx, &existingApiChecks, client.InNamespace(ingress.Namespace), client.MatchingLabels{"ingress-controller": ingress.Name})
-	if err != nil {
-		return
-	}
-
-	existingApiChecksMap := make(map[string]checklyv1alpha1.ApiCheck)
-	for _, existingApiCheck := range existingApiChecks.Items {
-		existingApiChecksMap[existingApiCheck.Name] = existingApiCheck
-	}
-
-	newApiChecksMap := make(map[string]*checklyv1alpha1.ApiCheck)
-	for _, ingressApiCheck := range ingressApiChecks {
-		newApiChecksMap[ingressApiCheck.Name] = ingressApiCheck
-	}
-
-	// Compare items
-	for _, existingApiCheck := range existingApiChecksMap {
-		newApiCheck, exists := newApiChecksMap[existingApiCheck.Name]
-		if exists {
-			if existingApiCheck.Spec == newApiCheck.Spec {
-				logger.Info("ApiCheck data is identical, no need for update", "ApiCheck Name", existingApiCheck.Name)
-			} else {
-				logger.Info(
-					"ApiCheck data is not identical, update needed", "ApiCheck Name", existingApiCheck.Name, "old spec", existingApiCheck.Spec, "new spec", newApiCheck.Spec)
-				updateApiChecks = append(updateApiChecks, newApiCheck)
-			}
-
-			// Remove items from new api checks map
-			delete(newApiChecksMap, existingApiCheck.Name)
-		} else {
-			logger.Info("ApiCheck is not needed anymore, delete", "ApiCheck Name", existingApiCheck.Name)
-			deleteApiChecks = append(deleteApiChecks, &existingApiCheck)
-		}
-	}
-
-	// Loop over remaining items and add them to the new checks list, these will be created
-	for _, newApiCheck := range newApiChecksMap {
-		newApiChecks = append(newApiChecks, newApiCheck)
-	}
-
-	return
-}
-
-func (r *IngressReconciler) deleteIngressApiChecks(
-	ctx context.Context,
-	req ctrl.Request,
-	apiCheckResources []*checklyv1alpha1.ApiCheck,
-	ingress networkingv1.Ingress,
-) {
-
-	logger := log.FromContext(ctx)
-
-	for _, apiCheckResource := range apiCheckResources {
-
-		logger.Info("Checking if ApiCheck was created", "Ingress Name", ingress.Name, "Ingress namespace", ingress.Namespace)
-		err := r.Get(ctx, req.NamespacedName, apiCheckResource)
-		if err != nil {
-			logger.Info("ApiCheck resource is not present, we don't need to do anything", "Ingress Name", ingress.Name, "Ingress namespace", ingress.Namespace)
-			continue
-		}
-
-		logger.Info("ApiCheck resource is present, we need to delete it", "Ingress Name", ingress.Name, "Ingress namespace", ingress.Namespace)
-		err = r.Delete(ctx, apiCheckResource)
-		if err != nil {
-			logger.Error(err, "Failed to delete ApiCheck", "Name", apiCheckResource.Name, "Namespace", apiCheckResource.Namespace)
-			continue
-		}
-
-		logger.Info("ApiCheck resource deleted successfully", apiCheckResource.Name, "Namespace", apiCheckResource.Namespace)
-	}
+// ingressLabels are set on the ApiChecks of the ingress to find them again
+func ingressLabels(ingress *networkingv1.Ingress) map[string]string {
+	return map[string]string{"ingress-controller": ingress.Name}
 }
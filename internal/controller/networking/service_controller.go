@@ -0,0 +1,188 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package networking
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	checklyv1alpha1 "github.com/checkly/checkly-operator/api/checkly/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// ServiceReconciler generates ApiChecks for the addresses and ports of LoadBalancer Services, the ApiChecks are
+// garbage collected with the Service
+type ServiceReconciler struct {
+	client.Client
+	Scheme           *runtime.Scheme
+	ControllerDomain string
+	Recorder         record.EventRecorder
+}
+
+//+kubebuilder:rbac:groups="",resources=services,verbs=get;list;watch
+//+kubebuilder:rbac:groups=k8s.checklyhq.com,resources=apichecks,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+
+// Reconcile is part of the main kubernetes reconciliation loop which aims to
+// move the current state of the cluster closer to the desired state.
+func (r *ServiceReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	service := corev1.Service{}
+	annotationEnabled := fmt.Sprintf("%s/enabled", r.ControllerDomain)
+	err := r.Get(ctx, req.NamespacedName, &service)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		logger.Error(err, "Can't read the Service object")
+		return ctrl.Result{}, err
+	}
+
+	// Remove the ApiChecks of disabled services
+	if value, exists := service.Annotations[annotationEnabled]; !exists || value == "false" {
+		return ctrl.Result{}, syncApiChecks(ctx, r.Client, r.Recorder, &service, serviceLabels(&service), nil)
+	}
+
+	apiCheckResources, err := r.gatherApiCheckData(&service)
+	if err != nil {
+		logger.Error(err, "unable to gather data for the apiCheck resource", "Service Name", service.Name, "Service namespace", service.Namespace)
+		r.Recorder.Event(&service, corev1.EventTypeWarning, "InvalidAnnotations", err.Error())
+		return ctrl.Result{}, err
+	}
+
+	// The Service is reconciled again once the load balancer address is in the status
+	if len(apiCheckResources) == 0 && r.waitingForAddress(&service) {
+		logger.Info("Waiting for the load balancer address", "Service Name", service.Name, "Service namespace", service.Namespace)
+		return ctrl.Result{}, nil
+	}
+
+	return ctrl.Result{}, syncApiChecks(ctx, r.Client, r.Recorder, &service, serviceLabels(&service), apiCheckResources)
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *ServiceReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&corev1.Service{}).
+		Complete(r)
+}
+
+// gatherApiCheckData builds an ApiCheck for every load balancer address and TCP port of the service,
+// no ApiChecks are returned while the load balancer has no address
+func (r *ServiceReconciler) gatherApiCheckData(
+	service *corev1.Service,
+) (
+	apiChecks []*checklyv1alpha1.ApiCheck,
+	err error,
+) {
+
+	if service.Spec.Type != corev1.ServiceTypeLoadBalancer {
+		err = fmt.Errorf("only Services of type %s are supported, got %s", corev1.ServiceTypeLoadBalancer, service.Spec.Type)
+		return
+	}
+
+	checkAnnotations, err := readApiCheckAnnotations(r.ControllerDomain, service.Annotations)
+	if err != nil {
+		return
+	}
+
+	// Get the host(s), the endpoint annotation is used for load balancers behind an external DNS name
+	var hosts []string
+	if checkAnnotations.endpoint != "" {
+		hosts = append(hosts, checkAnnotations.endpoint)
+	} else {
+		for _, ingress := range service.Status.LoadBalancer.Ingress {
+			if ingress.Hostname != "" {
+				hosts = append(hosts, ingress.Hostname)
+			} else if ingress.IP != "" {
+				hosts = append(hosts, ingress.IP)
+			}
+		}
+	}
+
+	path := "/"
+	if checkAnnotations.hasPath {
+		path = checkAnnotations.path
+	}
+
+	ownerRef := *metav1.NewControllerRef(service, corev1.SchemeGroupVersion.WithKind("Service"))
+	labels := serviceLabels(service)
+
+	for _, host := range hosts {
+		for _, port := range service.Spec.Ports {
+			if port.Protocol != "" && port.Protocol != corev1.ProtocolTCP {
+				continue
+			}
+			scheme := servicePortScheme(port)
+			if apiCheck := newApiCheck(service, ownerRef, labels, checkAnnotations, scheme, serviceHost(host, scheme, port.Port), path); apiCheck != nil {
+				apiChecks = append(apiChecks, apiCheck)
+			}
+		}
+	}
+
+	return
+}
+
+// waitingForAddress returns true while the load balancer has no address and the endpoint annotation isn't set
+func (r *ServiceReconciler) waitingForAddress(service *corev1.Service) bool {
+	annotationEndpoint := fmt.Sprintf("%s/endpoint", r.ControllerDomain)
+	return len(service.Status.LoadBalancer.Ingress) == 0 && service.Annotations[annotationEndpoint] == ""
+}
+
+// servicePortScheme returns the URL scheme of the port, read from the appProtocol or guessed from the port number
+func servicePortScheme(port corev1.ServicePort) string {
+	if port.AppProtocol != nil {
+		switch strings.ToLower(*port.AppProtocol) {
+		case "https":
+			return "https"
+		case "http":
+			return "http"
+		}
+	}
+
+	if port.Port == 443 || port.Port == 8443 {
+		return "https"
+	}
+	return "http"
+}
+
+// serviceHost adds the port to the host unless it's the default port of the scheme
+func serviceHost(host string, scheme string, port int32) string {
+	if (scheme == "https" && port == 443) || (scheme == "http" && port == 80) {
+		if strings.Contains(host, ":") {
+			// IPv6 address
+			return "[" + host + "]"
+		}
+		return host
+	}
+	return net.JoinHostPort(host, strconv.Itoa(int(port)))
+}
+
+// serviceLabels are set on the ApiChecks of the service to find them again
+func serviceLabels(service *corev1.Service) map[string]string {
+	return map[string]string{"service-controller": service.Name}
+}
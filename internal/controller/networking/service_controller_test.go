@@ -0,0 +1,221 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package networking
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"testing"
+
+	checklyv1alpha1 "github.com/checkly/checkly-operator/api/checkly/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func testService(annotations map[string]string) *corev1.Service {
+	https := "https"
+	return &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "test-service",
+			Namespace:   "default",
+			UID:         "1234",
+			Annotations: annotations,
+		},
+		Spec: corev1.ServiceSpec{
+			Type: corev1.ServiceTypeLoadBalancer,
+			Ports: []corev1.ServicePort{
+				{Name: "http", Port: 80, Protocol: corev1.ProtocolTCP},
+				{Name: "admin", Port: 9443, AppProtocol: &https},
+				{Name: "dns", Port: 53, Protocol: corev1.ProtocolUDP},
+			},
+		},
+		Status: corev1.ServiceStatus{
+			LoadBalancer: corev1.LoadBalancerStatus{
+				Ingress: []corev1.LoadBalancerIngress{
+					{Hostname: "lb.example.com"},
+					{IP: "10.0.0.1"},
+				},
+			},
+		},
+	}
+}
+
+func TestServiceGatherApiCheckData(t *testing.T) {
+	r := &ServiceReconciler{ControllerDomain: "testing.domain.tld"}
+
+	service := testService(map[string]string{
+		"testing.domain.tld/enabled": "true",
+		"testing.domain.tld/group":   "foo",
+		"testing.domain.tld/path":    "/healthz",
+	})
+
+	apiChecks, err := r.gatherApiCheckData(service)
+	if err != nil {
+		t.Fatalf("Expected no error, got %e", err)
+	}
+
+	endpoints := make(map[string]string)
+	for _, apiCheck := range apiChecks {
+		endpoints[apiCheck.Name] = apiCheck.Spec.Endpoint
+		if apiCheck.Spec.Group != "foo" || apiCheck.Labels["service-controller"] != service.Name {
+			t.Errorf("Unexpected ApiCheck %v", apiCheck)
+		}
+		if len(apiCheck.OwnerReferences) != 1 || apiCheck.OwnerReferences[0].Kind != "Service" {
+			t.Errorf("Unexpected owner references %v", apiCheck.OwnerReferences)
+		}
+	}
+
+	expected := map[string]string{
+		"test-service-lbexamplecom-healthz":     "http://lb.example.com/healthz",
+		"test-service-lbexamplecom9443-healthz": "https://lb.example.com:9443/healthz",
+		"test-service-10001-healthz":            "http://10.0.0.1/healthz",
+		"test-service-100019443-healthz":        "https://10.0.0.1:9443/healthz",
+	}
+	if !reflect.DeepEqual(endpoints, expected) {
+		t.Errorf("Expected %v, got %v", expected, endpoints)
+	}
+
+	// No address yet
+	service.Status.LoadBalancer.Ingress = nil
+	apiChecks, err = r.gatherApiCheckData(service)
+	if err != nil || len(apiChecks) != 0 {
+		t.Errorf("Expected no ApiChecks, got %v, %v", apiChecks, err)
+	}
+
+	// The endpoint annotation replaces the load balancer addresses
+	service.Annotations["testing.domain.tld/endpoint"] = "foo.bar"
+	apiChecks, err = r.gatherApiCheckData(service)
+	if err != nil || len(apiChecks) != 2 || apiChecks[0].Spec.Endpoint != "http://foo.bar/healthz" {
+		t.Errorf("Expected ApiChecks for %s, got %v, %v", "foo.bar", apiChecks, err)
+	}
+
+	service.Spec.Type = corev1.ServiceTypeClusterIP
+	if _, err := r.gatherApiCheckData(service); err == nil {
+		t.Error("Expected error, got none")
+	}
+}
+
+func TestServiceHost(t *testing.T) {
+	tests := []struct {
+		host     string
+		scheme   string
+		port     int32
+		expected string
+	}{
+		{"foo.bar", "https", 443, "foo.bar"},
+		{"foo.bar", "http", 443, "foo.bar:443"},
+		{"foo.bar", "http", 8080, "foo.bar:8080"},
+		{"2001:db8::1", "http", 80, "[2001:db8::1]"},
+		{"2001:db8::1", "https", 8443, "[2001:db8::1]:8443"},
+	}
+	for _, test := range tests {
+		if got := serviceHost(test.host, test.scheme, test.port); got != test.expected {
+			t.Errorf("Expected %s, got %s", test.expected, got)
+		}
+	}
+
+	if scheme := servicePortScheme(corev1.ServicePort{Port: 8443}); scheme != "https" {
+		t.Errorf("Expected %s, got %s", "https", scheme)
+	}
+	if scheme := servicePortScheme(corev1.ServicePort{Port: 8080}); scheme != "http" {
+		t.Errorf("Expected %s, got %s", "http", scheme)
+	}
+}
+
+func TestServiceReconcile(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := checklyv1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+
+	service := testService(map[string]string{
+		"testing.domain.tld/enabled": "true",
+		"testing.domain.tld/group":   "foo",
+	})
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(service).WithStatusSubresource(service).Build()
+	r := &ServiceReconciler{
+		Client:           c,
+		Scheme:           scheme,
+		ControllerDomain: "testing.domain.tld",
+		Recorder:         record.NewFakeRecorder(10),
+	}
+
+	ctx := context.Background()
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: service.Name, Namespace: service.Namespace}}
+
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("Expected no error, got %e", err)
+	}
+
+	apiChecks := &checklyv1alpha1.ApiCheckList{}
+	if err := c.List(ctx, apiChecks, client.MatchingLabels{"service-controller": service.Name}); err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, apiCheck := range apiChecks.Items {
+		names = append(names, apiCheck.Name)
+	}
+	sort.Strings(names)
+	expectedNames := []string{"test-service-10001", "test-service-100019443", "test-service-lbexamplecom", "test-service-lbexamplecom9443"}
+	if !reflect.DeepEqual(names, expectedNames) {
+		t.Errorf("Expected %v, got %v", expectedNames, names)
+	}
+
+	// A port is removed
+	if err := c.Get(ctx, req.NamespacedName, service); err != nil {
+		t.Fatal(err)
+	}
+	service.Spec.Ports = service.Spec.Ports[:1]
+	if err := c.Update(ctx, service); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("Expected no error, got %e", err)
+	}
+	if err := c.List(ctx, apiChecks, client.MatchingLabels{"service-controller": service.Name}); err != nil {
+		t.Fatal(err)
+	}
+	if len(apiChecks.Items) != 2 {
+		t.Errorf("Expected %d ApiChecks, got %d", 2, len(apiChecks.Items))
+	}
+
+	// Disabled
+	service.Annotations["testing.domain.tld/enabled"] = "false"
+	if err := c.Update(ctx, service); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("Expected no error, got %e", err)
+	}
+	if err := c.List(ctx, apiChecks, client.MatchingLabels{"service-controller": service.Name}); err != nil {
+		t.Fatal(err)
+	}
+	if len(apiChecks.Items) != 0 {
+		t.Errorf("Expected no ApiChecks, got %d", len(apiChecks.Items))
+	}
+}
@@ -95,6 +95,7 @@ var _ = BeforeSuite(func() {
 		Client:           k8sManager.GetClient(),
 		Scheme:           k8sManager.GetScheme(),
 		ControllerDomain: testControllerDomain,
+		Recorder:         k8sManager.GetEventRecorderFor("ingress-controller"),
 	}).SetupWithManager(k8sManager)
 	Expect(err).ToNot(HaveOccurred())
 
@@ -119,7 +120,7 @@ var _ = BeforeSuite(func() {
 			r.ParseForm()
 			method := r.Method
 			switch method {
-			case "PUT":
+			case "GET", "PUT":
 				w.WriteHeader(http.StatusOK)
 				w.Header().Set("Content-Type", "application/json")
 				resp := make(map[string]string)
@@ -142,7 +143,7 @@ var _ = BeforeSuite(func() {
 			r.ParseForm()
 			method := r.Method
 			switch method {
-			case "PUT":
+			case "GET", "PUT":
 				w.WriteHeader(http.StatusOK)
 				w.Header().Set("Content-Type", "application/json")
 				resp := make(map[string]interface{})
@@ -161,6 +162,7 @@ var _ = BeforeSuite(func() {
 		Scheme:           k8sManager.GetScheme(),
 		ApiClient:        testClient,
 		ControllerDomain: testControllerDomain,
+		Recorder:         k8sManager.GetEventRecorderFor("apicheck-controller"),
 	}).SetupWithManager(k8sManager)
 	Expect(err).ToNot(HaveOccurred())
 
@@ -169,6 +171,7 @@ var _ = BeforeSuite(func() {
 		Scheme:           k8sManager.GetScheme(),
 		ApiClient:        testClient,
 		ControllerDomain: testControllerDomain,
+		Recorder:         k8sManager.GetEventRecorderFor("group-controller"),
 	}).SetupWithManager(k8sManager)
 	Expect(err).ToNot(HaveOccurred())
 
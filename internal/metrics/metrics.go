@@ -0,0 +1,66 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const namespace = "checkly_operator"
+
+// Outcomes of a checklyhq.com API call
+const (
+	OutcomeSuccess  = "success"
+	OutcomeNotFound = "not_found"
+	OutcomeError    = "error"
+)
+
+var (
+	// APICallsTotal counts the checklyhq.com API calls by resource type, operation and outcome
+	APICallsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "api_calls_total",
+			Help:      "Number of checklyhq.com API calls by resource type, operation and outcome.",
+		},
+		[]string{"resource", "operation", "outcome"},
+	)
+
+	// APICallDuration observes the latency of the checklyhq.com API calls by resource type and operation
+	APICallDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "api_call_duration_seconds",
+			Help:      "Latency of the checklyhq.com API calls by resource type and operation.",
+			Buckets:   prometheus.DefBuckets,
+		},
+		[]string{"resource", "operation"},
+	)
+)
+
+func init() {
+	metrics.Registry.MustRegister(APICallsTotal, APICallDuration)
+}
+
+// ObserveAPICall records the outcome and the duration of a checklyhq.com API call
+func ObserveAPICall(resource string, operation string, outcome string, duration time.Duration) {
+	APICallsTotal.WithLabelValues(resource, operation, outcome).Inc()
+	APICallDuration.WithLabelValues(resource, operation).Observe(duration.Seconds())
+}
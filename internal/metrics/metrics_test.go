@@ -0,0 +1,121 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	checklyv1alpha1 "github.com/checkly/checkly-operator/api/checkly/v1alpha1"
+)
+
+func TestObserveAPICall(t *testing.T) {
+	ObserveAPICall("check", "create", OutcomeSuccess, time.Second)
+	ObserveAPICall("check", "create", OutcomeError, time.Second)
+	ObserveAPICall("check", "create", OutcomeError, time.Second)
+
+	if value := testutil.ToFloat64(APICallsTotal.WithLabelValues("check", "create", OutcomeError)); value != 2 {
+		t.Errorf("Expected %d, got %f", 2, value)
+	}
+	if value := testutil.ToFloat64(APICallsTotal.WithLabelValues("check", "create", OutcomeSuccess)); value != 1 {
+		t.Errorf("Expected %d, got %f", 1, value)
+	}
+	if count := testutil.CollectAndCount(APICallDuration, "checkly_operator_api_call_duration_seconds"); count != 1 {
+		t.Errorf("Expected %d, got %d", 1, count)
+	}
+}
+
+func TestResourceCollector(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := checklyv1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+
+	ready := &checklyv1alpha1.ApiCheck{
+		ObjectMeta: metav1.ObjectMeta{Name: "ready", Namespace: "foo"},
+	}
+	ready.Status.Conditions = []metav1.Condition{
+		{Type: checklyv1alpha1.ConditionReady, Status: metav1.ConditionTrue, Reason: checklyv1alpha1.ReasonSynced},
+		{Type: checklyv1alpha1.ConditionDrifted, Status: metav1.ConditionTrue, Reason: checklyv1alpha1.ReasonDriftCorrected},
+	}
+	failing := &checklyv1alpha1.ApiCheck{
+		ObjectMeta: metav1.ObjectMeta{Name: "failing", Namespace: "foo"},
+	}
+	failing.Status.Conditions = []metav1.Condition{
+		{Type: checklyv1alpha1.ConditionReady, Status: metav1.ConditionFalse, Reason: checklyv1alpha1.ReasonSyncFailed},
+	}
+	group := &checklyv1alpha1.Group{
+		ObjectMeta: metav1.ObjectMeta{Name: "group"},
+	}
+
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(ready, failing, group).Build()
+	collector := NewResourceCollector(c)
+
+	registry := prometheus.NewPedanticRegistry()
+	registry.MustRegister(collector)
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	values := map[string]map[string]float64{}
+	for _, family := range families {
+		values[family.GetName()] = map[string]float64{}
+		for _, metric := range family.GetMetric() {
+			key := ""
+			for _, label := range metric.GetLabel() {
+				key += label.GetName() + "=" + label.GetValue() + ","
+			}
+			values[family.GetName()][key] = metric.GetGauge().GetValue()
+		}
+	}
+
+	expected := map[string]map[string]float64{
+		"checkly_operator_managed_resources": {
+			"kind=ApiCheck,namespace=foo,": 2,
+			"kind=Group,namespace=,":       1,
+		},
+		"checkly_operator_resource_out_of_sync": {
+			"kind=ApiCheck,name=ready,namespace=foo,reason=Synced,":       0,
+			"kind=ApiCheck,name=failing,namespace=foo,reason=SyncFailed,": 1,
+			"kind=Group,name=group,namespace=,reason=Unknown,":            1,
+		},
+		"checkly_operator_resource_drifted": {
+			"kind=ApiCheck,name=ready,namespace=foo,":   1,
+			"kind=ApiCheck,name=failing,namespace=foo,": 0,
+			"kind=Group,name=group,namespace=,":         0,
+		},
+	}
+	for name, metrics := range expected {
+		for key, value := range metrics {
+			got, ok := values[name][key]
+			if !ok {
+				t.Errorf("Expected %s{%s} to be reported", name, key)
+				continue
+			}
+			if got != value {
+				t.Errorf("Expected %s{%s} to be %f, got %f", name, key, value, got)
+			}
+		}
+	}
+}
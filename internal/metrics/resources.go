@@ -0,0 +1,163 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	checklyv1alpha1 "github.com/checkly/checkly-operator/api/checkly/v1alpha1"
+)
+
+var (
+	managedResourcesDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "", "managed_resources"),
+		"Number of checkly resources managed by the operator by kind and namespace.",
+		[]string{"kind", "namespace"}, nil,
+	)
+	outOfSyncDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "", "resource_out_of_sync"),
+		"1 if the checkly resource is not in sync with checklyhq.com, based on the Ready condition.",
+		[]string{"kind", "namespace", "name", "reason"}, nil,
+	)
+	driftedDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "", "resource_drifted"),
+		"1 if the last resync found the checkly resource changed or deleted in checklyhq.com, based on the Drifted condition.",
+		[]string{"kind", "namespace", "name"}, nil,
+	)
+)
+
+// resourceState is the part of a checkly resource the collector reports on
+type resourceState struct {
+	kind      string
+	namespace string
+	name      string
+	status    checklyv1alpha1.SyncStatus
+}
+
+// ResourceCollector reports the state of the checkly resources, it reads them from the manager cache on every scrape
+// so the metrics never go stale when resources are deleted
+type ResourceCollector struct {
+	Client client.Reader
+}
+
+// NewResourceCollector returns a collector reading the checkly resources with the given client
+func NewResourceCollector(c client.Reader) *ResourceCollector {
+	return &ResourceCollector{Client: c}
+}
+
+// Describe implements prometheus.Collector
+func (c *ResourceCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- managedResourcesDesc
+	ch <- outOfSyncDesc
+	ch <- driftedDesc
+}
+
+// Collect implements prometheus.Collector
+func (c *ResourceCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	states, err := c.list(ctx)
+	if err != nil {
+		log.FromContext(ctx).Error(err, "Failed to list checkly resources for metrics")
+		return
+	}
+
+	managed := map[[2]string]int{}
+	for _, state := range states {
+		managed[[2]string{state.kind, state.namespace}]++
+
+		outOfSync, reason := 1.0, "Unknown"
+		if ready := meta.FindStatusCondition(state.status.Conditions, checklyv1alpha1.ConditionReady); ready != nil {
+			reason = ready.Reason
+			if ready.Status == metav1.ConditionTrue {
+				outOfSync = 0
+			}
+		}
+		ch <- prometheus.MustNewConstMetric(outOfSyncDesc, prometheus.GaugeValue, outOfSync, state.kind, state.namespace, state.name, reason)
+
+		drifted := 0.0
+		if meta.IsStatusConditionTrue(state.status.Conditions, checklyv1alpha1.ConditionDrifted) {
+			drifted = 1
+		}
+		ch <- prometheus.MustNewConstMetric(driftedDesc, prometheus.GaugeValue, drifted, state.kind, state.namespace, state.name)
+	}
+
+	for key, count := range managed {
+		ch <- prometheus.MustNewConstMetric(managedResourcesDesc, prometheus.GaugeValue, float64(count), key[0], key[1])
+	}
+}
+
+// list reads all checkly resources
+func (c *ResourceCollector) list(ctx context.Context) (states []resourceState, err error) {
+	apiChecks := &checklyv1alpha1.ApiCheckList{}
+	if err = c.Client.List(ctx, apiChecks); err != nil {
+		return
+	}
+	for _, item := range apiChecks.Items {
+		states = append(states, resourceState{"ApiCheck", item.Namespace, item.Name, item.Status.SyncStatus})
+	}
+
+	browserChecks := &checklyv1alpha1.BrowserCheckList{}
+	if err = c.Client.List(ctx, browserChecks); err != nil {
+		return
+	}
+	for _, item := range browserChecks.Items {
+		states = append(states, resourceState{"BrowserCheck", item.Namespace, item.Name, item.Status.SyncStatus})
+	}
+
+	groups := &checklyv1alpha1.GroupList{}
+	if err = c.Client.List(ctx, groups); err != nil {
+		return
+	}
+	for _, item := range groups.Items {
+		states = append(states, resourceState{"Group", item.Namespace, item.Name, item.Status.SyncStatus})
+	}
+
+	alertChannels := &checklyv1alpha1.AlertChannelList{}
+	if err = c.Client.List(ctx, alertChannels); err != nil {
+		return
+	}
+	for _, item := range alertChannels.Items {
+		states = append(states, resourceState{"AlertChannel", item.Namespace, item.Name, item.Status.SyncStatus})
+	}
+
+	checkGroups := &checklyv1alpha1.CheckGroupList{}
+	if err = c.Client.List(ctx, checkGroups); err != nil {
+		return
+	}
+	for _, item := range checkGroups.Items {
+		states = append(states, resourceState{"CheckGroup", item.Namespace, item.Name, item.Status.SyncStatus})
+	}
+
+	namespacedAlertChannels := &checklyv1alpha1.NamespacedAlertChannelList{}
+	if err = c.Client.List(ctx, namespacedAlertChannels); err != nil {
+		return
+	}
+	for _, item := range namespacedAlertChannels.Items {
+		states = append(states, resourceState{"NamespacedAlertChannel", item.Namespace, item.Name, item.Status.SyncStatus})
+	}
+
+	return
+}
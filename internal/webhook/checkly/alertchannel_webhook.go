@@ -0,0 +1,219 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package checkly
+
+import (
+	"context"
+	"fmt"
+	"net/mail"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/checkly/checkly-go-sdk"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	checklyv1alpha1 "github.com/checkly/checkly-operator/api/checkly/v1alpha1"
+)
+
+var alertchannellog = logf.Log.WithName("alertchannel-webhook")
+
+// opsGeniePriorities and opsGenieRegions hold the values checklyhq.com accepts for OpsGenie alert channels
+var (
+	opsGeniePriorities = []string{"P1", "P2", "P3", "P4", "P5"}
+	opsGenieRegions    = []string{"EU", "US"}
+)
+
+// smsNumber matches phone numbers in E.164 format, the format checklyhq.com expects for SMS alert channels
+var smsNumber = regexp.MustCompile(`^\+[1-9][0-9]{6,14}$`)
+
+// SetupAlertChannelWebhookWithManager registers the AlertChannel webhook with the Manager.
+// AlertChannel resources have no hidden defaults, so only the validating webhook is registered.
+func SetupAlertChannelWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(&checklyv1alpha1.AlertChannel{}).
+		WithValidator(&AlertChannelCustomValidator{}).
+		Complete()
+}
+
+//+kubebuilder:webhook:path=/validate-k8s-checklyhq-com-v1alpha1-alertchannel,mutating=false,failurePolicy=fail,sideEffects=None,groups=k8s.checklyhq.com,resources=alertchannels,verbs=create;update,versions=v1alpha1,name=valertchannel-v1alpha1.k8s.checklyhq.com,admissionReviewVersions=v1
+
+// AlertChannelCustomValidator rejects AlertChannel resources which checklyhq.com would not accept
+type AlertChannelCustomValidator struct{}
+
+var _ webhook.CustomValidator = &AlertChannelCustomValidator{}
+
+// ValidateCreate implements webhook.CustomValidator
+func (v *AlertChannelCustomValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	alertChannel, ok := obj.(*checklyv1alpha1.AlertChannel)
+	if !ok {
+		return nil, fmt.Errorf("expected an AlertChannel object but got %T", obj)
+	}
+	alertchannellog.V(1).Info("Validating create", "name", alertChannel.Name)
+
+	return nil, validateAlertChannel(alertChannel, nil)
+}
+
+// ValidateUpdate implements webhook.CustomValidator
+func (v *AlertChannelCustomValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	alertChannel, ok := newObj.(*checklyv1alpha1.AlertChannel)
+	if !ok {
+		return nil, fmt.Errorf("expected an AlertChannel object but got %T", newObj)
+	}
+	oldAlertChannel, ok := oldObj.(*checklyv1alpha1.AlertChannel)
+	if !ok {
+		return nil, fmt.Errorf("expected an AlertChannel object but got %T", oldObj)
+	}
+	alertchannellog.V(1).Info("Validating update", "name", alertChannel.Name)
+
+	return nil, validateAlertChannel(alertChannel, &oldAlertChannel.Spec)
+}
+
+// ValidateDelete implements webhook.CustomValidator, deletion is always allowed
+func (v *AlertChannelCustomValidator) ValidateDelete(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+func validateAlertChannel(alertChannel *checklyv1alpha1.AlertChannel, oldSpec *checklyv1alpha1.AlertChannelSpec) error {
+	allErrs := validateAlertChannelSpec(alertChannel.Spec, oldSpec, "")
+	if len(allErrs) == 0 {
+		return nil
+	}
+
+	return apierrors.NewInvalid(checklyv1alpha1.GroupVersion.WithKind("AlertChannel").GroupKind(), alertChannel.Name, allErrs)
+}
+
+// validateAlertChannelSpec validates the spec of an AlertChannel or a NamespacedAlertChannel, namespace is the namespace of the
+// NamespacedAlertChannel, its secrets have to be in the same namespace. oldSpec is the spec before an update, nil on create.
+func validateAlertChannelSpec(spec checklyv1alpha1.AlertChannelSpec, oldSpec *checklyv1alpha1.AlertChannelSpec, namespace string) (allErrs field.ErrorList) {
+	specPath := field.NewPath("spec")
+
+	if oldSpec != nil {
+		allErrs = append(allErrs, validateAccountUnchanged(oldSpec.Account, spec.Account, specPath.Child("account"))...)
+	}
+
+	hasEmail := spec.Email != (checkly.AlertChannelEmail{})
+	hasOpsGenie := spec.OpsGenie != (checklyv1alpha1.AlertChannelOpsGenie{})
+
+	var types []string
+	for alertChannelType, set := range map[string]bool{
+		"email":     hasEmail,
+		"opsgenie":  hasOpsGenie,
+		"slack":     spec.Slack != nil,
+		"pagerduty": spec.PagerDuty != nil,
+		"webhook":   spec.Webhook != nil,
+		"sms":       spec.SMS != nil,
+		"msteams":   spec.MSTeams != nil,
+	} {
+		if set {
+			types = append(types, alertChannelType)
+		}
+	}
+	slices.Sort(types)
+
+	switch {
+	case len(types) > 1:
+		allErrs = append(allErrs, field.Forbidden(specPath, fmt.Sprintf("only one alert channel type can be set, got %s, create an AlertChannel for each", strings.Join(types, ", "))))
+	case len(types) == 0:
+		allErrs = append(allErrs, field.Required(specPath, "one of email, opsgenie, slack, pagerduty, webhook, sms or msteams has to be set"))
+	}
+
+	if hasEmail {
+		emailPath := specPath.Child("email", "address")
+		if _, err := mail.ParseAddress(spec.Email.Address); err != nil {
+			allErrs = append(allErrs, field.Invalid(emailPath, spec.Email.Address, "has to be a valid email address"))
+		}
+	}
+
+	if hasOpsGenie {
+		opsGeniePath := specPath.Child("opsgenie")
+		allErrs = append(allErrs, validateSecretReference(spec.OpsGenie.APISecret, namespace, opsGeniePath.Child("apisecret"))...)
+		if priority := spec.OpsGenie.Priority; priority != "" && !slices.Contains(opsGeniePriorities, priority) {
+			allErrs = append(allErrs, field.NotSupported(opsGeniePath.Child("priority"), priority, opsGeniePriorities))
+		}
+		if region := spec.OpsGenie.Region; region != "" && !slices.Contains(opsGenieRegions, region) {
+			allErrs = append(allErrs, field.NotSupported(opsGeniePath.Child("region"), region, opsGenieRegions))
+		}
+	}
+
+	if spec.Slack != nil {
+		allErrs = append(allErrs, validateSecretReference(spec.Slack.URLSecret, namespace, specPath.Child("slack", "urlsecret"))...)
+	}
+
+	if spec.PagerDuty != nil {
+		allErrs = append(allErrs, validateSecretReference(spec.PagerDuty.ServiceKeySecret, namespace, specPath.Child("pagerduty", "servicekeysecret"))...)
+	}
+
+	if spec.MSTeams != nil {
+		allErrs = append(allErrs, validateSecretReference(spec.MSTeams.URLSecret, namespace, specPath.Child("msteams", "urlsecret"))...)
+	}
+
+	if spec.SMS != nil && !smsNumber.MatchString(spec.SMS.Number) {
+		allErrs = append(allErrs, field.Invalid(specPath.Child("sms", "number"), spec.SMS.Number, "has to be a phone number in E.164 format, ex. +15555550100"))
+	}
+
+	if spec.Webhook != nil {
+		webhookPath := specPath.Child("webhook")
+		allErrs = append(allErrs, validateSecretReference(spec.Webhook.URLSecret, namespace, webhookPath.Child("urlsecret"))...)
+		if spec.Webhook.WebhookSecret != nil {
+			allErrs = append(allErrs, validateSecretReference(*spec.Webhook.WebhookSecret, namespace, webhookPath.Child("webhooksecret"))...)
+		}
+		allErrs = append(allErrs, validateWebhookKeyValues(spec.Webhook.Headers, namespace, webhookPath.Child("headers"))...)
+		allErrs = append(allErrs, validateWebhookKeyValues(spec.Webhook.QueryParameters, namespace, webhookPath.Child("queryparameters"))...)
+	}
+
+	return
+}
+
+// validateSecretReference makes sure the controller can look up the referenced secret key. The secrets of a NamespacedAlertChannel
+// are read from its own namespace, so the namespace of the reference can be left empty but can not point to another namespace.
+func validateSecretReference(secret corev1.ObjectReference, namespace string, path *field.Path) field.ErrorList {
+	if namespace != "" {
+		if secret.Name == "" || secret.FieldPath == "" {
+			return field.ErrorList{field.Required(path, "name and fieldPath of the secret have to be set")}
+		}
+		if secret.Namespace != "" && secret.Namespace != namespace {
+			return field.ErrorList{field.Forbidden(path.Child("namespace"), fmt.Sprintf("secrets can only be read from namespace %s", namespace))}
+		}
+		return nil
+	}
+
+	if secret.Name == "" || secret.Namespace == "" || secret.FieldPath == "" {
+		return field.ErrorList{field.Required(path, "name, namespace and fieldPath of the secret have to be set")}
+	}
+	return nil
+}
+
+// validateWebhookKeyValues checks that every webhook header or query parameter has a key and a complete secret reference
+func validateWebhookKeyValues(keyValues []checklyv1alpha1.AlertChannelKeyValue, namespace string, path *field.Path) (allErrs field.ErrorList) {
+	for i, keyValue := range keyValues {
+		if keyValue.Key == "" {
+			allErrs = append(allErrs, field.Required(path.Index(i).Child("key"), "key has to be set"))
+		}
+		if keyValue.ValueFrom != nil {
+			allErrs = append(allErrs, validateSecretReference(*keyValue.ValueFrom, namespace, path.Index(i).Child("valuefrom"))...)
+		}
+	}
+	return
+}
@@ -0,0 +1,123 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package checkly
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/checkly/checkly-go-sdk"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	checklyv1alpha1 "github.com/checkly/checkly-operator/api/checkly/v1alpha1"
+)
+
+func TestAlertChannelValidate(t *testing.T) {
+	opsGenie := checklyv1alpha1.AlertChannelOpsGenie{
+		APISecret: corev1.ObjectReference{
+			Name:      "foo",
+			Namespace: "bar",
+			FieldPath: "API_KEY",
+		},
+		Region:   "EU",
+		Priority: "P3",
+	}
+
+	secret := corev1.ObjectReference{
+		Name:      "foo",
+		Namespace: "bar",
+		FieldPath: "URL",
+	}
+
+	webhook := &checklyv1alpha1.AlertChannelWebhook{
+		URLSecret: secret,
+		Method:    "POST",
+		Headers: []checklyv1alpha1.AlertChannelKeyValue{
+			{Key: "X-Foo", Value: "bar"},
+			{Key: "Authorization", ValueFrom: &secret},
+		},
+	}
+
+	testCases := []struct {
+		name  string
+		spec  checklyv1alpha1.AlertChannelSpec
+		valid bool
+	}{
+		{"email", checklyv1alpha1.AlertChannelSpec{Email: checkly.AlertChannelEmail{Address: "foo@bar.baz"}}, true},
+		{"opsgenie", checklyv1alpha1.AlertChannelSpec{OpsGenie: opsGenie}, true},
+		{"empty", checklyv1alpha1.AlertChannelSpec{}, false},
+		{"both", checklyv1alpha1.AlertChannelSpec{Email: checkly.AlertChannelEmail{Address: "foo@bar.baz"}, OpsGenie: opsGenie}, false},
+		{"invalid email", checklyv1alpha1.AlertChannelSpec{Email: checkly.AlertChannelEmail{Address: "foo"}}, false},
+		{"opsgenie without secret", checklyv1alpha1.AlertChannelSpec{OpsGenie: checklyv1alpha1.AlertChannelOpsGenie{Region: "EU"}}, false},
+		{"opsgenie unknown priority", checklyv1alpha1.AlertChannelSpec{OpsGenie: checklyv1alpha1.AlertChannelOpsGenie{APISecret: opsGenie.APISecret, Priority: "P9"}}, false},
+		{"opsgenie unknown region", checklyv1alpha1.AlertChannelSpec{OpsGenie: checklyv1alpha1.AlertChannelOpsGenie{APISecret: opsGenie.APISecret, Region: "APAC"}}, false},
+		{"slack", checklyv1alpha1.AlertChannelSpec{Slack: &checklyv1alpha1.AlertChannelSlack{URLSecret: secret, Channel: "#alerts"}}, true},
+		{"slack without secret", checklyv1alpha1.AlertChannelSpec{Slack: &checklyv1alpha1.AlertChannelSlack{Channel: "#alerts"}}, false},
+		{"slack and email", checklyv1alpha1.AlertChannelSpec{Email: checkly.AlertChannelEmail{Address: "foo@bar.baz"}, Slack: &checklyv1alpha1.AlertChannelSlack{URLSecret: secret}}, false},
+		{"pagerduty", checklyv1alpha1.AlertChannelSpec{PagerDuty: &checklyv1alpha1.AlertChannelPagerDuty{ServiceKeySecret: secret}}, true},
+		{"pagerduty without secret", checklyv1alpha1.AlertChannelSpec{PagerDuty: &checklyv1alpha1.AlertChannelPagerDuty{Account: "foo"}}, false},
+		{"msteams", checklyv1alpha1.AlertChannelSpec{MSTeams: &checklyv1alpha1.AlertChannelMSTeams{URLSecret: secret}}, true},
+		{"sms", checklyv1alpha1.AlertChannelSpec{SMS: &checklyv1alpha1.AlertChannelSMS{Number: "+15555550100"}}, true},
+		{"sms invalid number", checklyv1alpha1.AlertChannelSpec{SMS: &checklyv1alpha1.AlertChannelSMS{Number: "555-0100"}}, false},
+		{"webhook", checklyv1alpha1.AlertChannelSpec{Webhook: webhook}, true},
+		{"webhook without secret", checklyv1alpha1.AlertChannelSpec{Webhook: &checklyv1alpha1.AlertChannelWebhook{Method: "POST"}}, false},
+		{"webhook header without key", checklyv1alpha1.AlertChannelSpec{Webhook: &checklyv1alpha1.AlertChannelWebhook{URLSecret: secret, Headers: []checklyv1alpha1.AlertChannelKeyValue{{Value: "bar"}}}}, false},
+		{"webhook query parameter incomplete secret", checklyv1alpha1.AlertChannelSpec{Webhook: &checklyv1alpha1.AlertChannelWebhook{URLSecret: secret, QueryParameters: []checklyv1alpha1.AlertChannelKeyValue{{Key: "foo", ValueFrom: &corev1.ObjectReference{Name: "foo"}}}}}, false},
+	}
+
+	validator := &AlertChannelCustomValidator{}
+	for _, tc := range testCases {
+		alertChannel := &checklyv1alpha1.AlertChannel{
+			ObjectMeta: metav1.ObjectMeta{Name: "foo"},
+			Spec:       tc.spec,
+		}
+
+		_, err := validator.ValidateCreate(context.Background(), alertChannel)
+		if tc.valid && err != nil {
+			t.Errorf("%s: expected no error, got %s", tc.name, err)
+		}
+		if !tc.valid && err == nil {
+			t.Errorf("%s: expected error, got none", tc.name)
+		}
+	}
+}
+
+func TestAlertChannelValidateUpdate(t *testing.T) {
+	oldAlertChannel := &checklyv1alpha1.AlertChannel{
+		ObjectMeta: metav1.ObjectMeta{Name: "foo"},
+		Spec: checklyv1alpha1.AlertChannelSpec{
+			Email:   checkly.AlertChannelEmail{Address: "foo@bar.baz"},
+			Account: "staging",
+		},
+	}
+
+	alertChannel := oldAlertChannel.DeepCopy()
+	alertChannel.Spec.SendFailure = true
+	_, err := (&AlertChannelCustomValidator{}).ValidateUpdate(context.Background(), oldAlertChannel, alertChannel)
+	if err != nil {
+		t.Errorf("Expected no error, got %s", err)
+	}
+
+	alertChannel = oldAlertChannel.DeepCopy()
+	alertChannel.Spec.Account = ""
+	_, err = (&AlertChannelCustomValidator{}).ValidateUpdate(context.Background(), oldAlertChannel, alertChannel)
+	if err == nil || !strings.Contains(err.Error(), "spec.account") {
+		t.Errorf("Expected error about spec.account, got %v", err)
+	}
+}
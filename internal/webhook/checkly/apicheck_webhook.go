@@ -0,0 +1,135 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package checkly
+
+import (
+	"context"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	checklyv1alpha1 "github.com/checkly/checkly-operator/api/checkly/v1alpha1"
+	external "github.com/checkly/checkly-operator/external/checkly"
+)
+
+var apichecklog = logf.Log.WithName("apicheck-webhook")
+
+// SetupApiCheckWebhookWithManager registers the ApiCheck webhooks with the Manager.
+func SetupApiCheckWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(&checklyv1alpha1.ApiCheck{}).
+		WithDefaulter(&ApiCheckCustomDefaulter{}).
+		WithValidator(&ApiCheckCustomValidator{}).
+		Complete()
+}
+
+//+kubebuilder:webhook:path=/mutate-k8s-checklyhq-com-v1alpha1-apicheck,mutating=true,failurePolicy=fail,sideEffects=None,groups=k8s.checklyhq.com,resources=apichecks,verbs=create;update,versions=v1alpha1,name=mapicheck-v1alpha1.k8s.checklyhq.com,admissionReviewVersions=v1
+
+// ApiCheckCustomDefaulter sets the default values of ApiCheck resources
+type ApiCheckCustomDefaulter struct{}
+
+var _ webhook.CustomDefaulter = &ApiCheckCustomDefaulter{}
+
+// Default implements webhook.CustomDefaulter
+func (d *ApiCheckCustomDefaulter) Default(ctx context.Context, obj runtime.Object) error {
+	apiCheck, ok := obj.(*checklyv1alpha1.ApiCheck)
+	if !ok {
+		return fmt.Errorf("expected an ApiCheck object but got %T", obj)
+	}
+	apichecklog.V(1).Info("Defaulting", "name", apiCheck.Name, "namespace", apiCheck.Namespace)
+
+	if apiCheck.Spec.Frequency == 0 {
+		apiCheck.Spec.Frequency = external.DefaultApiCheckFrequency
+	}
+	if apiCheck.Spec.MaxResponseTime == 0 {
+		apiCheck.Spec.MaxResponseTime = external.DefaultApiCheckMaxResponseTime
+	}
+	if apiCheck.Spec.Method == "" {
+		apiCheck.Spec.Method = external.DefaultApiCheckMethod
+	}
+	if apiCheck.Spec.BodyType == "" {
+		apiCheck.Spec.BodyType = external.DefaultApiCheckBodyType
+	}
+
+	return nil
+}
+
+//+kubebuilder:webhook:path=/validate-k8s-checklyhq-com-v1alpha1-apicheck,mutating=false,failurePolicy=fail,sideEffects=None,groups=k8s.checklyhq.com,resources=apichecks,verbs=create;update,versions=v1alpha1,name=vapicheck-v1alpha1.k8s.checklyhq.com,admissionReviewVersions=v1
+
+// ApiCheckCustomValidator rejects ApiCheck resources which checklyhq.com would not accept
+type ApiCheckCustomValidator struct{}
+
+var _ webhook.CustomValidator = &ApiCheckCustomValidator{}
+
+// ValidateCreate implements webhook.CustomValidator
+func (v *ApiCheckCustomValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	apiCheck, ok := obj.(*checklyv1alpha1.ApiCheck)
+	if !ok {
+		return nil, fmt.Errorf("expected an ApiCheck object but got %T", obj)
+	}
+	apichecklog.V(1).Info("Validating create", "name", apiCheck.Name, "namespace", apiCheck.Namespace)
+
+	return nil, validateApiCheck(apiCheck)
+}
+
+// ValidateUpdate implements webhook.CustomValidator
+func (v *ApiCheckCustomValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	apiCheck, ok := newObj.(*checklyv1alpha1.ApiCheck)
+	if !ok {
+		return nil, fmt.Errorf("expected an ApiCheck object but got %T", newObj)
+	}
+	apichecklog.V(1).Info("Validating update", "name", apiCheck.Name, "namespace", apiCheck.Namespace)
+
+	return nil, validateApiCheck(apiCheck)
+}
+
+// ValidateDelete implements webhook.CustomValidator, deletion is always allowed
+func (v *ApiCheckCustomValidator) ValidateDelete(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+func validateApiCheck(apiCheck *checklyv1alpha1.ApiCheck) error {
+	var allErrs field.ErrorList
+	specPath := field.NewPath("spec")
+
+	allErrs = append(allErrs, validateFrequency(apiCheck.Spec.Frequency, specPath.Child("frequency"))...)
+	allErrs = append(allErrs, validateEndpoint(apiCheck.Spec.Endpoint, specPath.Child("endpoint"))...)
+	allErrs = append(allErrs, validateSuccess(apiCheck.Spec.Success, specPath.Child("success"))...)
+	allErrs = append(allErrs, validateLocations(apiCheck.Spec.Locations, specPath.Child("locations"))...)
+	allErrs = append(allErrs, validateKeyValues(apiCheck.Spec.Headers, specPath.Child("headers"))...)
+	allErrs = append(allErrs, validateKeyValues(apiCheck.Spec.QueryParameters, specPath.Child("queryParameters"))...)
+
+	if apiCheck.Spec.MaxResponseTime < 0 || apiCheck.Spec.MaxResponseTime > maxResponseTime {
+		allErrs = append(allErrs, field.Invalid(specPath.Child("maxresponsetime"), apiCheck.Spec.MaxResponseTime, fmt.Sprintf("has to be between 0 and %d milliseconds", maxResponseTime)))
+	}
+
+	if apiCheck.Spec.Group == "" {
+		allErrs = append(allErrs, field.Required(specPath.Child("group"), "group has to be set"))
+	}
+
+	if len(allErrs) == 0 {
+		return nil
+	}
+
+	return apierrors.NewInvalid(checklyv1alpha1.GroupVersion.WithKind("ApiCheck").GroupKind(), apiCheck.Name, allErrs)
+}
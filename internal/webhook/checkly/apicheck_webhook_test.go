@@ -0,0 +1,119 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package checkly
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	checklyv1alpha1 "github.com/checkly/checkly-operator/api/checkly/v1alpha1"
+	external "github.com/checkly/checkly-operator/external/checkly"
+)
+
+func TestApiCheckDefault(t *testing.T) {
+	apiCheck := &checklyv1alpha1.ApiCheck{
+		ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "bar"},
+		Spec: checklyv1alpha1.ApiCheckSpec{
+			Endpoint:  "https://foo.bar/baz",
+			Success:   "200",
+			Group:     "baz",
+			Frequency: 15,
+		},
+	}
+
+	err := (&ApiCheckCustomDefaulter{}).Default(context.Background(), apiCheck)
+	if err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+
+	if apiCheck.Spec.Frequency != 15 {
+		t.Errorf("Expected %d, got %d", 15, apiCheck.Spec.Frequency)
+	}
+
+	if apiCheck.Spec.MaxResponseTime != external.DefaultApiCheckMaxResponseTime {
+		t.Errorf("Expected %d, got %d", external.DefaultApiCheckMaxResponseTime, apiCheck.Spec.MaxResponseTime)
+	}
+
+	if apiCheck.Spec.Method != external.DefaultApiCheckMethod {
+		t.Errorf("Expected %s, got %s", external.DefaultApiCheckMethod, apiCheck.Spec.Method)
+	}
+
+	if apiCheck.Spec.BodyType != external.DefaultApiCheckBodyType {
+		t.Errorf("Expected %s, got %s", external.DefaultApiCheckBodyType, apiCheck.Spec.BodyType)
+	}
+
+	if err := (&ApiCheckCustomDefaulter{}).Default(context.Background(), &checklyv1alpha1.Group{}); err == nil {
+		t.Error("Expected error for a wrong object type, got none")
+	}
+}
+
+func TestApiCheckValidate(t *testing.T) {
+	validSpec := checklyv1alpha1.ApiCheckSpec{
+		Endpoint: "https://foo.bar/baz",
+		Success:  "200",
+		Group:    "baz",
+	}
+
+	testCases := []struct {
+		name  string
+		spec  func(spec *checklyv1alpha1.ApiCheckSpec)
+		valid bool
+	}{
+		{"valid", func(spec *checklyv1alpha1.ApiCheckSpec) {}, true},
+		{"supported frequency", func(spec *checklyv1alpha1.ApiCheckSpec) { spec.Frequency = 60 }, true},
+		{"unsupported frequency", func(spec *checklyv1alpha1.ApiCheckSpec) { spec.Frequency = 7 }, false},
+		{"relative endpoint", func(spec *checklyv1alpha1.ApiCheckSpec) { spec.Endpoint = "/baz" }, false},
+		{"ftp endpoint", func(spec *checklyv1alpha1.ApiCheckSpec) { spec.Endpoint = "ftp://foo.bar" }, false},
+		{"non numeric success", func(spec *checklyv1alpha1.ApiCheckSpec) { spec.Success = "OK" }, false},
+		{"out of range success", func(spec *checklyv1alpha1.ApiCheckSpec) { spec.Success = "999" }, false},
+		{"max response time too high", func(spec *checklyv1alpha1.ApiCheckSpec) { spec.MaxResponseTime = 60000 }, false},
+		{"public location", func(spec *checklyv1alpha1.ApiCheckSpec) { spec.Locations = []string{"eu-west-1"} }, true},
+		{"unknown location", func(spec *checklyv1alpha1.ApiCheckSpec) { spec.Locations = []string{"basement"} }, false},
+		{"missing group", func(spec *checklyv1alpha1.ApiCheckSpec) { spec.Group = "" }, false},
+		{"header without key", func(spec *checklyv1alpha1.ApiCheckSpec) {
+			spec.Headers = []checklyv1alpha1.ApiCheckKeyValue{{Value: "foo"}}
+		}, false},
+		{"header with incomplete secret", func(spec *checklyv1alpha1.ApiCheckSpec) {
+			spec.Headers = []checklyv1alpha1.ApiCheckKeyValue{{Key: "Authorization", ValueFrom: &corev1.SecretKeySelector{Key: "token"}}}
+		}, false},
+	}
+
+	validator := &ApiCheckCustomValidator{}
+	for _, tc := range testCases {
+		apiCheck := &checklyv1alpha1.ApiCheck{
+			ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "bar"},
+			Spec:       *validSpec.DeepCopy(),
+		}
+		tc.spec(&apiCheck.Spec)
+
+		_, err := validator.ValidateCreate(context.Background(), apiCheck)
+		if tc.valid && err != nil {
+			t.Errorf("%s: expected no error on create, got %s", tc.name, err)
+		}
+		if !tc.valid && err == nil {
+			t.Errorf("%s: expected error on create, got none", tc.name)
+		}
+
+		_, err = validator.ValidateUpdate(context.Background(), apiCheck, apiCheck)
+		if tc.valid != (err == nil) {
+			t.Errorf("%s: expected the same result on update as on create, got %v", tc.name, err)
+		}
+	}
+}
@@ -0,0 +1,125 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package checkly
+
+import (
+	"context"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	checklyv1alpha1 "github.com/checkly/checkly-operator/api/checkly/v1alpha1"
+	external "github.com/checkly/checkly-operator/external/checkly"
+)
+
+var browserchecklog = logf.Log.WithName("browsercheck-webhook")
+
+// SetupBrowserCheckWebhookWithManager registers the BrowserCheck webhooks with the Manager.
+func SetupBrowserCheckWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(&checklyv1alpha1.BrowserCheck{}).
+		WithDefaulter(&BrowserCheckCustomDefaulter{}).
+		WithValidator(&BrowserCheckCustomValidator{}).
+		Complete()
+}
+
+//+kubebuilder:webhook:path=/mutate-k8s-checklyhq-com-v1alpha1-browsercheck,mutating=true,failurePolicy=fail,sideEffects=None,groups=k8s.checklyhq.com,resources=browserchecks,verbs=create;update,versions=v1alpha1,name=mbrowsercheck-v1alpha1.k8s.checklyhq.com,admissionReviewVersions=v1
+
+// BrowserCheckCustomDefaulter sets the default values of BrowserCheck resources
+type BrowserCheckCustomDefaulter struct{}
+
+var _ webhook.CustomDefaulter = &BrowserCheckCustomDefaulter{}
+
+// Default implements webhook.CustomDefaulter
+func (d *BrowserCheckCustomDefaulter) Default(ctx context.Context, obj runtime.Object) error {
+	browserCheck, ok := obj.(*checklyv1alpha1.BrowserCheck)
+	if !ok {
+		return fmt.Errorf("expected a BrowserCheck object but got %T", obj)
+	}
+	browserchecklog.V(1).Info("Defaulting", "name", browserCheck.Name, "namespace", browserCheck.Namespace)
+
+	if browserCheck.Spec.Frequency == 0 {
+		browserCheck.Spec.Frequency = external.DefaultBrowserCheckFrequency
+	}
+
+	return nil
+}
+
+//+kubebuilder:webhook:path=/validate-k8s-checklyhq-com-v1alpha1-browsercheck,mutating=false,failurePolicy=fail,sideEffects=None,groups=k8s.checklyhq.com,resources=browserchecks,verbs=create;update,versions=v1alpha1,name=vbrowsercheck-v1alpha1.k8s.checklyhq.com,admissionReviewVersions=v1
+
+// BrowserCheckCustomValidator rejects BrowserCheck resources which checklyhq.com would not accept
+type BrowserCheckCustomValidator struct{}
+
+var _ webhook.CustomValidator = &BrowserCheckCustomValidator{}
+
+// ValidateCreate implements webhook.CustomValidator
+func (v *BrowserCheckCustomValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	browserCheck, ok := obj.(*checklyv1alpha1.BrowserCheck)
+	if !ok {
+		return nil, fmt.Errorf("expected a BrowserCheck object but got %T", obj)
+	}
+	browserchecklog.V(1).Info("Validating create", "name", browserCheck.Name, "namespace", browserCheck.Namespace)
+
+	return nil, validateBrowserCheck(browserCheck)
+}
+
+// ValidateUpdate implements webhook.CustomValidator
+func (v *BrowserCheckCustomValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	browserCheck, ok := newObj.(*checklyv1alpha1.BrowserCheck)
+	if !ok {
+		return nil, fmt.Errorf("expected a BrowserCheck object but got %T", newObj)
+	}
+	browserchecklog.V(1).Info("Validating update", "name", browserCheck.Name, "namespace", browserCheck.Namespace)
+
+	return nil, validateBrowserCheck(browserCheck)
+}
+
+// ValidateDelete implements webhook.CustomValidator, deletion is always allowed
+func (v *BrowserCheckCustomValidator) ValidateDelete(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+func validateBrowserCheck(browserCheck *checklyv1alpha1.BrowserCheck) error {
+	var allErrs field.ErrorList
+	specPath := field.NewPath("spec")
+
+	allErrs = append(allErrs, validateFrequency(browserCheck.Spec.Frequency, specPath.Child("frequency"))...)
+
+	if browserCheck.Spec.ScriptConfigMap == nil && browserCheck.Spec.Script == "" {
+		allErrs = append(allErrs, field.Required(specPath.Child("script"), "either script or scriptConfigMap has to be set"))
+	}
+
+	if browserCheck.Spec.ScriptConfigMap != nil && (browserCheck.Spec.ScriptConfigMap.Name == "" || browserCheck.Spec.ScriptConfigMap.Key == "") {
+		allErrs = append(allErrs, field.Required(specPath.Child("scriptConfigMap"), "name and key of the configmap have to be set"))
+	}
+
+	if browserCheck.Spec.Group == "" {
+		allErrs = append(allErrs, field.Required(specPath.Child("group"), "group has to be set"))
+	}
+
+	if len(allErrs) == 0 {
+		return nil
+	}
+
+	return apierrors.NewInvalid(checklyv1alpha1.GroupVersion.WithKind("BrowserCheck").GroupKind(), browserCheck.Name, allErrs)
+}
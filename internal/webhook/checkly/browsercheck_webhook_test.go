@@ -0,0 +1,79 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package checkly
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	checklyv1alpha1 "github.com/checkly/checkly-operator/api/checkly/v1alpha1"
+	external "github.com/checkly/checkly-operator/external/checkly"
+)
+
+func TestBrowserCheckDefault(t *testing.T) {
+	browserCheck := &checklyv1alpha1.BrowserCheck{
+		ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "bar"},
+	}
+
+	err := (&BrowserCheckCustomDefaulter{}).Default(context.Background(), browserCheck)
+	if err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+
+	if browserCheck.Spec.Frequency != external.DefaultBrowserCheckFrequency {
+		t.Errorf("Expected %d, got %d", external.DefaultBrowserCheckFrequency, browserCheck.Spec.Frequency)
+	}
+}
+
+func TestBrowserCheckValidate(t *testing.T) {
+	testCases := []struct {
+		name  string
+		spec  checklyv1alpha1.BrowserCheckSpec
+		valid bool
+	}{
+		{"inline script", checklyv1alpha1.BrowserCheckSpec{Group: "baz", Script: "console.log('foo')"}, true},
+		{"configmap script", checklyv1alpha1.BrowserCheckSpec{Group: "baz", ScriptConfigMap: &corev1.ConfigMapKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: "foo"},
+			Key:                  "script.js",
+		}}, true},
+		{"configmap without key", checklyv1alpha1.BrowserCheckSpec{Group: "baz", ScriptConfigMap: &corev1.ConfigMapKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: "foo"},
+		}}, false},
+		{"missing script", checklyv1alpha1.BrowserCheckSpec{Group: "baz"}, false},
+		{"missing group", checklyv1alpha1.BrowserCheckSpec{Script: "console.log('foo')"}, false},
+		{"unsupported frequency", checklyv1alpha1.BrowserCheckSpec{Group: "baz", Script: "console.log('foo')", Frequency: 3}, false},
+	}
+
+	validator := &BrowserCheckCustomValidator{}
+	for _, tc := range testCases {
+		browserCheck := &checklyv1alpha1.BrowserCheck{
+			ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "bar"},
+			Spec:       tc.spec,
+		}
+
+		_, err := validator.ValidateCreate(context.Background(), browserCheck)
+		if tc.valid && err != nil {
+			t.Errorf("%s: expected no error, got %s", tc.name, err)
+		}
+		if !tc.valid && err == nil {
+			t.Errorf("%s: expected error, got none", tc.name)
+		}
+	}
+}
@@ -0,0 +1,122 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package checkly
+
+import (
+	"context"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	checklyv1alpha1 "github.com/checkly/checkly-operator/api/checkly/v1alpha1"
+	external "github.com/checkly/checkly-operator/external/checkly"
+)
+
+var checkgrouplog = logf.Log.WithName("checkgroup-webhook")
+
+// SetupCheckGroupWebhookWithManager registers the CheckGroup webhooks with the Manager.
+func SetupCheckGroupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(&checklyv1alpha1.CheckGroup{}).
+		WithDefaulter(&CheckGroupCustomDefaulter{}).
+		WithValidator(&CheckGroupCustomValidator{Client: mgr.GetClient()}).
+		Complete()
+}
+
+//+kubebuilder:webhook:path=/mutate-k8s-checklyhq-com-v1alpha1-checkgroup,mutating=true,failurePolicy=fail,sideEffects=None,groups=k8s.checklyhq.com,resources=checkgroups,verbs=create;update,versions=v1alpha1,name=mcheckgroup-v1alpha1.k8s.checklyhq.com,admissionReviewVersions=v1
+
+// CheckGroupCustomDefaulter sets the default values of CheckGroup resources
+type CheckGroupCustomDefaulter struct{}
+
+var _ webhook.CustomDefaulter = &CheckGroupCustomDefaulter{}
+
+// Default implements webhook.CustomDefaulter
+func (d *CheckGroupCustomDefaulter) Default(ctx context.Context, obj runtime.Object) error {
+	group, ok := obj.(*checklyv1alpha1.CheckGroup)
+	if !ok {
+		return fmt.Errorf("expected a CheckGroup object but got %T", obj)
+	}
+	checkgrouplog.V(1).Info("Defaulting", "name", group.Name, "namespace", group.Namespace)
+
+	if len(group.Spec.Locations) == 0 && len(group.Spec.PrivateLocations) == 0 {
+		group.Spec.Locations = []string{external.DefaultGroupLocation}
+	}
+
+	return nil
+}
+
+//+kubebuilder:webhook:path=/validate-k8s-checklyhq-com-v1alpha1-checkgroup,mutating=false,failurePolicy=fail,sideEffects=None,groups=k8s.checklyhq.com,resources=checkgroups,verbs=create;update,versions=v1alpha1,name=vcheckgroup-v1alpha1.k8s.checklyhq.com,admissionReviewVersions=v1
+
+// CheckGroupCustomValidator rejects CheckGroup resources which checklyhq.com would not accept
+// or which reference alert channels that do not exist
+type CheckGroupCustomValidator struct {
+	Client client.Reader
+}
+
+var _ webhook.CustomValidator = &CheckGroupCustomValidator{}
+
+// ValidateCreate implements webhook.CustomValidator
+func (v *CheckGroupCustomValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	group, ok := obj.(*checklyv1alpha1.CheckGroup)
+	if !ok {
+		return nil, fmt.Errorf("expected a CheckGroup object but got %T", obj)
+	}
+	checkgrouplog.V(1).Info("Validating create", "name", group.Name, "namespace", group.Namespace)
+
+	return nil, v.validateCheckGroup(ctx, group, nil)
+}
+
+// ValidateUpdate implements webhook.CustomValidator
+func (v *CheckGroupCustomValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	group, ok := newObj.(*checklyv1alpha1.CheckGroup)
+	if !ok {
+		return nil, fmt.Errorf("expected a CheckGroup object but got %T", newObj)
+	}
+	oldGroup, ok := oldObj.(*checklyv1alpha1.CheckGroup)
+	if !ok {
+		return nil, fmt.Errorf("expected a CheckGroup object but got %T", oldObj)
+	}
+	checkgrouplog.V(1).Info("Validating update", "name", group.Name, "namespace", group.Namespace)
+
+	return nil, v.validateCheckGroup(ctx, group, &oldGroup.Spec)
+}
+
+// ValidateDelete implements webhook.CustomValidator, deletion is always allowed
+func (v *CheckGroupCustomValidator) ValidateDelete(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+func (v *CheckGroupCustomValidator) validateCheckGroup(ctx context.Context, group *checklyv1alpha1.CheckGroup, oldSpec *checklyv1alpha1.GroupSpec) error {
+	allErrs := validateGroupSpec(ctx, v.Client, group.Spec, oldSpec, group.Namespace, group.DeletionTimestamp != nil)
+
+	if len(group.Spec.AllowedNamespaces) != 0 {
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "allowedNamespaces"), "a CheckGroup only accepts checks from its own namespace"))
+	}
+
+	if len(allErrs) == 0 {
+		return nil
+	}
+
+	return apierrors.NewInvalid(checklyv1alpha1.GroupVersion.WithKind("CheckGroup").GroupKind(), group.Name, allErrs)
+}
@@ -0,0 +1,102 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package checkly
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	checklyv1alpha1 "github.com/checkly/checkly-operator/api/checkly/v1alpha1"
+	external "github.com/checkly/checkly-operator/external/checkly"
+)
+
+func TestCheckGroupDefault(t *testing.T) {
+	group := &checklyv1alpha1.CheckGroup{
+		ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "team-a"},
+	}
+
+	err := (&CheckGroupCustomDefaulter{}).Default(context.Background(), group)
+	if err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+
+	if len(group.Spec.Locations) != 1 || group.Spec.Locations[0] != external.DefaultGroupLocation {
+		t.Errorf("Expected %v, got %v", []string{external.DefaultGroupLocation}, group.Spec.Locations)
+	}
+}
+
+func TestCheckGroupValidate(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := checklyv1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+
+	objects := []runtime.Object{
+		&checklyv1alpha1.AlertChannel{
+			ObjectMeta: metav1.ObjectMeta{Name: "shared"},
+		},
+		&checklyv1alpha1.NamespacedAlertChannel{
+			ObjectMeta: metav1.ObjectMeta{Name: "own", Namespace: "team-a"},
+		},
+		&checklyv1alpha1.NamespacedAlertChannel{
+			ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "team-b"},
+		},
+	}
+
+	validator := &CheckGroupCustomValidator{
+		Client: fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(objects...).Build(),
+	}
+
+	testCases := []struct {
+		name    string
+		spec    checklyv1alpha1.GroupSpec
+		message string
+	}{
+		{"valid", checklyv1alpha1.GroupSpec{Locations: []string{"eu-west-1"}, AlertChannels: []string{"own", "shared"}}, ""},
+		{"alert channel in another namespace", checklyv1alpha1.GroupSpec{AlertChannels: []string{"own", "other"}}, "spec.alertchannel[1]"},
+		{"allowed namespaces", checklyv1alpha1.GroupSpec{AllowedNamespaces: []string{"team-b"}}, "spec.allowedNamespaces"},
+	}
+
+	for _, tc := range testCases {
+		group := &checklyv1alpha1.CheckGroup{
+			ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "team-a"},
+			Spec:       tc.spec,
+		}
+
+		_, err := validator.ValidateCreate(context.Background(), group)
+		if tc.message == "" {
+			if err != nil {
+				t.Errorf("%s: expected no error, got %s", tc.name, err)
+			}
+			continue
+		}
+
+		if err == nil {
+			t.Errorf("%s: expected error, got none", tc.name)
+			continue
+		}
+
+		if !strings.Contains(err.Error(), tc.message) {
+			t.Errorf("%s: expected error to mention %s, got %s", tc.name, tc.message, err)
+		}
+	}
+}
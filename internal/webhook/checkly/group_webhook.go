@@ -0,0 +1,176 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package checkly
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	checklyv1alpha1 "github.com/checkly/checkly-operator/api/checkly/v1alpha1"
+	external "github.com/checkly/checkly-operator/external/checkly"
+)
+
+var grouplog = logf.Log.WithName("group-webhook")
+
+// SetupGroupWebhookWithManager registers the Group webhooks with the Manager.
+func SetupGroupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(&checklyv1alpha1.Group{}).
+		WithDefaulter(&GroupCustomDefaulter{}).
+		WithValidator(&GroupCustomValidator{Client: mgr.GetClient()}).
+		Complete()
+}
+
+//+kubebuilder:webhook:path=/mutate-k8s-checklyhq-com-v1alpha1-group,mutating=true,failurePolicy=fail,sideEffects=None,groups=k8s.checklyhq.com,resources=groups,verbs=create;update,versions=v1alpha1,name=mgroup-v1alpha1.k8s.checklyhq.com,admissionReviewVersions=v1
+
+// GroupCustomDefaulter sets the default values of Group resources
+type GroupCustomDefaulter struct{}
+
+var _ webhook.CustomDefaulter = &GroupCustomDefaulter{}
+
+// Default implements webhook.CustomDefaulter
+func (d *GroupCustomDefaulter) Default(ctx context.Context, obj runtime.Object) error {
+	group, ok := obj.(*checklyv1alpha1.Group)
+	if !ok {
+		return fmt.Errorf("expected a Group object but got %T", obj)
+	}
+	grouplog.V(1).Info("Defaulting", "name", group.Name)
+
+	if len(group.Spec.Locations) == 0 && len(group.Spec.PrivateLocations) == 0 {
+		group.Spec.Locations = []string{external.DefaultGroupLocation}
+	}
+
+	return nil
+}
+
+//+kubebuilder:webhook:path=/validate-k8s-checklyhq-com-v1alpha1-group,mutating=false,failurePolicy=fail,sideEffects=None,groups=k8s.checklyhq.com,resources=groups,verbs=create;update,versions=v1alpha1,name=vgroup-v1alpha1.k8s.checklyhq.com,admissionReviewVersions=v1
+
+// GroupCustomValidator rejects Group resources which checklyhq.com would not accept
+// or which reference AlertChannel resources that do not exist
+type GroupCustomValidator struct {
+	Client client.Reader
+}
+
+var _ webhook.CustomValidator = &GroupCustomValidator{}
+
+// ValidateCreate implements webhook.CustomValidator
+func (v *GroupCustomValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	group, ok := obj.(*checklyv1alpha1.Group)
+	if !ok {
+		return nil, fmt.Errorf("expected a Group object but got %T", obj)
+	}
+	grouplog.V(1).Info("Validating create", "name", group.Name)
+
+	return nil, v.validateGroup(ctx, group, nil)
+}
+
+// ValidateUpdate implements webhook.CustomValidator
+func (v *GroupCustomValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	group, ok := newObj.(*checklyv1alpha1.Group)
+	if !ok {
+		return nil, fmt.Errorf("expected a Group object but got %T", newObj)
+	}
+	oldGroup, ok := oldObj.(*checklyv1alpha1.Group)
+	if !ok {
+		return nil, fmt.Errorf("expected a Group object but got %T", oldObj)
+	}
+	grouplog.V(1).Info("Validating update", "name", group.Name)
+
+	return nil, v.validateGroup(ctx, group, &oldGroup.Spec)
+}
+
+// ValidateDelete implements webhook.CustomValidator, deletion is always allowed
+func (v *GroupCustomValidator) ValidateDelete(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+func (v *GroupCustomValidator) validateGroup(ctx context.Context, group *checklyv1alpha1.Group, oldSpec *checklyv1alpha1.GroupSpec) error {
+	allErrs := validateGroupSpec(ctx, v.Client, group.Spec, oldSpec, "", group.DeletionTimestamp != nil)
+	if len(allErrs) == 0 {
+		return nil
+	}
+
+	return apierrors.NewInvalid(checklyv1alpha1.GroupVersion.WithKind("Group").GroupKind(), group.Name, allErrs)
+}
+
+// validateGroupSpec validates the spec of a Group or a CheckGroup, namespace is the namespace of the CheckGroup,
+// it can reference NamespacedAlertChannels in the same namespace besides the cluster scoped AlertChannels.
+// oldSpec is the spec before an update, nil on create, deleting is true if the group has a deletion timestamp.
+//
+// The referenced ChecklyAccount and alert channels are only looked up on create and when an update adds them, so a group
+// whose account or alert channel was deleted can still be updated, ex. to remove its finalizer or to add the abandon
+// annotation. They aren't looked up at all for a group which is being deleted.
+func validateGroupSpec(ctx context.Context, c client.Reader, spec checklyv1alpha1.GroupSpec, oldSpec *checklyv1alpha1.GroupSpec, namespace string, deleting bool) (allErrs field.ErrorList) {
+	specPath := field.NewPath("spec")
+
+	allErrs = append(allErrs, validateLocations(spec.Locations, specPath.Child("locations"))...)
+	if oldSpec != nil {
+		allErrs = append(allErrs, validateAccountUnchanged(oldSpec.Account, spec.Account, specPath.Child("account"))...)
+	}
+	if spec.DependentsPolicy == checklyv1alpha1.DependentsPolicyReparent && spec.ReparentTo == "" {
+		allErrs = append(allErrs, field.Required(specPath.Child("reparentTo"), "the group the checks are moved to is required by the Reparent dependents policy"))
+	}
+
+	if deleting {
+		return
+	}
+
+	if oldSpec == nil || oldSpec.Account != spec.Account {
+		allErrs = append(allErrs, validateAccount(ctx, c, spec.Account, specPath.Child("account"))...)
+	}
+
+	for i, name := range spec.AlertChannels {
+		if oldSpec != nil && slices.Contains(oldSpec.AlertChannels, name) {
+			continue
+		}
+
+		if namespace != "" {
+			namespacedAlertChannel := &checklyv1alpha1.NamespacedAlertChannel{}
+			err := c.Get(ctx, types.NamespacedName{Name: name, Namespace: namespace}, namespacedAlertChannel)
+			if err == nil {
+				continue
+			}
+			if !apierrors.IsNotFound(err) {
+				allErrs = append(allErrs, field.InternalError(specPath.Child("alertchannel").Index(i), err))
+				continue
+			}
+		}
+
+		alertChannel := &checklyv1alpha1.AlertChannel{}
+		err := c.Get(ctx, types.NamespacedName{Name: name}, alertChannel)
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				allErrs = append(allErrs, field.NotFound(specPath.Child("alertchannel").Index(i), name))
+				continue
+			}
+			allErrs = append(allErrs, field.InternalError(specPath.Child("alertchannel").Index(i), err))
+		}
+	}
+
+	return
+}
@@ -0,0 +1,192 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package checkly
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	checklyv1alpha1 "github.com/checkly/checkly-operator/api/checkly/v1alpha1"
+	external "github.com/checkly/checkly-operator/external/checkly"
+)
+
+func TestGroupDefault(t *testing.T) {
+	group := &checklyv1alpha1.Group{
+		ObjectMeta: metav1.ObjectMeta{Name: "foo"},
+	}
+
+	err := (&GroupCustomDefaulter{}).Default(context.Background(), group)
+	if err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+
+	if len(group.Spec.Locations) != 1 || group.Spec.Locations[0] != external.DefaultGroupLocation {
+		t.Errorf("Expected %v, got %v", []string{external.DefaultGroupLocation}, group.Spec.Locations)
+	}
+
+	group = &checklyv1alpha1.Group{
+		ObjectMeta: metav1.ObjectMeta{Name: "foo"},
+		Spec: checklyv1alpha1.GroupSpec{
+			PrivateLocations: []string{"basement"},
+		},
+	}
+
+	err = (&GroupCustomDefaulter{}).Default(context.Background(), group)
+	if err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+
+	if len(group.Spec.Locations) != 0 {
+		t.Errorf("Expected no locations next to private locations, got %v", group.Spec.Locations)
+	}
+}
+
+func TestGroupValidate(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := checklyv1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+
+	alertChannel := &checklyv1alpha1.AlertChannel{
+		ObjectMeta: metav1.ObjectMeta{Name: "existing"},
+	}
+
+	account := &checklyv1alpha1.ChecklyAccount{
+		ObjectMeta: metav1.ObjectMeta{Name: "staging"},
+	}
+
+	validator := &GroupCustomValidator{
+		Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(alertChannel, account).Build(),
+	}
+
+	testCases := []struct {
+		name    string
+		spec    checklyv1alpha1.GroupSpec
+		message string
+	}{
+		{"valid", checklyv1alpha1.GroupSpec{Locations: []string{"eu-west-1", "us-east-1"}, AlertChannels: []string{"existing"}}, ""},
+		{"unknown location", checklyv1alpha1.GroupSpec{Locations: []string{"basement"}}, "spec.locations[0]"},
+		{"missing alert channel", checklyv1alpha1.GroupSpec{AlertChannels: []string{"existing", "missing"}}, "spec.alertchannel[1]"},
+		{"account", checklyv1alpha1.GroupSpec{Account: "staging"}, ""},
+		{"missing account", checklyv1alpha1.GroupSpec{Account: "production"}, "spec.account"},
+		{"reparent", checklyv1alpha1.GroupSpec{DependentsPolicy: checklyv1alpha1.DependentsPolicyReparent, ReparentTo: "other"}, ""},
+		{"missing reparent group", checklyv1alpha1.GroupSpec{DependentsPolicy: checklyv1alpha1.DependentsPolicyReparent}, "spec.reparentTo"},
+	}
+
+	for _, tc := range testCases {
+		group := &checklyv1alpha1.Group{
+			ObjectMeta: metav1.ObjectMeta{Name: "foo"},
+			Spec:       tc.spec,
+		}
+
+		_, err := validator.ValidateCreate(context.Background(), group)
+		if tc.message == "" {
+			if err != nil {
+				t.Errorf("%s: expected no error, got %s", tc.name, err)
+			}
+			continue
+		}
+
+		if err == nil {
+			t.Errorf("%s: expected error, got none", tc.name)
+			continue
+		}
+
+		if !strings.Contains(err.Error(), tc.message) {
+			t.Errorf("%s: expected error to mention %s, got %s", tc.name, tc.message, err)
+		}
+	}
+}
+
+func TestGroupValidateUpdate(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := checklyv1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+
+	account := &checklyv1alpha1.ChecklyAccount{
+		ObjectMeta: metav1.ObjectMeta{Name: "staging"},
+	}
+
+	validator := &GroupCustomValidator{
+		Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(account).Build(),
+	}
+
+	oldGroup := &checklyv1alpha1.Group{
+		ObjectMeta: metav1.ObjectMeta{Name: "foo"},
+	}
+
+	group := oldGroup.DeepCopy()
+	group.Spec.Locations = []string{"eu-west-1"}
+	_, err := validator.ValidateUpdate(context.Background(), oldGroup, group)
+	if err != nil {
+		t.Errorf("Expected no error, got %s", err)
+	}
+
+	group = oldGroup.DeepCopy()
+	group.Spec.Account = "staging"
+	_, err = validator.ValidateUpdate(context.Background(), oldGroup, group)
+	if err == nil || !strings.Contains(err.Error(), "spec.account") {
+		t.Errorf("Expected error about spec.account, got %v", err)
+	}
+}
+
+func TestGroupValidateUpdateMissingReferences(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := checklyv1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+
+	// The AlertChannel and the ChecklyAccount were deleted after the Group was created
+	validator := &GroupCustomValidator{
+		Client: fake.NewClientBuilder().WithScheme(scheme).Build(),
+	}
+
+	now := metav1.Now()
+	oldGroup := &checklyv1alpha1.Group{
+		ObjectMeta: metav1.ObjectMeta{Name: "foo", Finalizers: []string{"k8s.checklyhq.com/finalizer"}, DeletionTimestamp: &now},
+		Spec:       checklyv1alpha1.GroupSpec{AlertChannels: []string{"deleted"}, Account: "deleted"},
+	}
+
+	// The controller removes its finalizer
+	group := oldGroup.DeepCopy()
+	group.Finalizers = nil
+	if _, err := validator.ValidateUpdate(context.Background(), oldGroup, group); err != nil {
+		t.Errorf("Expected no error removing the finalizer, got %s", err)
+	}
+
+	// The user abandons the group
+	oldGroup.DeletionTimestamp = nil
+	group = oldGroup.DeepCopy()
+	group.Annotations = map[string]string{"k8s.checklyhq.com/abandon": "true"}
+	if _, err := validator.ValidateUpdate(context.Background(), oldGroup, group); err != nil {
+		t.Errorf("Expected no error adding an annotation, got %s", err)
+	}
+
+	// Newly added references are still looked up
+	group = oldGroup.DeepCopy()
+	group.Spec.AlertChannels = append(group.Spec.AlertChannels, "missing")
+	_, err := validator.ValidateUpdate(context.Background(), oldGroup, group)
+	if err == nil || !strings.Contains(err.Error(), "spec.alertchannel[1]") {
+		t.Errorf("Expected error about spec.alertchannel[1], got %v", err)
+	}
+}
@@ -0,0 +1,90 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package checkly
+
+import (
+	"context"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	checklyv1alpha1 "github.com/checkly/checkly-operator/api/checkly/v1alpha1"
+)
+
+var namespacedalertchannellog = logf.Log.WithName("namespacedalertchannel-webhook")
+
+// SetupNamespacedAlertChannelWebhookWithManager registers the NamespacedAlertChannel webhook with the Manager.
+// NamespacedAlertChannel resources have no hidden defaults, so only the validating webhook is registered.
+func SetupNamespacedAlertChannelWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(&checklyv1alpha1.NamespacedAlertChannel{}).
+		WithValidator(&NamespacedAlertChannelCustomValidator{}).
+		Complete()
+}
+
+//+kubebuilder:webhook:path=/validate-k8s-checklyhq-com-v1alpha1-namespacedalertchannel,mutating=false,failurePolicy=fail,sideEffects=None,groups=k8s.checklyhq.com,resources=namespacedalertchannels,verbs=create;update,versions=v1alpha1,name=vnamespacedalertchannel-v1alpha1.k8s.checklyhq.com,admissionReviewVersions=v1
+
+// NamespacedAlertChannelCustomValidator rejects NamespacedAlertChannel resources which checklyhq.com would not accept
+// or which read secrets from another namespace
+type NamespacedAlertChannelCustomValidator struct{}
+
+var _ webhook.CustomValidator = &NamespacedAlertChannelCustomValidator{}
+
+// ValidateCreate implements webhook.CustomValidator
+func (v *NamespacedAlertChannelCustomValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	alertChannel, ok := obj.(*checklyv1alpha1.NamespacedAlertChannel)
+	if !ok {
+		return nil, fmt.Errorf("expected a NamespacedAlertChannel object but got %T", obj)
+	}
+	namespacedalertchannellog.V(1).Info("Validating create", "name", alertChannel.Name, "namespace", alertChannel.Namespace)
+
+	return nil, validateNamespacedAlertChannel(alertChannel, nil)
+}
+
+// ValidateUpdate implements webhook.CustomValidator
+func (v *NamespacedAlertChannelCustomValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	alertChannel, ok := newObj.(*checklyv1alpha1.NamespacedAlertChannel)
+	if !ok {
+		return nil, fmt.Errorf("expected a NamespacedAlertChannel object but got %T", newObj)
+	}
+	oldAlertChannel, ok := oldObj.(*checklyv1alpha1.NamespacedAlertChannel)
+	if !ok {
+		return nil, fmt.Errorf("expected a NamespacedAlertChannel object but got %T", oldObj)
+	}
+	namespacedalertchannellog.V(1).Info("Validating update", "name", alertChannel.Name, "namespace", alertChannel.Namespace)
+
+	return nil, validateNamespacedAlertChannel(alertChannel, &oldAlertChannel.Spec)
+}
+
+// ValidateDelete implements webhook.CustomValidator, deletion is always allowed
+func (v *NamespacedAlertChannelCustomValidator) ValidateDelete(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+func validateNamespacedAlertChannel(alertChannel *checklyv1alpha1.NamespacedAlertChannel, oldSpec *checklyv1alpha1.AlertChannelSpec) error {
+	allErrs := validateAlertChannelSpec(alertChannel.Spec, oldSpec, alertChannel.Namespace)
+	if len(allErrs) == 0 {
+		return nil
+	}
+
+	return apierrors.NewInvalid(checklyv1alpha1.GroupVersion.WithKind("NamespacedAlertChannel").GroupKind(), alertChannel.Name, allErrs)
+}
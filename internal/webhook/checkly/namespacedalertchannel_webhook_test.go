@@ -0,0 +1,63 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package checkly
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	checklyv1alpha1 "github.com/checkly/checkly-operator/api/checkly/v1alpha1"
+)
+
+func TestNamespacedAlertChannelValidate(t *testing.T) {
+	testCases := []struct {
+		name  string
+		ref   corev1.ObjectReference
+		valid bool
+	}{
+		{"secret in own namespace", corev1.ObjectReference{Name: "foo", Namespace: "team-a", FieldPath: "URL"}, true},
+		{"secret without namespace", corev1.ObjectReference{Name: "foo", FieldPath: "URL"}, true},
+		{"secret in another namespace", corev1.ObjectReference{Name: "foo", Namespace: "team-b", FieldPath: "URL"}, false},
+		{"secret without key", corev1.ObjectReference{Name: "foo"}, false},
+	}
+
+	validator := &NamespacedAlertChannelCustomValidator{}
+	for _, tc := range testCases {
+		alertChannel := &checklyv1alpha1.NamespacedAlertChannel{
+			ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "team-a"},
+			Spec: checklyv1alpha1.AlertChannelSpec{
+				Webhook: &checklyv1alpha1.AlertChannelWebhook{
+					URLSecret: tc.ref,
+					Headers: []checklyv1alpha1.AlertChannelKeyValue{
+						{Key: "Authorization", ValueFrom: &tc.ref},
+					},
+				},
+			},
+		}
+
+		_, err := validator.ValidateCreate(context.Background(), alertChannel)
+		if tc.valid && err != nil {
+			t.Errorf("%s: expected no error, got %s", tc.name, err)
+		}
+		if !tc.valid && err == nil {
+			t.Errorf("%s: expected error, got none", tc.name)
+		}
+	}
+}
@@ -0,0 +1,139 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package checkly
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	checklyv1alpha1 "github.com/checkly/checkly-operator/api/checkly/v1alpha1"
+)
+
+// checkFrequencies holds the check frequencies in minutes accepted by checklyhq.com
+var checkFrequencies = []int{1, 2, 5, 10, 15, 30, 60, 120, 180, 360, 720, 1440}
+
+// checklyLocations holds the public checklyhq.com locations, see https://www.checklyhq.com/docs/monitoring/global-locations/
+var checklyLocations = []string{
+	"us-east-1", "us-east-2", "us-west-1", "us-west-2", "ca-central-1", "sa-east-1",
+	"eu-west-1", "eu-central-1", "eu-west-2", "eu-west-3", "eu-north-1", "eu-south-1",
+	"me-south-1", "af-south-1",
+	"ap-east-1", "ap-south-1", "ap-southeast-1", "ap-southeast-2", "ap-southeast-3",
+	"ap-northeast-1", "ap-northeast-2", "ap-northeast-3",
+}
+
+// maxResponseTime is the highest response time in milliseconds checklyhq.com accepts for API checks
+const maxResponseTime = 30000
+
+// validateFrequency checks if the frequency is supported by checklyhq.com, 0 means the default is used
+func validateFrequency(frequency int, fldPath *field.Path) field.ErrorList {
+	if frequency == 0 || slices.Contains(checkFrequencies, frequency) {
+		return nil
+	}
+
+	supported := make([]string, len(checkFrequencies))
+	for i, f := range checkFrequencies {
+		supported[i] = strconv.Itoa(f)
+	}
+	return field.ErrorList{field.NotSupported(fldPath, frequency, supported)}
+}
+
+// validateEndpoint checks if the endpoint is an absolute http or https URL
+func validateEndpoint(endpoint string, fldPath *field.Path) field.ErrorList {
+	if endpoint == "" {
+		return field.ErrorList{field.Required(fldPath, "endpoint has to be set")}
+	}
+
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return field.ErrorList{field.Invalid(fldPath, endpoint, err.Error())}
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return field.ErrorList{field.Invalid(fldPath, endpoint, "has to be an absolute http or https URL")}
+	}
+
+	return nil
+}
+
+// validateSuccess checks if the success value is an HTTP status code
+func validateSuccess(success string, fldPath *field.Path) field.ErrorList {
+	if success == "" {
+		return nil
+	}
+
+	code, err := strconv.Atoi(success)
+	if err != nil || code < 100 || code > 599 {
+		return field.ErrorList{field.Invalid(fldPath, success, "has to be an HTTP status code between 100 and 599")}
+	}
+
+	return nil
+}
+
+// validateKeyValues checks that every header or query parameter has a key and a complete secret reference
+func validateKeyValues(kvs []checklyv1alpha1.ApiCheckKeyValue, fldPath *field.Path) (allErrs field.ErrorList) {
+	for i, kv := range kvs {
+		if kv.Key == "" {
+			allErrs = append(allErrs, field.Required(fldPath.Index(i).Child("key"), "key has to be set"))
+		}
+		if kv.ValueFrom != nil && (kv.ValueFrom.Name == "" || kv.ValueFrom.Key == "") {
+			allErrs = append(allErrs, field.Required(fldPath.Index(i).Child("valueFrom"), fmt.Sprintf("name and key of the secret have to be set for %s", kv.Key)))
+		}
+	}
+	return
+}
+
+// validateLocations checks if all locations are public checklyhq.com locations
+func validateLocations(locations []string, fldPath *field.Path) (allErrs field.ErrorList) {
+	for i, location := range locations {
+		if !slices.Contains(checklyLocations, location) {
+			allErrs = append(allErrs, field.NotSupported(fldPath.Index(i), location, checklyLocations))
+		}
+	}
+	return
+}
+
+// validateAccount checks if the referenced ChecklyAccount exists, empty means the default account of the operator is used
+func validateAccount(ctx context.Context, c client.Reader, account string, fldPath *field.Path) (allErrs field.ErrorList) {
+	if account == "" {
+		return
+	}
+
+	err := c.Get(ctx, types.NamespacedName{Name: account}, &checklyv1alpha1.ChecklyAccount{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			allErrs = append(allErrs, field.NotFound(fldPath, account))
+			return
+		}
+		allErrs = append(allErrs, field.InternalError(fldPath, err))
+	}
+	return
+}
+
+// validateAccountUnchanged rejects moving a resource to another account, the operator would lose track of the object created in the old account
+func validateAccountUnchanged(oldAccount string, account string, fldPath *field.Path) (allErrs field.ErrorList) {
+	if oldAccount != account {
+		allErrs = append(allErrs, field.Forbidden(fldPath, "the account can't be changed, recreate the resource instead"))
+	}
+	return
+}